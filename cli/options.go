@@ -66,6 +66,10 @@ type ProjectOptions struct {
 	// used if appropriate.
 	Environment types.Mapping
 
+	// EnvironmentSources tells where Environment variables are set, as recorded by WithOsEnv and WithDotEnv, see
+	// loader Options.TrackEnvironmentSources
+	EnvironmentSources map[string]types.EnvironmentSource
+
 	// EnvFiles are file paths to ".env" files with additional environment
 	// variable data.
 	//
@@ -236,6 +240,22 @@ func WithProfiles(profiles []string) ProjectOptionsFn {
 	}
 }
 
+// WithPrebuildProfiles sets profiles prebuild jobs are selected by, regardless of the profiles enabling services.
+// When not set, profiles are read from the COMPOSE_PREBUILD_PROFILES environment variable, if set
+func WithPrebuildProfiles(profiles []string) ProjectOptionsFn {
+	return func(o *ProjectOptions) error {
+		o.loadOptions = append(o.loadOptions, loader.WithPrebuildProfiles(profiles))
+		return nil
+	}
+}
+
+// WithSensitiveDisabled strips sensitive entries from services, as for local development without access to secrets.
+// Setting the COMPOSE_DISABLE_SENSITIVE environment variable to a true value has the same effect
+func WithSensitiveDisabled(o *ProjectOptions) error {
+	o.loadOptions = append(o.loadOptions, loader.WithSkipSensitive)
+	return nil
+}
+
 // WithOsEnv imports environment variables from OS
 func WithOsEnv(o *ProjectOptions) error {
 	for k, v := range utils.GetAsEqualsMap(os.Environ()) {
@@ -243,10 +263,18 @@ func WithOsEnv(o *ProjectOptions) error {
 			continue
 		}
 		o.Environment[k] = v
+		o.setEnvironmentSource(k, types.EnvironmentSource{Origin: types.EnvironmentOriginOS})
 	}
 	return nil
 }
 
+func (o *ProjectOptions) setEnvironmentSource(key string, source types.EnvironmentSource) {
+	if o.EnvironmentSources == nil {
+		o.EnvironmentSources = map[string]types.EnvironmentSource{}
+	}
+	o.EnvironmentSources[key] = source
+}
+
 // WithEnvFile sets an alternate env file.
 //
 // Deprecated: use WithEnvFiles instead.
@@ -298,10 +326,17 @@ func WithEnvFiles(file ...string) ProjectOptionsFn {
 
 // WithDotEnv imports environment variables from .env file
 func WithDotEnv(o *ProjectOptions) error {
-	envMap, err := dotenv.GetEnvFromFile(o.Environment, o.EnvFiles)
+	envMap, declarations, err := dotenv.GetEnvFromFileWithDeclarations(o.Environment, o.EnvFiles)
 	if err != nil {
 		return err
 	}
+	for k, declaration := range declarations {
+		if _, set := o.Environment[k]; !set {
+			o.setEnvironmentSource(k, types.EnvironmentSource{
+				Origin: types.EnvironmentOriginEnvFile, File: declaration.File, Line: declaration.Line,
+			})
+		}
+	}
 	o.Environment.Merge(envMap)
 	return nil
 }
@@ -453,9 +488,10 @@ func (o *ProjectOptions) LoadProject(ctx context.Context) (*types.Project, error
 	}
 
 	project, err := loader.LoadWithContext(ctx, types.ConfigDetails{
-		ConfigFiles: config.ConfigFiles,
-		WorkingDir:  config.WorkingDir,
-		Environment: o.Environment,
+		ConfigFiles:        config.ConfigFiles,
+		WorkingDir:         config.WorkingDir,
+		Environment:        o.Environment,
+		EnvironmentSources: o.EnvironmentSources,
 	}, o.loadOptions...)
 	if err != nil {
 		return nil, err
@@ -512,6 +548,7 @@ func (o *ProjectOptions) prepare(ctx context.Context) (*types.ConfigDetails, err
 	o.loadOptions = append(o.loadOptions,
 		withNamePrecedenceLoad(defaultDir, isNamed, o),
 		withConvertWindowsPaths(o),
+		withCICDEnvironment(o),
 		withListeners(o))
 
 	return configDetails, nil
@@ -551,6 +588,23 @@ func withConvertWindowsPaths(options *ProjectOptions) func(*loader.Options) {
 	}
 }
 
+// withCICDEnvironment applies the cicdez environment variables, for options not set otherwise
+func withCICDEnvironment(options *ProjectOptions) func(*loader.Options) {
+	return func(o *loader.Options) {
+		if profiles, ok := options.Environment[consts.ComposePrebuildProfiles]; ok && o.PrebuildProfiles == nil {
+			o.PrebuildProfiles = []string{}
+			for _, s := range strings.Split(profiles, ",") {
+				if s = strings.TrimSpace(s); s != "" {
+					o.PrebuildProfiles = append(o.PrebuildProfiles, s)
+				}
+			}
+		}
+		if utils.StringToBool(options.Environment[consts.ComposeDisableSensitive]) {
+			o.SkipSensitive = true
+		}
+	}
+}
+
 // save listeners from ProjectOptions (compose) to loader.Options
 func withListeners(options *ProjectOptions) func(*loader.Options) {
 	return func(opts *loader.Options) {
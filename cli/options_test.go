@@ -27,6 +27,7 @@ import (
 	"gotest.tools/v3/assert"
 
 	"github.com/compose-spec/compose-go/v2/consts"
+	"github.com/compose-spec/compose-go/v2/loader"
 	"github.com/compose-spec/compose-go/v2/utils"
 )
 
@@ -323,6 +324,58 @@ func TestProjectWithMultipleEnvFile(t *testing.T) {
 	assert.Equal(t, service.Ports[0].Published, "9000")
 }
 
+func TestProjectWithEnvironmentSources(t *testing.T) {
+	opts, err := NewProjectOptions([]string{
+		"testdata/env-file/compose-with-env-files.yaml",
+	}, WithEnvFiles("testdata/env-file/.env", "testdata/env-file/override.env"),
+		WithDotEnv,
+		WithOsEnv,
+		WithLoadOptions(func(o *loader.Options) {
+			o.TrackEnvironmentSources = true
+		}))
+	assert.NilError(t, err)
+	p, err := ProjectFromOptions(context.TODO(), opts)
+	assert.NilError(t, err)
+	override, err := filepath.Abs("testdata/env-file/override.env")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]types.EnvironmentSource{
+		"PORT": {Origin: types.EnvironmentOriginEnvFile, File: override, Line: 1},
+	}, p.EnvironmentSources)
+	assert.Equal(t, opts.EnvironmentSources["COMPOSE_PROJECT_NAME"].Line, 2)
+}
+
+func TestProjectWithCICDOptions(t *testing.T) {
+	load := func(fns ...ProjectOptionsFn) *types.Project {
+		opts, err := NewProjectOptions([]string{"testdata/cicdez/compose.yaml"}, fns...)
+		assert.NilError(t, err)
+		p, err := opts.LoadProject(context.TODO())
+		assert.NilError(t, err)
+		return p
+	}
+	jobs := func(p *types.Project) []string {
+		var names []string
+		for _, job := range p.Services["web"].Prebuild {
+			names = append(names, job.Name)
+		}
+		return names
+	}
+
+	p := load()
+	assert.DeepEqual(t, []string{"Build"}, jobs(p))
+	assert.Equal(t, len(p.Services["web"].Sensitive), 1)
+
+	p = load(WithPrebuildProfiles([]string{"release"}), WithSensitiveDisabled)
+	assert.DeepEqual(t, []string{"Build", "Publish"}, jobs(p))
+	assert.Equal(t, len(p.Services["web"].Sensitive), 0)
+
+	p = load(WithEnv([]string{"COMPOSE_PREBUILD_PROFILES=release", "COMPOSE_DISABLE_SENSITIVE=1"}))
+	assert.DeepEqual(t, []string{"Build", "Publish"}, jobs(p))
+	assert.Equal(t, len(p.Services["web"].Sensitive), 0)
+
+	p = load(WithEnv([]string{"COMPOSE_PREBUILD_PROFILES=release"}), WithPrebuildProfiles([]string{}))
+	assert.DeepEqual(t, []string{"Build"}, jobs(p))
+}
+
 func TestProjectNameFromWorkingDir(t *testing.T) {
 	opts, err := NewProjectOptions([]string{
 		"testdata/env-file/compose-with-env-file.yaml",
@@ -22,6 +22,8 @@ const (
 	ComposeFilePath              = "COMPOSE_FILE"
 	ComposeDisableDefaultEnvFile = "COMPOSE_DISABLE_ENV_FILE"
 	ComposeProfiles              = "COMPOSE_PROFILES"
+	ComposePrebuildProfiles      = "COMPOSE_PREBUILD_PROFILES"
+	ComposeDisableSensitive      = "COMPOSE_DISABLE_SENSITIVE"
 )
 
 const Extensions = "#extensions" // Using # prefix, we prevent risk to conflict with an actual yaml key
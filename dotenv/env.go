@@ -23,51 +23,69 @@ import (
 	"path/filepath"
 )
 
+// Declaration is where an env file sets a variable
+type Declaration struct {
+	File string
+	Line int
+}
+
 func GetEnvFromFile(currentEnv map[string]string, filenames []string) (map[string]string, error) {
+	envMap, _, err := GetEnvFromFileWithDeclarations(currentEnv, filenames)
+	return envMap, err
+}
+
+// GetEnvFromFileWithDeclarations is GetEnvFromFile also returning where variables are declared, by the last file
+// setting each of them
+func GetEnvFromFileWithDeclarations(currentEnv map[string]string, filenames []string) (map[string]string, map[string]Declaration, error) {
 	envMap := make(map[string]string)
+	declarations := make(map[string]Declaration)
 
 	for _, dotEnvFile := range filenames {
 		abs, err := filepath.Abs(dotEnvFile)
 		if err != nil {
-			return envMap, err
+			return envMap, declarations, err
 		}
 		dotEnvFile = abs
 
 		s, err := os.Stat(dotEnvFile)
 		if os.IsNotExist(err) {
-			return envMap, fmt.Errorf("couldn't find env file: %s", dotEnvFile)
+			return envMap, declarations, fmt.Errorf("couldn't find env file: %s", dotEnvFile)
 		}
 		if err != nil {
-			return envMap, err
+			return envMap, declarations, err
 		}
 
 		if s.IsDir() {
 			if len(filenames) == 0 {
-				return envMap, nil
+				return envMap, declarations, nil
 			}
-			return envMap, fmt.Errorf("%s is a directory", dotEnvFile)
+			return envMap, declarations, fmt.Errorf("%s is a directory", dotEnvFile)
 		}
 
 		b, err := os.ReadFile(dotEnvFile)
 		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("couldn't read env file: %s", dotEnvFile)
+			return nil, nil, fmt.Errorf("couldn't read env file: %s", dotEnvFile)
 		}
 		if err != nil {
-			return envMap, err
+			return envMap, declarations, err
 		}
 
-		err = parseWithLookup(bytes.NewReader(b), envMap, func(k string) (string, bool) {
+		lines := map[string]int{}
+		err = parseWithLines(bytes.NewReader(b), envMap, func(k string) (string, bool) {
 			v, ok := currentEnv[k]
 			if ok {
 				return v, true
 			}
 			v, ok = envMap[k]
 			return v, ok
-		})
+		}, lines)
 		if err != nil {
-			return envMap, fmt.Errorf("failed to read %s: %w", dotEnvFile, err)
+			return envMap, declarations, fmt.Errorf("failed to read %s: %w", dotEnvFile, err)
+		}
+		for key, line := range lines {
+			declarations[key] = Declaration{File: dotEnvFile, Line: line}
 		}
 	}
 
-	return envMap, nil
+	return envMap, declarations, nil
 }
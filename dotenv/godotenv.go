@@ -48,6 +48,11 @@ func ParseWithLookup(r io.Reader, lookupFn LookupFn) (map[string]string, error)
 
 // ParseWithLookup reads an env file from io.Reader, returning a map of keys and values.
 func parseWithLookup(r io.Reader, vars map[string]string, lookupFn LookupFn) error {
+	return parseWithLines(r, vars, lookupFn, nil)
+}
+
+// parseWithLines is parseWithLookup also recording into lines, when set, the line each variable is declared at
+func parseWithLines(r io.Reader, vars map[string]string, lookupFn LookupFn, lines map[string]int) error {
 	data, err := io.ReadAll(r)
 	if err != nil {
 		return err
@@ -57,7 +62,9 @@ func parseWithLookup(r io.Reader, vars map[string]string, lookupFn LookupFn) err
 	// editors tend to add it, and it'll cause parsing to fail)
 	data = bytes.TrimPrefix(data, utf8BOM)
 
-	return newParser().parse(string(data), vars, lookupFn)
+	p := newParser()
+	p.lines = lines
+	return p.parse(string(data), vars, lookupFn)
 }
 
 // Load will read your env file(s) and load them into ENV for this process.
@@ -22,6 +22,8 @@ var (
 
 type parser struct {
 	line int
+	// lines, when set, records the line each variable is declared at
+	lines map[string]int
 }
 
 func newParser() *parser {
@@ -42,6 +44,7 @@ func (p *parser) parse(src string, out map[string]string, lookupFn LookupFn) err
 			break
 		}
 
+		line := p.line
 		key, left, inherited, err := p.locateKeyName(cutset)
 		if err != nil {
 			return err
@@ -54,6 +57,7 @@ func (p *parser) parse(src string, out map[string]string, lookupFn LookupFn) err
 			value, ok := lookupFn(key)
 			if ok {
 				out[key] = value
+				p.declare(key, line)
 			}
 			cutset = left
 			continue
@@ -65,12 +69,19 @@ func (p *parser) parse(src string, out map[string]string, lookupFn LookupFn) err
 		}
 
 		out[key] = value
+		p.declare(key, line)
 		cutset = left
 	}
 
 	return nil
 }
 
+func (p *parser) declare(key string, line int) {
+	if p.lines != nil {
+		p.lines[key] = line
+	}
+}
+
 // getStatementPosition returns position of statement begin.
 //
 // It skips any comment line or non-whitespace character.
@@ -0,0 +1,250 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package edit
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/errdefs"
+	"github.com/compose-spec/compose-go/v2/types"
+	"go.yaml.in/yaml/v4"
+)
+
+// File is a compose file edited as a yaml node tree, so comments, key order and scalar styles are preserved when
+// written back. Indentation is detected from the original content, sequences being written either at the level of
+// their parent key or indented, as the first sequence of the file is
+type File struct {
+	document   *yaml.Node
+	indent     int
+	compactSeq bool
+}
+
+// Parse parses compose file content for editing. Files declaring multiple documents are not supported
+func Parse(content []byte) (*File, error) {
+	f := &File{document: &yaml.Node{Kind: yaml.DocumentNode}}
+	f.indent, f.compactSeq = detectIndent(content)
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	if err := decoder.Decode(f.document); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	var next yaml.Node
+	if err := decoder.Decode(&next); !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("editing files with multiple documents: %w", errdefs.ErrUnsupported)
+	}
+	if len(f.document.Content) == 0 {
+		f.document.Content = []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}
+	}
+	if f.document.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("top-level object must be a mapping")
+	}
+	return f, nil
+}
+
+// ReadFile reads compose file filename for editing
+func ReadFile(filename string) (*File, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(content)
+}
+
+// Bytes returns the edited compose file content
+func (f *File) Bytes() ([]byte, error) {
+	buf := bytes.NewBuffer([]byte{})
+	encoder := yaml.NewEncoder(buf)
+	encoder.SetIndent(f.indent)
+	if f.compactSeq {
+		encoder.CompactSeqIndent()
+	}
+	if err := encoder.Encode(f.document); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// WriteFile writes the edited compose file to filename, keeping its permissions when it exists
+func (f *File) WriteFile(filename string) error {
+	content, err := f.Bytes()
+	if err != nil {
+		return err
+	}
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(filename); err == nil {
+		mode = info.Mode().Perm()
+	}
+	return os.WriteFile(filename, content, mode)
+}
+
+// AddSensitive declares sensitive entry name for service, as the `x-sensitive` extension when service already uses
+// it. An entry with the same name must not be declared already
+func AddSensitive(f *File, service string, name string, entry types.SensitiveConfig) error {
+	s, err := f.service(service)
+	if err != nil {
+		return err
+	}
+	key := attributeKey(s, "sensitive", types.CICDExtensionSensitive)
+	sensitive := lookup(s, key)
+	if sensitive == nil {
+		sensitive = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		appendKeyValue(s, key, sensitive)
+	}
+	if sensitive.Kind != yaml.MappingNode {
+		return fmt.Errorf("services.%s.%s: must be a mapping: %w", service, key, errdefs.ErrInvalid)
+	}
+	if lookup(sensitive, name) != nil {
+		return fmt.Errorf("services.%s.%s.%s: entry is already declared: %w", service, key, name, errdefs.ErrInvalid)
+	}
+	value, err := encode(entry)
+	if err != nil {
+		return err
+	}
+	appendKeyValue(sensitive, name, value)
+	return nil
+}
+
+// AddPrebuildJob appends job to service prebuild jobs, as the `x-prebuild` extension when service already uses it.
+// A job with the same name must not be declared already
+func AddPrebuildJob(f *File, service string, job types.PrebuildJob) error {
+	s, err := f.service(service)
+	if err != nil {
+		return err
+	}
+	key := attributeKey(s, "prebuild", types.CICDExtensionPrebuild)
+	prebuild := lookup(s, key)
+	if prebuild == nil {
+		prebuild = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+		appendKeyValue(s, key, prebuild)
+	}
+	jobs := prebuild
+	if prebuild.Kind == yaml.MappingNode {
+		// prebuild declared as a mapping, with jobs set under `jobs`
+		if jobs = lookup(prebuild, "jobs"); jobs == nil {
+			jobs = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+			appendKeyValue(prebuild, "jobs", jobs)
+		}
+	}
+	if jobs.Kind != yaml.SequenceNode {
+		return fmt.Errorf("services.%s.%s: must be a list of jobs: %w", service, key, errdefs.ErrInvalid)
+	}
+	for _, j := range jobs.Content {
+		if n := lookup(j, "name"); n != nil && n.Value == job.Name {
+			return fmt.Errorf("services.%s.%s.%s: job is already declared: %w", service, key, job.Name, errdefs.ErrInvalid)
+		}
+	}
+	value, err := encode(job)
+	if err != nil {
+		return err
+	}
+	if len(jobs.Content) == 0 {
+		jobs.Style = 0
+	}
+	jobs.Content = append(jobs.Content, value)
+	return nil
+}
+
+func (f *File) service(name string) (*yaml.Node, error) {
+	services := lookup(f.document.Content[0], "services")
+	if services == nil || services.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("no such service: %s: %w", name, errdefs.ErrNotFound)
+	}
+	s := lookup(services, name)
+	if s == nil {
+		return nil, fmt.Errorf("no such service: %s: %w", name, errdefs.ErrNotFound)
+	}
+	if s.Kind != yaml.MappingNode {
+		// service declared as null, like `web:`
+		*s = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", HeadComment: s.HeadComment, LineComment: s.LineComment}
+	}
+	return s, nil
+}
+
+// attributeKey returns extension when service declares a cicdez attribute in its extension form, native otherwise
+func attributeKey(service *yaml.Node, native string, extension string) string {
+	if lookup(service, native) == nil && lookup(service, extension) != nil {
+		return extension
+	}
+	return native
+}
+
+// lookup returns the value of key in mapping node, or nil
+func lookup(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func appendKeyValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	if len(mapping.Content) == 0 {
+		// an empty flow mapping, like `{}`, gets block style as it is filled
+		mapping.Style = 0
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+}
+
+func encode(v any) (*yaml.Node, error) {
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return nil, err
+	}
+	return &node, nil
+}
+
+// detectIndent returns the indentation content uses, as the indentation of its first nested line, and whether its
+// first block sequence is at the level of its parent key. Defaults to 2 spaces and indented sequences
+func detectIndent(content []byte) (int, bool) {
+	indent, compactSeq, seqFound := 0, false, false
+	parent := -1
+	for _, line := range strings.Split(string(content), "\n") {
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		level := len(line) - len(trimmed)
+		if parent >= 0 {
+			if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+				if !seqFound {
+					compactSeq, seqFound = level == parent, true
+				}
+			} else if indent == 0 && level > parent {
+				indent = level - parent
+			}
+		}
+		parent = -1
+		if strings.HasSuffix(trimmed, ":") {
+			parent = level
+		}
+	}
+	if indent == 0 {
+		indent = 2
+	}
+	return indent, compactSeq
+}
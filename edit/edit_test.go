@@ -0,0 +1,133 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package edit
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/compose-spec/compose-go/v2/errdefs"
+	"github.com/compose-spec/compose-go/v2/types"
+)
+
+func TestAddSensitive(t *testing.T) {
+	f, err := Parse([]byte(`# project comment
+name: demo
+services:
+  # the web frontend
+  web:
+    image: nginx # pinned later
+    ports:
+      - "8080:80"
+  db:
+    image: postgres
+`))
+	assert.NilError(t, err)
+	err = AddSensitive(f, "web", "env", types.SensitiveConfig{
+		Target:  "/run/secrets/app.env",
+		Secrets: []types.SensitiveSecret{{Source: "api_key"}},
+	})
+	assert.NilError(t, err)
+	actual, err := f.Bytes()
+	assert.NilError(t, err)
+	assert.Equal(t, string(actual), `# project comment
+name: demo
+services:
+  # the web frontend
+  web:
+    image: nginx # pinned later
+    ports:
+      - "8080:80"
+    sensitive:
+      env:
+        target: /run/secrets/app.env
+        secrets:
+          - source: api_key
+  db:
+    image: postgres
+`)
+
+	err = AddSensitive(f, "web", "env", types.SensitiveConfig{Target: "/run/secrets/other.env"})
+	assert.ErrorContains(t, err, "services.web.sensitive.env: entry is already declared")
+	assert.ErrorIs(t, err, errdefs.ErrInvalid)
+	err = AddSensitive(f, "cache", "env", types.SensitiveConfig{})
+	assert.ErrorIs(t, err, errdefs.ErrNotFound)
+}
+
+func TestAddPrebuildJob(t *testing.T) {
+	f, err := Parse([]byte(`services:
+  web:
+    image: nginx
+    x-prebuild:
+    - name: Build # first
+      commands:
+      - name: Compile
+        command: make
+  db:
+    image: postgres
+    prebuild:
+      runs-on: alpine
+`))
+	assert.NilError(t, err)
+	test := types.PrebuildJob{Name: "Test", Commands: []types.PrebuildCommand{{Name: "Unit", Command: "make test"}}}
+	assert.NilError(t, AddPrebuildJob(f, "web", test))
+	assert.NilError(t, AddPrebuildJob(f, "db", types.PrebuildJob{Name: "Migrate"}))
+	actual, err := f.Bytes()
+	assert.NilError(t, err)
+	assert.Equal(t, string(actual), `services:
+  web:
+    image: nginx
+    x-prebuild:
+    - name: Build # first
+      commands:
+      - name: Compile
+        command: make
+    - name: Test
+      commands:
+      - name: Unit
+        command: make test
+  db:
+    image: postgres
+    prebuild:
+      runs-on: alpine
+      jobs:
+      - name: Migrate
+`)
+
+	err = AddPrebuildJob(f, "web", test)
+	assert.ErrorContains(t, err, "services.web.x-prebuild.Test: job is already declared")
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	content := `# comment
+services:
+  web:
+    image: nginx
+    command: ["run", '--verbose']
+    environment:
+      - FOO=bar # trailing
+`
+	f, err := Parse([]byte(content))
+	assert.NilError(t, err)
+	actual, err := f.Bytes()
+	assert.NilError(t, err)
+	assert.Equal(t, string(actual), content)
+
+	_, err = Parse([]byte("services: {}\n---\nservices: {}\n"))
+	assert.ErrorIs(t, err, errdefs.ErrUnsupported)
+}
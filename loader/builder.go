@@ -0,0 +1,155 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package loader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/compose-spec/compose-go/v2/errdefs"
+	"github.com/compose-spec/compose-go/v2/types"
+)
+
+// BuilderFilename is the compose file name reported for projects constructed by ProjectBuilder
+const BuilderFilename = "<project builder>"
+
+// ProjectBuilder constructs a project programmatically. Build loads it as a compose file declaring the same model
+// would be, so defaults, normalization and validation, cicdez ones included, apply the same
+type ProjectBuilder struct {
+	project     types.Project
+	environment types.Mapping
+	errs        []error
+}
+
+// NewProjectBuilder creates a ProjectBuilder for project name
+func NewProjectBuilder(name string) *ProjectBuilder {
+	return &ProjectBuilder{
+		project: types.Project{Name: name, Services: types.Services{}},
+	}
+}
+
+// WorkingDir sets the directory relative paths are resolved against
+func (b *ProjectBuilder) WorkingDir(dir string) *ProjectBuilder {
+	b.project.WorkingDir = dir
+	return b
+}
+
+// Environment sets the environment used to resolve the project, as for inline secrets or prebuild conditions
+func (b *ProjectBuilder) Environment(environment types.Mapping) *ProjectBuilder {
+	b.environment = environment
+	return b
+}
+
+// AddService adds service, replacing any service with the same name
+func (b *ProjectBuilder) AddService(service types.ServiceConfig) *ProjectBuilder {
+	if service.Name == "" {
+		b.errs = append(b.errs, fmt.Errorf("service name is required: %w", errdefs.ErrInvalid))
+		return b
+	}
+	b.project.Services[service.Name] = service
+	return b
+}
+
+// AddSecret declares top-level secret name
+func (b *ProjectBuilder) AddSecret(name string, secret types.SecretConfig) *ProjectBuilder {
+	if b.project.Secrets == nil {
+		b.project.Secrets = types.Secrets{}
+	}
+	b.project.Secrets[name] = secret
+	return b
+}
+
+// AddConfig declares top-level config name
+func (b *ProjectBuilder) AddConfig(name string, config types.ConfigObjConfig) *ProjectBuilder {
+	if b.project.Configs == nil {
+		b.project.Configs = types.Configs{}
+	}
+	b.project.Configs[name] = config
+	return b
+}
+
+// WithStages declares the order of prebuild stages
+func (b *ProjectBuilder) WithStages(stages ...string) *ProjectBuilder {
+	b.project.Stages = stages
+	return b
+}
+
+// WithFileDefaults sets the project file_defaults
+func (b *ProjectBuilder) WithFileDefaults(defaults types.FileDefaults) *ProjectBuilder {
+	b.project.FileDefaults = &defaults
+	return b
+}
+
+// WithPrebuild appends prebuild jobs to service, which must have been added
+func (b *ProjectBuilder) WithPrebuild(service string, jobs ...types.PrebuildJob) *ProjectBuilder {
+	return b.updateService(service, func(s *types.ServiceConfig) {
+		s.Prebuild = append(s.Prebuild, jobs...)
+	})
+}
+
+// WithLocalConfig sets local config name of service, which must have been added
+func (b *ProjectBuilder) WithLocalConfig(service string, name string, config types.LocalConfigConfig) *ProjectBuilder {
+	return b.updateService(service, func(s *types.ServiceConfig) {
+		if s.LocalConfigs == nil {
+			s.LocalConfigs = map[string]types.LocalConfigConfig{}
+		}
+		s.LocalConfigs[name] = config
+	})
+}
+
+// WithSensitive sets sensitive entry name of service, which must have been added
+func (b *ProjectBuilder) WithSensitive(service string, name string, config types.SensitiveConfig) *ProjectBuilder {
+	return b.updateService(service, func(s *types.ServiceConfig) {
+		if s.Sensitive == nil {
+			s.Sensitive = map[string]types.SensitiveConfig{}
+		}
+		s.Sensitive[name] = config
+	})
+}
+
+func (b *ProjectBuilder) updateService(name string, fn func(s *types.ServiceConfig)) *ProjectBuilder {
+	s, ok := b.project.Services[name]
+	if !ok {
+		b.errs = append(b.errs, fmt.Errorf("service %q is not declared: %w", name, errdefs.ErrNotFound))
+		return b
+	}
+	fn(&s)
+	b.project.Services[name] = s
+	return b
+}
+
+// Build loads the constructed project, reporting errors of the builder calls first. Values are used literally, as
+// interpolation is skipped unless options enable it back
+func (b *ProjectBuilder) Build(ctx context.Context, options ...func(*Options)) (*types.Project, error) {
+	if err := errors.Join(b.errs...); err != nil {
+		return nil, err
+	}
+	content, err := b.project.MarshalYAML()
+	if err != nil {
+		return nil, err
+	}
+	configDetails := types.ConfigDetails{
+		WorkingDir:  b.project.WorkingDir,
+		ConfigFiles: []types.ConfigFile{{Filename: BuilderFilename, Content: content}},
+		Environment: b.environment,
+	}
+	options = append([]func(*Options){func(o *Options) {
+		o.SkipInterpolation = true
+	}}, options...)
+	return LoadWithContext(ctx, configDetails, options...)
+}
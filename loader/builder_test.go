@@ -0,0 +1,69 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package loader
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/errdefs"
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestProjectBuilder(t *testing.T) {
+	project, err := NewProjectBuilder("test-builder").
+		WorkingDir(t.TempDir()).
+		Environment(types.Mapping{"API_KEY": "secret"}).
+		AddService(types.ServiceConfig{Name: "web", Image: "nginx"}).
+		AddSecret("api_key", types.SecretConfig{Environment: "API_KEY"}).
+		WithPrebuild("web", types.PrebuildJob{
+			Name:     "Build",
+			RunsOn:   "node:18",
+			Commands: []types.PrebuildCommand{{Name: "Print", Command: "echo $HOME"}},
+		}).
+		WithLocalConfig("web", "app", types.LocalConfigConfig{Content: "hello", Target: "/etc/app.conf"}).
+		WithSensitive("web", "env", types.SensitiveConfig{
+			Target:  "/run/secrets/app.env",
+			Secrets: []types.SensitiveSecret{{Source: "api_key"}},
+		}).
+		Build(context.TODO())
+	assert.NilError(t, err)
+	web := project.Services["web"]
+	assert.Check(t, is.Equal("test-builder", project.Name))
+	assert.Check(t, is.Equal("echo $HOME", web.Prebuild[0].Commands[0].Command))
+	assert.Check(t, is.Equal("0444", web.LocalConfigs["app"].Mode.String()))
+	assert.Check(t, is.Equal("0400", web.Sensitive["env"].Mode.String()))
+	assert.Check(t, is.Equal("0", web.Sensitive["env"].UID))
+}
+
+func TestProjectBuilderValidation(t *testing.T) {
+	_, err := NewProjectBuilder("test-builder").
+		AddService(types.ServiceConfig{Name: "web", Image: "nginx"}).
+		WithSensitive("db", "env", types.SensitiveConfig{Target: "/run/secrets/app.env"}).
+		Build(context.TODO())
+	assert.ErrorContains(t, err, `service "db" is not declared`)
+	assert.ErrorIs(t, err, errdefs.ErrNotFound)
+
+	_, err = NewProjectBuilder("test-builder").
+		WorkingDir(t.TempDir()).
+		AddService(types.ServiceConfig{Name: "web", Image: "nginx"}).
+		WithLocalConfig("web", "app", types.LocalConfigConfig{Content: "hello", Target: "etc/app.conf"}).
+		Build(context.TODO())
+	assert.ErrorContains(t, err, `services.web.local_configs.app: target "etc/app.conf" must be an absolute path`)
+}
@@ -0,0 +1,1853 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/errdefs"
+	"github.com/compose-spec/compose-go/v2/template"
+	"github.com/compose-spec/compose-go/v2/tree"
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/compose-spec/compose-go/v2/utils"
+	"go.yaml.in/yaml/v4"
+)
+
+// DefaultMaxCICDNestingDepth is the maximum nesting of prebuild, local_configs and sensitive attributes
+// accepted when Options.MaxCICDNestingDepth is not set
+const DefaultMaxCICDNestingDepth = 32
+
+var cicdAttributes = []string{"prebuild", "local_configs", "sensitive"}
+
+// autoIncludeCICDFiles loads, for services declared by dict, the files declaring cicdez attributes as selected
+// by include, as config files to be merged into the model
+func autoIncludeCICDFiles(dict map[string]any, workingDir string, include func(service string) (string, bool)) ([]types.ConfigFile, error) {
+	services, _ := dict["services"].(map[string]any)
+	var files []types.ConfigFile
+	for _, name := range slices.Sorted(maps.Keys(services)) {
+		file, ok := include(name)
+		if !ok {
+			continue
+		}
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(workingDir, file)
+		}
+		content, err := os.ReadFile(file)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var attrs map[string]any
+		if err := yaml.Unmarshal(content, &attrs); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		for attr := range attrs {
+			if !slices.Contains(cicdAttributes, attr) {
+				return nil, fmt.Errorf("%s: unsupported attribute %q for service %s, only %s can be declared: %w",
+					file, attr, name, strings.Join(cicdAttributes, ", "), errdefs.ErrInvalid)
+			}
+		}
+		files = append(files, types.ConfigFile{
+			Filename: file,
+			Config: map[string]any{
+				"services": map[string]any{name: attrs},
+			},
+		})
+	}
+	return files, nil
+}
+
+// checkRemoteCICDPaths rejects relative paths in cicdez attributes of a remote include, as remote sources must be
+// self-contained
+func checkRemoteCICDPaths(dict map[string]any, include []string) error {
+	services, _ := dict["services"].(map[string]any)
+	for _, name := range slices.Sorted(maps.Keys(services)) {
+		service, _ := services[name].(map[string]any)
+		configs, _ := service["local_configs"].(map[string]any)
+		for _, key := range slices.Sorted(maps.Keys(configs)) {
+			config, _ := configs[key].(map[string]any)
+			if source, ok := config["source"].(string); ok && !filepath.IsAbs(source) {
+				return fmt.Errorf("include %s: services.%s.local_configs.%s: relative source %q is not supported by remote include, use inline content: %w",
+					strings.Join(include, ","), name, key, source, errdefs.ErrInvalid)
+			}
+		}
+		sensitive, _ := service["sensitive"].(map[string]any)
+		for _, key := range slices.Sorted(maps.Keys(sensitive)) {
+			config, _ := sensitive[key].(map[string]any)
+			if tmpl, ok := config["template"].(string); ok && !filepath.IsAbs(tmpl) {
+				return fmt.Errorf("include %s: services.%s.sensitive.%s: relative template %q is not supported by remote include: %w",
+					strings.Join(include, ","), name, key, tmpl, errdefs.ErrInvalid)
+			}
+		}
+	}
+	return nil
+}
+
+// CICDValidationLevel is a tier of cicdez validations, each level including the checks of the previous one
+type CICDValidationLevel string
+
+const (
+	// CICDValidationOff skips cicdez specific checks. Schema validation is still controlled by Options.SkipValidation
+	CICDValidationOff CICDValidationLevel = "off"
+	// CICDValidationBasic checks cicdez attributes structure: nesting depth, sensitive formats and prebuild
+	// environment, on top of schema validation which enforces required attributes
+	CICDValidationBasic CICDValidationLevel = "basic"
+	// CICDValidationStandard also checks references (needs, networks, secrets, registers, stages), uniqueness of job
+	// and command names, and entries consistency. This is the default
+	CICDValidationStandard CICDValidationLevel = "standard"
+	// CICDValidationStrict also runs lints, reporting findings as errors rather than warnings
+	CICDValidationStrict CICDValidationLevel = "strict"
+)
+
+var cicdValidationLevels = []CICDValidationLevel{CICDValidationOff, CICDValidationBasic, CICDValidationStandard, CICDValidationStrict}
+
+// includes tells if level runs the checks introduced by other
+func (l CICDValidationLevel) includes(other CICDValidationLevel) bool {
+	return slices.Index(cicdValidationLevels, l) >= slices.Index(cicdValidationLevels, other)
+}
+
+func (o *Options) cicdValidationLevel() (CICDValidationLevel, error) {
+	if o.CICDValidationLevel == "" {
+		return CICDValidationStandard, nil
+	}
+	if !slices.Contains(cicdValidationLevels, o.CICDValidationLevel) {
+		return "", fmt.Errorf("unsupported cicd validation level %q: %w", o.CICDValidationLevel, errdefs.ErrInvalid)
+	}
+	return o.CICDValidationLevel, nil
+}
+
+func (o *Options) maxCICDNestingDepth() int {
+	if o.MaxCICDNestingDepth > 0 {
+		return o.MaxCICDNestingDepth
+	}
+	return DefaultMaxCICDNestingDepth
+}
+
+// checkCICDModel validates raw cicdez attributes before they get processed by generic compose model transformations
+func checkCICDModel(dict map[string]any) error {
+	if err := checkSensitiveFormats(dict); err != nil {
+		return err
+	}
+	return checkPrebuildEnvironment(dict)
+}
+
+// checkSensitiveFormats rejects unsupported sensitive formats, including formats selected by profile. Formats set
+// by a variable are checked by schema validation once interpolated
+func checkSensitiveFormats(dict map[string]any) error {
+	services, _ := dict["services"].(map[string]any)
+	for _, name := range slices.Sorted(maps.Keys(services)) {
+		service, _ := services[name].(map[string]any)
+		sensitive, _ := service["sensitive"].(map[string]any)
+		for _, key := range slices.Sorted(maps.Keys(sensitive)) {
+			config, _ := sensitive[key].(map[string]any)
+			var formats []any
+			switch f := config["format"].(type) {
+			case string:
+				formats = append(formats, f)
+			case map[string]any:
+				for _, profile := range slices.Sorted(maps.Keys(f)) {
+					formats = append(formats, f[profile])
+				}
+			}
+			for _, f := range formats {
+				format, ok := f.(string)
+				if !ok || strings.Contains(format, "$") || types.SensitiveFormat(format).IsSupported() {
+					continue
+				}
+				target, _ := config["target"].(string)
+				if target == "" {
+					target = "/run/secrets/" + key
+				}
+				return fmt.Errorf("services.%s.sensitive.%s.format: unsupported format %q for target %s, must be one of %s: %w",
+					name, key, format, target, strings.Join(sensitiveFormatNames(), ", "), errdefs.ErrInvalid)
+			}
+		}
+	}
+	return nil
+}
+
+func sensitiveFormatNames() []string {
+	names := make([]string, len(types.SensitiveFormats))
+	for i, f := range types.SensitiveFormats {
+		names[i] = string(f)
+	}
+	return names
+}
+
+// deprecatedCICDKey is a cicdez attribute renamed by replacement, still accepted under its deprecated name
+type deprecatedCICDKey struct {
+	// parent is the path pattern of the mapping holding the attribute
+	parent      tree.Path
+	deprecated  string
+	replacement string
+}
+
+var deprecatedCICDKeys = []deprecatedCICDKey{
+	{parent: "services.*.prebuild.*", deprecated: "runs_on", replacement: "runs-on"},
+}
+
+// migrateDeprecatedKeys renames deprecated cicdez attributes to their replacement, notifying onDeprecated once per
+// occurrence
+func migrateDeprecatedKeys(value any, p tree.Path, onDeprecated func(path string, replacement string)) error {
+	switch v := value.(type) {
+	case map[string]any:
+		for _, key := range deprecatedCICDKeys {
+			old, ok := v[key.deprecated]
+			if !ok || !p.Matches(key.parent) {
+				continue
+			}
+			if _, ok := v[key.replacement]; ok {
+				return fmt.Errorf("%s: %s and deprecated %s are mutually exclusive: %w", p, key.replacement, key.deprecated, errdefs.ErrInvalid)
+			}
+			v[key.replacement] = old
+			delete(v, key.deprecated)
+			onDeprecated(p.Next(key.deprecated).String(), key.replacement)
+		}
+		for _, k := range slices.Sorted(maps.Keys(v)) {
+			if err := migrateDeprecatedKeys(v[k], p.Next(k), onDeprecated); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, e := range v {
+			item, _ := e.(map[string]any)
+			if err := migrateDeprecatedKeys(e, p.Next(nameOrIndex(item, i)), onDeprecated); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+var cicdExtensions = map[string]string{
+	types.CICDExtensionPrebuild:     "prebuild",
+	types.CICDExtensionLocalConfigs: "local_configs",
+	types.CICDExtensionSensitive:    "sensitive",
+	types.CICDExtensionFileDefaults: "file_defaults",
+}
+
+var cicdProjectExtensions = map[string]string{
+	types.CICDExtensionStages:       "stages",
+	types.CICDExtensionFileDefaults: "file_defaults",
+}
+
+// CICDAttributesMode selects how native cicdez attributes are loaded, see Options.CICDAttributes
+type CICDAttributesMode string
+
+const (
+	// CICDAttributesAllowed loads native cicdez attributes, as well as their extension form. This is the default
+	CICDAttributesAllowed CICDAttributesMode = ""
+	// CICDAttributesRejected rejects native cicdez attributes, so that files are also valid for compose implementations
+	// not supporting those. Extension form, as produced by types.WithCICDAsExtensions, is still loaded
+	CICDAttributesRejected CICDAttributesMode = "rejected"
+	// CICDAttributesAsExtensions strips native cicdez attributes into their extension form, as produced by
+	// types.WithCICDAsExtensions, so that files are loaded as standard compose files
+	CICDAttributesAsExtensions CICDAttributesMode = "extensions"
+)
+
+// applyCICDAttributesMode converts cicdez attributes of a file to the form mode loads
+func applyCICDAttributesMode(dict map[string]any, mode CICDAttributesMode) error {
+	switch mode {
+	case CICDAttributesAllowed:
+		return convertCICDExtensions(dict)
+	case CICDAttributesRejected:
+		if err := rejectCICDAttributes(dict); err != nil {
+			return err
+		}
+		return convertCICDExtensions(dict)
+	case CICDAttributesAsExtensions:
+		return convertCICDAttributes(dict)
+	default:
+		return fmt.Errorf("unsupported cicd attributes mode %q: %w", mode, errdefs.ErrInvalid)
+	}
+}
+
+// convertCICDExtensions moves cicdez attributes declared as extensions, as produced by types.WithCICDAsExtensions,
+// to their native attributes
+func convertCICDExtensions(dict map[string]any) error {
+	for extension, attr := range cicdProjectExtensions {
+		if err := moveAttribute(dict, "", extension, attr); err != nil {
+			return err
+		}
+	}
+	services, _ := dict["services"].(map[string]any)
+	for name, s := range services {
+		service, _ := s.(map[string]any)
+		for extension, attr := range cicdExtensions {
+			if err := moveAttribute(service, "services."+name, extension, attr); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// convertCICDAttributes moves native cicdez attributes to their extension form, as produced by
+// types.WithCICDAsExtensions
+func convertCICDAttributes(dict map[string]any) error {
+	for extension, attr := range cicdProjectExtensions {
+		if err := moveAttribute(dict, "", attr, extension); err != nil {
+			return err
+		}
+	}
+	services, _ := dict["services"].(map[string]any)
+	for name, s := range services {
+		service, _ := s.(map[string]any)
+		for extension, attr := range cicdExtensions {
+			if err := moveAttribute(service, "services."+name, attr, extension); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// moveAttribute renames attribute from into to, both being mutually exclusive. p is the path to dict, empty for the
+// top-level
+func moveAttribute(dict map[string]any, p string, from string, to string) error {
+	v, ok := dict[from]
+	if !ok {
+		return nil
+	}
+	if _, ok := dict[to]; ok {
+		attr, extension := from, to
+		if strings.HasPrefix(from, "x-") {
+			attr, extension = to, from
+		}
+		if p == "" {
+			return fmt.Errorf("%s and %s are mutually exclusive: %w", attr, extension, errdefs.ErrInvalid)
+		}
+		return fmt.Errorf("%s: %s and %s are mutually exclusive: %w", p, attr, extension, errdefs.ErrInvalid)
+	}
+	dict[to] = v
+	delete(dict, from)
+	return nil
+}
+
+// rejectCICDAttributes reports native cicdez attributes, suggesting their extension form
+func rejectCICDAttributes(dict map[string]any) error {
+	var errs []error
+	for _, extension := range slices.Sorted(maps.Keys(cicdProjectExtensions)) {
+		attr := cicdProjectExtensions[extension]
+		if _, ok := dict[attr]; ok {
+			errs = append(errs, fmt.Errorf("%s: cicdez attribute is not allowed, use %s: %w", attr, extension, errdefs.ErrInvalid))
+		}
+	}
+	services, _ := dict["services"].(map[string]any)
+	for _, name := range slices.Sorted(maps.Keys(services)) {
+		service, _ := services[name].(map[string]any)
+		for _, extension := range slices.Sorted(maps.Keys(cicdExtensions)) {
+			attr := cicdExtensions[extension]
+			if _, ok := service[attr]; ok {
+				errs = append(errs, fmt.Errorf("services.%s.%s: cicdez attribute is not allowed, use %s: %w", name, attr, extension, errdefs.ErrInvalid))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// normalizePrebuildJobs converts the object form of prebuild, declaring jobs under a `jobs` key, to the bare list of
+// jobs. Shared `runs-on` is applied to jobs which don't set their own. An object without `jobs` is left to schema
+// validation. Commands set as a plain string are expanded to a command named after it, so files get merged the same
+// way whatever the load options
+func normalizePrebuildJobs(dict map[string]any) error {
+	services, _ := dict["services"].(map[string]any)
+	for _, name := range slices.Sorted(maps.Keys(services)) {
+		service, _ := services[name].(map[string]any)
+		prebuild, ok := service["prebuild"].(map[string]any)
+		if !ok {
+			expandShortPrebuildCommands(service["prebuild"])
+			continue
+		}
+		v, ok := prebuild["jobs"]
+		if !ok {
+			continue
+		}
+		jobs, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("services.%s.prebuild.jobs: must be a list of jobs, got %T: %w", name, v, errdefs.ErrInvalid)
+		}
+		for _, key := range slices.Sorted(maps.Keys(prebuild)) {
+			if key != "jobs" && key != "runs-on" && !strings.HasPrefix(key, "x-") {
+				return fmt.Errorf("services.%s.prebuild: unsupported key %s, jobs must be declared under jobs: %w", name, key, errdefs.ErrInvalid)
+			}
+		}
+		if runsOn, ok := prebuild["runs-on"]; ok {
+			for _, j := range jobs {
+				job, ok := j.(map[string]any)
+				if !ok {
+					continue
+				}
+				if _, ok := job["runs-on"]; !ok {
+					job["runs-on"] = runsOn
+				}
+			}
+		}
+		service["prebuild"] = jobs
+		expandShortPrebuildCommands(jobs)
+	}
+	return nil
+}
+
+func expandShortPrebuildCommands(prebuild any) {
+	jobs, _ := prebuild.([]any)
+	for _, j := range jobs {
+		job, _ := j.(map[string]any)
+		commands, _ := job["commands"].([]any)
+		for i, c := range commands {
+			if short, ok := c.(string); ok {
+				commands[i] = map[string]any{"name": short, "command": short}
+			}
+		}
+	}
+}
+
+// checkCICDNestingDepth rejects cicdez attributes nested deeper than maxDepth. It runs on documents as decoded, before
+// any recursive processing is applied, and doesn't walk deeper than maxDepth itself. Attributes are walked in sorted
+// order, so the reported path is stable when multiple branches are too deep
+func checkCICDNestingDepth(raw any, maxDepth int) error {
+	document, _ := rawMapping(raw)
+	services, _ := rawMapping(document["services"])
+	for _, name := range slices.Sorted(maps.Keys(services)) {
+		service, ok := rawMapping(services[name])
+		if !ok {
+			continue
+		}
+		for _, key := range slices.Sorted(maps.Keys(service)) {
+			if !slices.Contains(cicdAttributes, key) && !slices.Contains(cicdAttributes, cicdExtensions[key]) {
+				continue
+			}
+			if err := checkNestingDepth(service[key], tree.NewPath("services", name, key), 1, maxDepth); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkNestingDepth(value any, p tree.Path, depth int, maxDepth int) error {
+	if depth > maxDepth {
+		return fmt.Errorf("%s: exceeds maximum nesting depth of %d: %w", p, maxDepth, errdefs.ErrInvalid)
+	}
+	if list, ok := value.([]any); ok {
+		for i, e := range list {
+			if err := checkNestingDepth(e, p.Next(fmt.Sprintf("[%d]", i)), depth+1, maxDepth); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	mapping, _ := rawMapping(value)
+	for _, k := range slices.Sorted(maps.Keys(mapping)) {
+		if err := checkNestingDepth(mapping[k], p.Next(k), depth+1, maxDepth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rawMapping returns value as a mapping with string keys, as yaml decodes it either with string keys or with keys of
+// any type
+func rawMapping(value any) (map[string]any, bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		return v, true
+	case map[any]any:
+		mapping := make(map[string]any, len(v))
+		for k, e := range v {
+			mapping[fmt.Sprint(k)] = e
+		}
+		return mapping, true
+	}
+	return nil, false
+}
+
+// checkPrebuildEnvironment rejects prebuild jobs and commands environment values which are not scalars, typically an
+// accidentally nested mapping, which would otherwise get stringified
+func checkPrebuildEnvironment(dict map[string]any) error {
+	services, _ := dict["services"].(map[string]any)
+	for _, name := range slices.Sorted(maps.Keys(services)) {
+		service, _ := services[name].(map[string]any)
+		jobs, _ := service["prebuild"].([]any)
+		for i, j := range jobs {
+			job, _ := j.(map[string]any)
+			p := tree.NewPath("services", name, "prebuild", nameOrIndex(job, i))
+			if err := checkScalarEnvironment(job["environment"], p.Next("environment")); err != nil {
+				return err
+			}
+			commands, _ := job["commands"].([]any)
+			for k, c := range commands {
+				command, _ := c.(map[string]any)
+				if err := checkScalarEnvironment(command["environment"], p.Next("commands").Next(nameOrIndex(command, k)).Next("environment")); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func checkScalarEnvironment(environment any, p tree.Path) error {
+	switch env := environment.(type) {
+	case map[string]any:
+		for _, key := range slices.Sorted(maps.Keys(env)) {
+			if err := checkScalarValue(env[key], p.Next(key)); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, value := range env {
+			if err := checkScalarValue(value, p); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkPrebuildDurations rejects invalid prebuild jobs and commands durations, reporting those by name, which
+// schema validation can't do. Values left uninterpolated are checked once interpolated
+func checkPrebuildDurations(dict map[string]any) error {
+	check := func(value any, p tree.Path) error {
+		d, ok := value.(string)
+		if !ok || strings.Contains(d, "$") {
+			return nil
+		}
+		if _, err := time.ParseDuration(d); err != nil {
+			return fmt.Errorf("%s: invalid duration %q: %w", p, d, errdefs.ErrInvalid)
+		}
+		return nil
+	}
+	services, _ := dict["services"].(map[string]any)
+	for _, name := range slices.Sorted(maps.Keys(services)) {
+		service, _ := services[name].(map[string]any)
+		jobs, _ := service["prebuild"].([]any)
+		for i, j := range jobs {
+			job, _ := j.(map[string]any)
+			p := tree.NewPath("services", name, "prebuild", nameOrIndex(job, i))
+			if err := check(job["timeout"], p.Next("timeout")); err != nil {
+				return err
+			}
+			commands, _ := job["commands"].([]any)
+			for k, c := range commands {
+				command, _ := c.(map[string]any)
+				cp := p.Next("commands").Next(nameOrIndex(command, k))
+				for _, attr := range []string{"timeout", "estimated_duration"} {
+					if err := check(command[attr], cp.Next(attr)); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// nameOrIndex identifies a list item by its `name` attribute if set, by index otherwise
+func nameOrIndex(item map[string]any, index int) string {
+	if name, ok := item["name"].(string); ok && name != "" {
+		return name
+	}
+	return fmt.Sprintf("[%d]", index)
+}
+
+func checkScalarValue(value any, p tree.Path) error {
+	switch value.(type) {
+	case map[string]any:
+		return fmt.Errorf("%s: value must be a string, number or boolean, got a mapping: %w", p, errdefs.ErrInvalid)
+	case []any:
+		return fmt.Errorf("%s: value must be a string, number or boolean, got a sequence: %w", p, errdefs.ErrInvalid)
+	}
+	return nil
+}
+
+// filterPrebuildJobs removes prebuild jobs excluded by Options.PrebuildJobFilter, jobs disabled by profiles or skip
+// being set apart by Project.WithProfiles. It reports jobs needing a removed or undefined job, as those would never run
+func filterPrebuildJobs(project *types.Project, opts *Options) error {
+	var errs []error
+	for _, name := range project.ServiceNames() {
+		s := project.Services[name]
+		if len(s.Prebuild) == 0 {
+			continue
+		}
+		declared := map[string]bool{}
+		for _, job := range project.DisabledPrebuildJobs[name] {
+			declared[job.Name] = true
+		}
+		excluded := map[string]bool{}
+		var active []types.PrebuildJob
+		for _, job := range s.Prebuild {
+			declared[job.Name] = true
+			if opts.PrebuildJobFilter != nil && !opts.PrebuildJobFilter(name, job) {
+				excluded[job.Name] = true
+			} else {
+				active = append(active, job)
+			}
+		}
+		enabled := map[string]bool{}
+		for _, job := range active {
+			enabled[job.Name] = true
+		}
+		for _, job := range active {
+			for _, need := range job.NeededJobs() {
+				switch {
+				case !declared[need]:
+					errs = append(errs, fmt.Errorf("services.%s.prebuild.%s: needs undefined job %q: %w", name, job.Name, need, errdefs.ErrInvalid))
+				case excluded[need]:
+					errs = append(errs, fmt.Errorf("services.%s.prebuild.%s: needs job %q which is excluded by job filter: %w",
+						name, job.Name, need, errdefs.ErrInvalid))
+				case !enabled[need]:
+					errs = append(errs, fmt.Errorf("services.%s.prebuild.%s: needs job %q which is disabled by profiles or skip: %w",
+						name, job.Name, need, errdefs.ErrInvalid))
+				}
+			}
+		}
+		s.Prebuild = active
+		project.Services[name] = s
+	}
+	return errors.Join(errs...)
+}
+
+// checkCICDConsistency validates cicdez attributes (prebuild, local_configs, sensitive) are consistent. All
+// detected errors are reported, services being considered by name
+func checkCICDConsistency(project *types.Project, opts *Options) error {
+	var errs []error
+	if opts.RequireUniquePrebuildJobNames {
+		errs = append(errs, checkUniquePrebuildJobNames(project))
+	}
+	errs = append(errs, checkFileDefaults("file_defaults", project.FileDefaults))
+	for _, name := range project.ServiceNames() {
+		s := project.Services[name]
+		errs = append(errs, checkFileDefaults("services."+name+".file_defaults", s.FileDefaults))
+		// undefined needs are reported by filterPrebuildJobs
+		if _, err := s.PrebuildTopoSort(); err != nil && prebuildNeedsDeclared(s) {
+			errs = append(errs, err)
+		}
+		jobs := map[string]bool{}
+		for _, job := range s.Prebuild {
+			if jobs[job.Name] {
+				errs = append(errs, fmt.Errorf("services.%s.prebuild: job name %q is not unique: %w", s.Name, job.Name, errdefs.ErrInvalid))
+			}
+			jobs[job.Name] = true
+			errs = append(errs, checkPrebuildCommandNames(s.Name, job, opts.ValidatePrebuildCommandNames))
+			errs = append(errs, checkPrebuildRetryBackoff(s.Name, job))
+			errs = append(errs, checkPrebuildPlatforms(s.Name, job))
+			errs = append(errs, checkPrebuildRegisters(s.Name, job))
+			errs = append(errs, checkPrebuildSensitiveReferences(s, job))
+			if _, err := project.PrebuildRequiredServices(s.Name, job); err != nil {
+				errs = append(errs, err)
+			}
+			errs = append(errs, checkPrebuildConditions(s.Name, job, project.Environment))
+			if job.Container != nil && job.Container.Image != "" && job.RunsOn != "" && job.Container.Image != job.RunsOn {
+				errs = append(errs, fmt.Errorf("services.%s.prebuild.%s: runs-on %q conflicts with container image %q: %w",
+					s.Name, job.Name, job.RunsOn, job.Container.Image, errdefs.ErrInvalid))
+			}
+			for _, need := range job.Needs {
+				if need.Status != "" && !slices.Contains(types.PrebuildNeedStatuses, need.Status) {
+					errs = append(errs, fmt.Errorf("services.%s.prebuild.%s: needs %s with unsupported status %q, must be one of %s: %w",
+						s.Name, job.Name, need.Job, need.Status, strings.Join(types.PrebuildNeedStatuses, ", "), errdefs.ErrInvalid))
+				}
+			}
+			if _, err := job.ExpandMatrix(); err != nil {
+				errs = append(errs, fmt.Errorf("services.%s.prebuild.%s: %w", s.Name, job.Name, err))
+			}
+			if job.ImagePullPolicy != "" && !slices.Contains(types.PrebuildPullPolicies, job.ImagePullPolicy) {
+				errs = append(errs, fmt.Errorf("services.%s.prebuild.%s: unsupported image_pull_policy %q, must be one of %s: %w",
+					s.Name, job.Name, job.ImagePullPolicy, strings.Join(types.PrebuildPullPolicies, ", "), errdefs.ErrInvalid))
+			}
+			for _, pattern := range job.WhenChanged {
+				if _, err := path.Match(pattern, ""); err != nil || strings.TrimSpace(pattern) == "" {
+					errs = append(errs, fmt.Errorf("services.%s.prebuild.%s.when_changed: invalid pattern %q: %w",
+						s.Name, job.Name, pattern, errdefs.ErrInvalid))
+				}
+			}
+			if job.Runner() == types.ServicePrefix+s.Name && s.Build == nil {
+				errs = append(errs, fmt.Errorf("services.%s.prebuild.%s: runs-on refers to service %s itself, which requires a build section: %w",
+					s.Name, job.Name, s.Name, errdefs.ErrInvalid))
+			}
+			if job.Stage != "" && len(project.Stages) > 0 && !slices.Contains(project.Stages, job.Stage) {
+				errs = append(errs, fmt.Errorf("services.%s.prebuild.%s: stage %q is not declared by stages: %w",
+					s.Name, job.Name, job.Stage, errdefs.ErrInvalid))
+			}
+		}
+		for _, key := range slices.Sorted(maps.Keys(s.Sensitive)) {
+			c := s.Sensitive[key]
+			errs = append(errs, checkSensitiveAlias(s.Name, key, c))
+			errs = append(errs, checkFileMode(s.Name, "sensitive", key, c.Target, c.Mode))
+			if c.TTL != nil && *c.TTL < 0 {
+				errs = append(errs, fmt.Errorf("services.%s.sensitive.%s: ttl %s must not be negative: %w", s.Name, key, c.TTL, errdefs.ErrInvalid))
+			}
+			if c.Format == types.SensitiveFormatTemplate && c.TrailingNewline != nil {
+				errs = append(errs, fmt.Errorf("services.%s.sensitive.%s: trailing_newline is not supported by template format: %w", s.Name, key, errdefs.ErrInvalid))
+			}
+			errs = append(errs, checkSensitiveTemplateContent(s.Name, key, c, opts))
+			if c.OnChange != nil && c.OnChange.Signal != "" && c.ChangeAction() != types.SensitiveChangeSignal {
+				errs = append(errs, fmt.Errorf("services.%s.sensitive.%s.on_change: signal requires %s action: %w",
+					s.Name, key, types.SensitiveChangeSignal, errdefs.ErrInvalid))
+			}
+			for _, secret := range c.Secrets {
+				if _, ok := project.Secrets[secret.Source]; !ok {
+					errs = append(errs, fmt.Errorf("services.%s.sensitive.%s: target %s refers to undefined secret %s: %w",
+						s.Name, key, c.Target, secret.Source, errdefs.ErrInvalid))
+				}
+				if secret.Provider != "" && secret.ProviderPath == "" {
+					errs = append(errs, fmt.Errorf("services.%s.sensitive.%s: secret %s: provider %s requires a provider_path: %w",
+						s.Name, key, secret.Source, secret.Provider, errdefs.ErrInvalid))
+				}
+				v := secret.Validate
+				if v == nil {
+					continue
+				}
+				if _, err := regexp.Compile(v.Pattern); err != nil {
+					errs = append(errs, fmt.Errorf("services.%s.sensitive.%s: secret %s: invalid validate.pattern %q: %v: %w",
+						s.Name, key, secret.Source, v.Pattern, err, errdefs.ErrInvalid))
+				} else if secret.Default != nil {
+					if err := v.Check(*secret.Default); err != nil {
+						errs = append(errs, fmt.Errorf("services.%s.sensitive.%s: secret %s: default: %w", s.Name, key, secret.Source, err))
+					}
+				}
+			}
+		}
+		for _, key := range slices.Sorted(maps.Keys(s.LocalConfigs)) {
+			errs = append(errs, checkFileMode(s.Name, "local_configs", key, s.LocalConfigs[key].Target, s.LocalConfigs[key].Mode))
+			errs = append(errs, checkLocalConfigTemplate(s.Name, key, s.LocalConfigs[key]))
+			errs = append(errs, checkLocalConfigSource(project.WorkingDir, s.Name, key, s.LocalConfigs[key], opts))
+			errs = append(errs, checkLocalConfigSize(project.WorkingDir, s.Name, key, s.LocalConfigs[key], opts))
+		}
+		if !opts.AllowHomeRelativeTargets {
+			errs = append(errs, checkHomeRelativeTargets(s))
+		}
+		errs = append(errs, checkCICDTargets(s)...)
+	}
+	return errors.Join(errs...)
+}
+
+// checkUniquePrebuildJobNames rejects job names declared by more than one service, listing the declaring services
+func checkUniquePrebuildJobNames(project *types.Project) error {
+	names, ambiguous := project.PrebuildJobNames()
+	var errs []error
+	for _, name := range names {
+		if services, ok := ambiguous[name]; ok {
+			errs = append(errs, fmt.Errorf("prebuild job name %q is declared by services %s: %w", name, strings.Join(services, ", "), errdefs.ErrInvalid))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkCICDTargets validates local_configs and sensitive targets are absolute container paths, `~` prefixed targets
+// being checked by checkHomeRelativeTargets, and that no two files of a service, including configs and secrets
+// mounts, are written to the same target
+func checkCICDTargets(s types.ServiceConfig) []error {
+	var errs []error
+	targets := map[string]string{}
+	mount := func(entry string, target string) {
+		if other, ok := targets[path.Clean(target)]; ok {
+			errs = append(errs, fmt.Errorf("%s: target %q is already used by %s: %w", entry, target, other, errdefs.ErrInvalid))
+			return
+		}
+		targets[path.Clean(target)] = entry
+	}
+	for i, c := range s.Configs {
+		mount(fmt.Sprintf("services.%s.configs[%d]", s.Name, i), c.ContainerTarget())
+	}
+	for i, c := range s.Secrets {
+		mount(fmt.Sprintf("services.%s.secrets[%d]", s.Name, i), c.ContainerTarget())
+	}
+	check := func(attr string, name string, target string) {
+		entry := fmt.Sprintf("services.%s.%s.%s", s.Name, attr, name)
+		if !path.IsAbs(target) && !strings.HasPrefix(target, "~") {
+			errs = append(errs, fmt.Errorf("%s: target %q must be an absolute path: %w", entry, target, errdefs.ErrInvalid))
+			return
+		}
+		mount(entry, target)
+	}
+	for _, name := range slices.Sorted(maps.Keys(s.LocalConfigs)) {
+		check("local_configs", name, s.LocalConfigs[name].Target)
+	}
+	for _, name := range slices.Sorted(maps.Keys(s.Sensitive)) {
+		check("sensitive", name, s.Sensitive[name].Target)
+	}
+	return errs
+}
+
+// checkHomeRelativeTargets rejects `~` prefixed targets, as container runtimes won't expand those. Prebuild working
+// directories are container paths as well for jobs running in a container, while local_configs sources and
+// sensitive secrets files are host paths, resolved by the loader. All findings are reported
+func checkHomeRelativeTargets(s types.ServiceConfig) error {
+	var errs []error
+	check := func(entry string, attr string, value string) {
+		if strings.HasPrefix(value, "~") {
+			errs = append(errs, fmt.Errorf("%s: %s %q must not start with `~` as it won't be expanded inside container: %w",
+				entry, attr, value, errdefs.ErrInvalid))
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(s.LocalConfigs)) {
+		check(fmt.Sprintf("services.%s.local_configs.%s", s.Name, name), "target", s.LocalConfigs[name].Target)
+	}
+	for _, name := range slices.Sorted(maps.Keys(s.Sensitive)) {
+		check(fmt.Sprintf("services.%s.sensitive.%s", s.Name, name), "target", s.Sensitive[name].Target)
+	}
+	for _, job := range s.Prebuild {
+		if job.Runner() == "" {
+			continue
+		}
+		entry := fmt.Sprintf("services.%s.prebuild.%s", s.Name, job.Name)
+		if job.Container != nil {
+			check(entry+".container", "working_dir", job.Container.WorkingDir)
+		}
+		for _, cmd := range job.Commands {
+			check(fmt.Sprintf("%s.commands.%s", entry, cmd.Name), "working_dir", cmd.WorkingDir)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func prebuildNeedsDeclared(s types.ServiceConfig) bool {
+	for _, job := range s.Prebuild {
+		for _, need := range job.NeededJobs() {
+			if !slices.ContainsFunc(s.Prebuild, func(j types.PrebuildJob) bool { return j.Name == need }) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// lintPrebuildTimeouts reports prebuild jobs which commands would exceed job timeout. Commands of a job always run
+// sequentially, in declaration order, as only jobs can run in parallel, so their timeouts sum is the job worst case.
+// Jobs are only considered when job and all commands set a timeout. Findings are warnings unless validation is strict
+func lintPrebuildTimeouts(project *types.Project) []error {
+	var lints []error
+	for _, name := range project.ServiceNames() {
+		for _, job := range project.Services[name].Prebuild {
+			if job.Timeout == nil || len(job.Commands) == 0 {
+				continue
+			}
+			var total time.Duration
+			for _, cmd := range job.Commands {
+				if cmd.Timeout == nil {
+					total = 0
+					break
+				}
+				total += time.Duration(*cmd.Timeout)
+			}
+			if total > time.Duration(*job.Timeout) {
+				lints = append(lints, fmt.Errorf("services.%s.prebuild.%s: commands timeouts sum up to %s, exceeding job timeout of %s",
+					name, job.Name, total, job.Timeout))
+			}
+		}
+	}
+	return lints
+}
+
+// SecretPattern is a heuristic detecting secret values inlined in prebuild commands
+type SecretPattern struct {
+	// Name describes the kind of secret detected, in lint messages
+	Name   string
+	Regexp *regexp.Regexp
+	// MinEntropy, when set, is the minimum Shannon entropy in bits per character of a match for it to be reported
+	MinEntropy float64
+}
+
+// DefaultPrebuildSecretPatterns are the patterns LintPrebuildSecrets looks for unless Options.PrebuildSecretPatterns
+// is set
+var DefaultPrebuildSecretPatterns = []SecretPattern{
+	{Name: "AWS access key", Regexp: regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{Name: "private key", Regexp: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----`)},
+	{Name: "high-entropy token", Regexp: regexp.MustCompile(`[A-Za-z0-9+/_=-]{32,}`), MinEntropy: 4},
+}
+
+// lintPrebuildSecrets reports prebuild commands which, as declared, look like they contain a secret value. Messages
+// never include the matched value
+func lintPrebuildSecrets(project *types.Project, opts *Options) []error {
+	patterns := opts.PrebuildSecretPatterns
+	if patterns == nil {
+		patterns = DefaultPrebuildSecretPatterns
+	}
+	var lints []error
+	for _, name := range project.ServiceNames() {
+		for _, job := range project.Services[name].Prebuild {
+			for i, cmd := range job.Commands {
+				command := cmd.Command
+				if cmd.RawCommand != "" {
+					command = cmd.RawCommand
+				} else if cmd.OriginalCommand != "" {
+					command = cmd.OriginalCommand
+				}
+				for _, pattern := range patterns {
+					if matchesSecretPattern(command, pattern) {
+						lints = append(lints, fmt.Errorf("services.%s.prebuild.%s.commands[%d]: command %s looks like it contains a secret (%s), use a sensitive entry instead",
+							name, job.Name, i, cmd.Name, pattern.Name))
+						break
+					}
+				}
+			}
+		}
+	}
+	return lints
+}
+
+func matchesSecretPattern(command string, pattern SecretPattern) bool {
+	for _, match := range pattern.Regexp.FindAllString(command, -1) {
+		if pattern.MinEntropy <= 0 || shannonEntropy(match) >= pattern.MinEntropy {
+			return true
+		}
+	}
+	return false
+}
+
+func shannonEntropy(s string) float64 {
+	counts := map[rune]int{}
+	for _, r := range s {
+		counts[r]++
+	}
+	var entropy float64
+	n := float64(len([]rune(s)))
+	for _, c := range counts {
+		p := float64(c) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// checkPrebuildCommandNames validates command names are unique within a job, as those identify commands, and set
+// when requireNames is
+func checkPrebuildCommandNames(service string, job types.PrebuildJob, requireNames bool) error {
+	names := map[string]int{}
+	for i, cmd := range job.Commands {
+		if strings.TrimSpace(cmd.Name) == "" {
+			if requireNames {
+				return fmt.Errorf("services.%s.prebuild.%s.commands[%d]: name must not be empty: %w", service, job.Name, i, errdefs.ErrInvalid)
+			}
+			continue
+		}
+		if j, ok := names[cmd.Name]; ok {
+			return fmt.Errorf("services.%s.prebuild.%s.commands[%d]: name %q already used by commands[%d]: %w",
+				service, job.Name, i, cmd.Name, j, errdefs.ErrInvalid)
+		}
+		names[cmd.Name] = i
+	}
+	return nil
+}
+
+// checkPrebuildRetryBackoff validates retry backoff delays are positive and factor doesn't decrease delay
+func checkPrebuildRetryBackoff(service string, job types.PrebuildJob) error {
+	for i, cmd := range job.Commands {
+		b := cmd.RetryBackoff
+		if b == nil {
+			continue
+		}
+		p := fmt.Sprintf("services.%s.prebuild.%s.commands[%d].retry_backoff", service, job.Name, i)
+		if b.Initial <= 0 {
+			return fmt.Errorf("%s.initial: must be a positive duration: %w", p, errdefs.ErrInvalid)
+		}
+		if b.Max != nil && *b.Max <= 0 {
+			return fmt.Errorf("%s.max: must be a positive duration: %w", p, errdefs.ErrInvalid)
+		}
+		if b.Factor != 0 && b.Factor < 1 {
+			return fmt.Errorf("%s.factor: must be greater than or equal to 1: %w", p, errdefs.ErrInvalid)
+		}
+	}
+	return nil
+}
+
+// checkPrebuildPlatforms validates command platforms use the `os/arch[/variant]` syntax
+func checkPrebuildPlatforms(service string, job types.PrebuildJob) error {
+	for i, cmd := range job.Commands {
+		for _, platform := range cmd.Platforms {
+			if !types.PrebuildPlatformPattern.MatchString(strings.ToLower(platform)) {
+				return fmt.Errorf("services.%s.prebuild.%s.commands[%d].platforms: invalid platform %q, must be os/arch[/variant]: %w",
+					service, job.Name, i, platform, errdefs.ErrInvalid)
+			}
+		}
+	}
+	return nil
+}
+
+// checkPrebuildSensitiveReferences validates `${sensitive.<source>}` references target a secret delivered by a
+// sensitive entry of the service
+func checkPrebuildSensitiveReferences(s types.ServiceConfig, job types.PrebuildJob) error {
+	for i, cmd := range job.Commands {
+		for _, ref := range cmd.SensitiveReferences() {
+			delivered := false
+			for _, c := range s.Sensitive {
+				if slices.ContainsFunc(c.Secrets, func(secret types.SensitiveSecret) bool { return secret.Source == ref.Source }) {
+					delivered = true
+					break
+				}
+			}
+			if !delivered {
+				return fmt.Errorf("services.%s.prebuild.%s.commands[%d].environment.%s: refers to secret %q which is not delivered by a sensitive entry: %w",
+					s.Name, job.Name, i, ref.Variable, ref.Source, errdefs.ErrInvalid)
+			}
+		}
+	}
+	return nil
+}
+
+// checkPrebuildRegisters validates `${result.<register>.<field>}` references only target commands declared earlier in job
+func checkPrebuildRegisters(service string, job types.PrebuildJob) error {
+	registered := map[string]int{}
+	for i, cmd := range job.Commands {
+		for _, ref := range cmd.ResultReferences() {
+			if ref.Field != types.ResultFieldRC && ref.Field != types.ResultFieldStdout {
+				return fmt.Errorf("services.%s.prebuild.%s.commands[%d]: unsupported result field %q for register %q: %w",
+					service, job.Name, i, ref.Field, ref.Register, errdefs.ErrInvalid)
+			}
+			if _, ok := registered[ref.Register]; !ok {
+				return fmt.Errorf("services.%s.prebuild.%s.commands[%d]: refers to register %q which is not declared by a previous command: %w",
+					service, job.Name, i, ref.Register, errdefs.ErrInvalid)
+			}
+		}
+		if cmd.Register == "" {
+			continue
+		}
+		if j, ok := registered[cmd.Register]; ok {
+			return fmt.Errorf("services.%s.prebuild.%s.commands[%d]: register %q already declared by commands[%d]: %w",
+				service, job.Name, i, cmd.Register, j, errdefs.ErrInvalid)
+		}
+		registered[cmd.Register] = i
+	}
+	return nil
+}
+
+// checkPrebuildConditions validates job and command `if` expressions parse. Job ones must not refer to registers,
+// command ones must refer to registers declared by previous commands and to environment variables either set for
+// commands, required by job, or by project environment
+func checkPrebuildConditions(service string, job types.PrebuildJob, environment types.Mapping) error {
+	if job.If != "" {
+		condition, err := types.ParsePrebuildCondition(job.If)
+		if err != nil {
+			return fmt.Errorf("services.%s.prebuild.%s.if: %v: %w", service, job.Name, err, errdefs.ErrInvalid)
+		}
+		for _, operand := range []types.PrebuildOperand{condition.Left, condition.Right} {
+			if operand.Result != nil {
+				return fmt.Errorf("services.%s.prebuild.%s.if: refers to register %q, while job condition is evaluated before any command runs: %w",
+					service, job.Name, operand.Result.Register, errdefs.ErrInvalid)
+			}
+		}
+	}
+	registered := map[string]bool{}
+	for i, cmd := range job.Commands {
+		if cmd.If != "" {
+			condition, err := types.ParsePrebuildCondition(cmd.If)
+			if err != nil {
+				return fmt.Errorf("services.%s.prebuild.%s.commands[%d].if: %v: %w", service, job.Name, i, err, errdefs.ErrInvalid)
+			}
+			for _, operand := range []types.PrebuildOperand{condition.Left, condition.Right} {
+				if operand.Result != nil && !registered[operand.Result.Register] {
+					return fmt.Errorf("services.%s.prebuild.%s.commands[%d].if: refers to register %q which is not declared by a previous command: %w",
+						service, job.Name, i, operand.Result.Register, errdefs.ErrInvalid)
+				}
+				if operand.Env != "" && !definesVariable(job, cmd, environment, operand.Env) {
+					return fmt.Errorf("services.%s.prebuild.%s.commands[%d].if: refers to undefined environment variable %q: %w",
+						service, job.Name, i, operand.Env, errdefs.ErrInvalid)
+				}
+			}
+		}
+		if cmd.Register != "" {
+			registered[cmd.Register] = true
+		}
+	}
+	return nil
+}
+
+func definesVariable(job types.PrebuildJob, cmd types.PrebuildCommand, environment types.Mapping, name string) bool {
+	if _, ok := cmd.Environment[name]; ok {
+		return true
+	}
+	if job.Container != nil {
+		if _, ok := job.Container.Environment[name]; ok {
+			return true
+		}
+	}
+	if _, ok := environment[name]; ok {
+		return true
+	}
+	return slices.Contains(job.RequiresEnv, name)
+}
+
+// checkSensitiveTemplateContent validates inline template parses and only refers to secrets of the entry. Entries
+// selecting secrets by label are only checked once normalization expanded those
+func checkSensitiveTemplateContent(service string, name string, c types.SensitiveConfig, opts *Options) error {
+	if c.TemplateContent == "" {
+		return nil
+	}
+	if c.Template != "" {
+		return fmt.Errorf("services.%s.sensitive.%s: template and template_content are mutually exclusive: %w", service, name, errdefs.ErrInvalid)
+	}
+	tmpl, err := c.ParseTemplate()
+	if err != nil {
+		return fmt.Errorf("services.%s.sensitive.%s: invalid template_content: %v: %w", service, name, err, errdefs.ErrInvalid)
+	}
+	if c.FromLabel != "" && opts.SkipNormalization {
+		return nil
+	}
+	if err := c.CheckTemplatePlaceholders(tmpl); err != nil {
+		return fmt.Errorf("services.%s.sensitive.%s: %w", service, name, err)
+	}
+	return nil
+}
+
+// checkFileDefaults validates the mode of a project or service file_defaults is a file permission. p is the path to
+// the file_defaults attribute
+func checkFileDefaults(p string, defaults *types.FileDefaults) error {
+	if defaults == nil || defaults.Mode == nil || defaults.Mode.IsPermission() {
+		return nil
+	}
+	return fmt.Errorf("%s.mode: mode %s is not a valid file permission, must be within 0-0777: %w", p, defaults.Mode, errdefs.ErrInvalid)
+}
+
+// checkFileMode validates a local_configs or sensitive mode is a file permission
+func checkFileMode(service string, attr string, name string, target string, mode *types.FileMode) error {
+	if mode == nil || mode.IsPermission() {
+		return nil
+	}
+	return fmt.Errorf("services.%s.%s.%s: mode %s for target %s is not a valid file permission, must be within 0-0777: %w",
+		service, attr, name, mode, target, errdefs.ErrInvalid)
+}
+
+// checkLocalConfigTemplate validates template engine only applies to inline content, which must parse as a template
+func checkLocalConfigTemplate(service string, name string, c types.LocalConfigConfig) error {
+	if c.TemplateEngine == "" {
+		return nil
+	}
+	if c.Content == "" {
+		return fmt.Errorf("services.%s.local_configs.%s: template_engine requires inline content: %w", service, name, errdefs.ErrInvalid)
+	}
+	if _, err := c.ContentTemplate(); err != nil {
+		return fmt.Errorf("services.%s.local_configs.%s: invalid content template: %v: %w", service, name, err, errdefs.ErrInvalid)
+	}
+	return nil
+}
+
+// checkLocalConfigSource validates a local config source is confined to the project directory, and exists with
+// Options.RequireLocalConfigSources set
+func checkLocalConfigSource(workingDir string, service string, name string, c types.LocalConfigConfig, opts *Options) error {
+	if c.Source == "" {
+		return nil
+	}
+	source := c.Source
+	if filepath.IsAbs(source) {
+		if !opts.AllowAbsoluteLocalConfigSources {
+			return fmt.Errorf("services.%s.local_configs.%s: source %q must be relative to the project directory: %w",
+				service, name, c.Source, errdefs.ErrInvalid)
+		}
+	} else {
+		source = filepath.Join(workingDir, source)
+	}
+	rel, err := filepath.Rel(workingDir, source)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("services.%s.local_configs.%s: source %q is outside of the project directory: %w",
+			service, name, c.Source, errdefs.ErrInvalid)
+	}
+	if opts.RequireLocalConfigSources && opts.ResolvePaths && !hasGlobMeta(source) {
+		if _, err := os.Stat(source); err != nil {
+			return fmt.Errorf("services.%s.local_configs.%s: source %q: %w", service, name, c.Source, err)
+		}
+	}
+	return nil
+}
+
+// checkLocalConfigSize validates a local config doesn't exceed max_size. Source file is only checked with
+// Options.CheckLocalConfigSizes set, as this requires paths to be resolved
+func checkLocalConfigSize(workingDir string, service string, name string, c types.LocalConfigConfig, opts *Options) error {
+	if c.MaxSize == nil {
+		return nil
+	}
+	size := int64(len(c.Content))
+	if c.Source != "" {
+		if !opts.CheckLocalConfigSizes || !opts.ResolvePaths {
+			return nil
+		}
+		source := c.Source
+		if !filepath.IsAbs(source) {
+			source = filepath.Join(workingDir, source)
+		}
+		fi, err := os.Stat(source)
+		if err != nil {
+			return fmt.Errorf("services.%s.local_configs.%s: %w", service, name, err)
+		}
+		size = fi.Size()
+	}
+	if size > int64(*c.MaxSize) {
+		return fmt.Errorf("services.%s.local_configs.%s: size of %d bytes exceeds max_size of %d bytes: %w",
+			service, name, size, *c.MaxSize, errdefs.ErrInvalid)
+	}
+	return nil
+}
+
+// checkSensitiveAlias validates aliases only rename secrets listed by the sensitive entry
+func checkSensitiveAlias(service string, name string, c types.SensitiveConfig) error {
+	for source := range c.Alias {
+		if !slices.ContainsFunc(c.Secrets, func(s types.SensitiveSecret) bool { return s.Source == source }) {
+			return fmt.Errorf("services.%s.sensitive.%s: alias refers to secret %q which is not listed by secrets: %w",
+				service, name, source, errdefs.ErrInvalid)
+		}
+	}
+	return nil
+}
+
+// defaultSensitiveFormat is the format selected by a profile-keyed sensitive format without default
+const defaultSensitiveFormat = types.SensitiveFormatEnv
+
+// resolveSensitiveFormats selects sensitive formats declared by profile, by first active profile with a format
+// set, then `default` key, then defaultSensitiveFormat. Keys must be declared profiles
+func resolveSensitiveFormats(dict map[string]any, profiles []string) error {
+	services, _ := dict["services"].(map[string]any)
+	declared := utils.Set[string]{}
+	for _, s := range services {
+		service, _ := s.(map[string]any)
+		for _, p := range asStrings(service["profiles"]) {
+			declared.Add(p)
+		}
+	}
+	for name, s := range services {
+		service, _ := s.(map[string]any)
+		sensitive, _ := service["sensitive"].(map[string]any)
+		for key, c := range sensitive {
+			config, _ := c.(map[string]any)
+			formats, ok := config["format"].(map[string]any)
+			if !ok {
+				continue
+			}
+			for _, profile := range slices.Sorted(maps.Keys(formats)) {
+				if profile != "default" && !declared.Has(profile) {
+					return fmt.Errorf("services.%s.sensitive.%s.format: %q is not a declared profile: %w", name, key, profile, errdefs.ErrInvalid)
+				}
+			}
+			format, ok := formats["default"]
+			if !ok {
+				format = string(defaultSensitiveFormat)
+			}
+			for _, profile := range profiles {
+				if f, ok := formats[profile]; ok {
+					format = f
+					break
+				}
+			}
+			config["format"] = format
+		}
+	}
+	return nil
+}
+
+func asStrings(value any) []string {
+	items, _ := value.([]any)
+	var strs []string
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
+
+// rawCommandExtension holds the raw prebuild command while model is processed, see recordRawPrebuildCommands
+const rawCommandExtension = "x-cicdez-raw-command"
+
+// recordRawPrebuildCommands records, before interpolation, raw prebuild commands and whether those contain variables.
+// Raw command is escaped so interpolation restores it as-is. Every command gets recorded, so overrides can't
+// inherit a stale record.
+func recordRawPrebuildCommands(dict map[string]any) {
+	services, _ := dict["services"].(map[string]any)
+	for _, s := range services {
+		service, _ := s.(map[string]any)
+		jobs, _ := service["prebuild"].([]any)
+		for _, j := range jobs {
+			job, _ := j.(map[string]any)
+			commands, _ := job["commands"].([]any)
+			for _, c := range commands {
+				command, ok := c.(map[string]any)
+				if !ok {
+					continue
+				}
+				raw, ok := command["command"].(string)
+				if !ok {
+					continue
+				}
+				command[rawCommandExtension] = map[string]any{
+					"raw":          strings.ReplaceAll(raw, "$", "$$"),
+					"interpolated": len(template.ExtractVariables(map[string]any{"command": raw}, nil)) > 0,
+				}
+			}
+		}
+	}
+}
+
+// rebaseLocalConfigSources makes relative local_configs sources of services loaded from an extended or included file
+// relative to the extending or including one, dir being the loaded file directory relative to it. Sources are
+// otherwise kept as declared
+func rebaseLocalConfigSources(services map[string]any, dir string) {
+	for _, s := range services {
+		service, _ := s.(map[string]any)
+		configs, _ := service["local_configs"].(map[string]any)
+		for _, c := range configs {
+			config, _ := c.(map[string]any)
+			if source, ok := config["source"].(string); ok && !filepath.IsAbs(source) {
+				config["source"] = filepath.Join(dir, source)
+			}
+		}
+	}
+}
+
+// stripSensitive removes sensitive entries from dict services, as native attributes or extensions
+func stripSensitive(dict map[string]any) {
+	services, _ := dict["services"].(map[string]any)
+	for _, s := range services {
+		if service, ok := s.(map[string]any); ok {
+			delete(service, "sensitive")
+			delete(service, types.CICDExtensionSensitive)
+		}
+	}
+}
+
+// declareSensitiveInlineSecrets declares as top-level secrets the sensitive secrets defined inline by `environment` or
+// `file`. A secret already declared, at top-level or by another entry, must be defined the same way
+func declareSensitiveInlineSecrets(dict map[string]any) error {
+	services, _ := dict["services"].(map[string]any)
+	declared, _ := dict["secrets"].(map[string]any)
+	for _, name := range slices.Sorted(maps.Keys(services)) {
+		service, _ := services[name].(map[string]any)
+		sensitive, _ := service["sensitive"].(map[string]any)
+		for _, key := range slices.Sorted(maps.Keys(sensitive)) {
+			config, _ := sensitive[key].(map[string]any)
+			secrets, _ := config["secrets"].([]any)
+			for i, s := range secrets {
+				secret, _ := s.(map[string]any)
+				environment, hasEnvironment := secret["environment"].(string)
+				file, hasFile := secret["file"].(string)
+				source, _ := secret["source"].(string)
+				switch {
+				case !hasEnvironment && !hasFile:
+					continue
+				case hasEnvironment && hasFile:
+					return fmt.Errorf("services.%s.sensitive.%s.secrets[%d]: file|environment attributes are mutually exclusive: %w",
+						name, key, i, errdefs.ErrInvalid)
+				}
+				attr, value := "environment", environment
+				if hasFile {
+					attr, value = "file", file
+				}
+				if existing, ok := declared[source].(map[string]any); ok {
+					if other, _ := existing[attr].(string); other == "" || filepath.Clean(other) != filepath.Clean(value) {
+						return fmt.Errorf("services.%s.sensitive.%s.secrets[%d]: secret %s %s %q conflicts with its declaration as secrets.%s: %w",
+							name, key, i, source, attr, value, source, errdefs.ErrInvalid)
+					}
+					continue
+				}
+				if declared == nil {
+					declared = map[string]any{}
+					dict["secrets"] = declared
+				}
+				declared[source] = map[string]any{attr: value}
+			}
+		}
+	}
+	return nil
+}
+
+// escapeLocalConfigsContent escapes, before interpolation, inline local_configs content not opting in with
+// `interpolate`, so literal `$` are preserved
+func escapeLocalConfigsContent(dict map[string]any) {
+	services, _ := dict["services"].(map[string]any)
+	for _, s := range services {
+		service, _ := s.(map[string]any)
+		configs, _ := service["local_configs"].(map[string]any)
+		for _, c := range configs {
+			config, _ := c.(map[string]any)
+			content, ok := config["content"].(string)
+			if !ok {
+				continue
+			}
+			var interpolate bool
+			switch v := config["interpolate"].(type) {
+			case bool:
+				interpolate = v
+			case string:
+				interpolate, _ = strconv.ParseBool(v)
+			}
+			if !interpolate {
+				config["content"] = strings.ReplaceAll(content, "$", "$$")
+			}
+		}
+	}
+}
+
+// restoreRawPrebuildCommands sets prebuild commands RawCommand and WasInterpolated from recordRawPrebuildCommands
+// records, which get removed from extensions
+func restoreRawPrebuildCommands(project *types.Project) {
+	for name, s := range project.Services {
+		for _, job := range s.Prebuild {
+			for j, cmd := range job.Commands {
+				if record, ok := cmd.Extensions[rawCommandExtension].(map[string]any); ok {
+					if raw, ok := record["raw"].(string); ok && raw != cmd.Command {
+						cmd.RawCommand = raw
+					}
+					cmd.WasInterpolated, _ = record["interpolated"].(bool)
+				}
+				delete(cmd.Extensions, rawCommandExtension)
+				if len(cmd.Extensions) == 0 {
+					cmd.Extensions = nil
+				}
+				job.Commands[j] = cmd
+			}
+		}
+		project.Services[name] = s
+	}
+}
+
+// resolveInheritedPrebuild copies prebuild jobs from the service set by `inherit_prebuild`, rewriting `runs-on`
+// references to the base service so they target the inheriting one. Local jobs override inherited ones by name
+func resolveInheritedPrebuild(project *types.Project) error {
+	resolved := map[string][]types.PrebuildJob{}
+	var resolve func(name string, chain []string) ([]types.PrebuildJob, error)
+	resolve = func(name string, chain []string) ([]types.PrebuildJob, error) {
+		if jobs, ok := resolved[name]; ok {
+			return jobs, nil
+		}
+		s := project.Services[name]
+		if s.InheritPrebuild == "" {
+			return s.Prebuild, nil
+		}
+		if s.InheritPrebuild == name {
+			return nil, fmt.Errorf("services.%s.inherit_prebuild: service can't inherit from itself: %w", name, errdefs.ErrInvalid)
+		}
+		if _, ok := project.Services[s.InheritPrebuild]; !ok {
+			return nil, fmt.Errorf("services.%s.inherit_prebuild: undefined service %s: %w", name, s.InheritPrebuild, errdefs.ErrInvalid)
+		}
+		chain = append(chain, name)
+		if slices.Contains(chain, s.InheritPrebuild) {
+			return nil, fmt.Errorf("services.%s.inherit_prebuild: cycle detected: %s -> %s: %w",
+				name, strings.Join(chain, " -> "), s.InheritPrebuild, errdefs.ErrInvalid)
+		}
+		base, err := resolve(s.InheritPrebuild, chain)
+		if err != nil {
+			return nil, err
+		}
+		var jobs []types.PrebuildJob
+		for _, job := range base {
+			if slices.ContainsFunc(s.Prebuild, func(j types.PrebuildJob) bool { return j.Name == job.Name }) {
+				continue
+			}
+			inherited := job.DeepCopy()
+			if inherited.RunsOn == types.ServicePrefix+s.InheritPrebuild {
+				inherited.RunsOn = types.ServicePrefix + name
+			}
+			jobs = append(jobs, inherited)
+		}
+		jobs = append(jobs, s.Prebuild...)
+		resolved[name] = jobs
+		return jobs, nil
+	}
+
+	for _, name := range project.ServiceNames() {
+		jobs, err := resolve(name, nil)
+		if err != nil {
+			return err
+		}
+		s := project.Services[name]
+		s.Prebuild = jobs
+		project.Services[name] = s
+	}
+	return nil
+}
+
+// expandPrebuildMatrix replaces jobs declaring a matrix by their expanded jobs, see types.PrebuildJob.ExpandMatrix.
+// Jobs needing an expanded job need all its expanded jobs
+func expandPrebuildMatrix(project *types.Project) error {
+	for name, s := range project.Services {
+		var jobs []types.PrebuildJob
+		expanded := map[string][]string{}
+		for _, job := range s.Prebuild {
+			matrix, err := job.ExpandMatrix()
+			if err != nil {
+				return fmt.Errorf("services.%s.prebuild.%s: %w", name, job.Name, err)
+			}
+			if len(job.Matrix) > 0 {
+				for _, j := range matrix {
+					expanded[job.Name] = append(expanded[job.Name], j.Name)
+				}
+			}
+			jobs = append(jobs, matrix...)
+		}
+		if len(expanded) == 0 {
+			continue
+		}
+		for i, job := range jobs {
+			var needs []types.PrebuildNeed
+			for _, need := range job.Needs {
+				names, ok := expanded[need.Job]
+				if !ok {
+					needs = append(needs, need)
+					continue
+				}
+				for _, n := range names {
+					needs = append(needs, types.PrebuildNeed{Job: n, Status: need.Status, Extensions: need.Extensions})
+				}
+			}
+			jobs[i].Needs = needs
+		}
+		s.Prebuild = jobs
+		project.Services[name] = s
+	}
+	return nil
+}
+
+// resolveLocalConfigSources sets local_configs ResolvedSource, relative sources being resolved against project working
+// directory as build contexts and bind mounts are. Source is kept as declared, for display
+func resolveLocalConfigSources(project *types.Project) {
+	for name, s := range project.Services {
+		for key, c := range s.LocalConfigs {
+			if c.Source == "" {
+				continue
+			}
+			c.ResolvedSource = c.Source
+			if !filepath.IsAbs(c.Source) {
+				c.ResolvedSource = filepath.Join(project.WorkingDir, c.Source)
+			}
+			s.LocalConfigs[key] = c
+		}
+		project.Services[name] = s
+	}
+}
+
+// expandLocalConfigSources expands local_configs with a glob or directory source into an entry per matched file,
+// named `<name>/<file>` and targeting the file path relative to the glob base directory, or to the source
+// directory, joined to the original target. A literal file source is left as-is
+func expandLocalConfigSources(project *types.Project) error {
+	for name, s := range project.Services {
+		expanded := map[string]types.LocalConfigConfig{}
+		for key, c := range s.LocalConfigs {
+			if c.Source == "" {
+				expanded[key] = c
+				continue
+			}
+			source := c.Source
+			if !filepath.IsAbs(source) {
+				source = filepath.Join(project.WorkingDir, source)
+			}
+			var base string
+			var files []string
+			if hasGlobMeta(source) {
+				matches, err := filepath.Glob(source)
+				if err != nil {
+					return fmt.Errorf("services.%s.local_configs.%s: invalid source pattern %q: %v: %w", name, key, c.Source, err, errdefs.ErrInvalid)
+				}
+				for _, match := range matches {
+					if fi, err := os.Stat(match); err == nil && fi.Mode().IsRegular() {
+						files = append(files, match)
+					}
+				}
+				if len(files) == 0 {
+					return fmt.Errorf("services.%s.local_configs.%s: source %q doesn't match any file: %w", name, key, c.Source, errdefs.ErrInvalid)
+				}
+				base = source
+				for hasGlobMeta(base) {
+					base = filepath.Dir(base)
+				}
+			} else if fi, err := os.Stat(source); err == nil && fi.IsDir() {
+				err := filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+					if err == nil && d.Type().IsRegular() {
+						files = append(files, path)
+					}
+					return err
+				})
+				if err != nil {
+					return fmt.Errorf("services.%s.local_configs.%s: %w", name, key, err)
+				}
+				if len(files) == 0 {
+					return fmt.Errorf("services.%s.local_configs.%s: source directory %q is empty: %w", name, key, c.Source, errdefs.ErrInvalid)
+				}
+				base = source
+			} else {
+				expanded[key] = c
+				continue
+			}
+			for _, file := range files {
+				rel, err := filepath.Rel(base, file)
+				if err != nil {
+					return err
+				}
+				entry := c
+				entry.Source = file
+				if !filepath.IsAbs(c.Source) {
+					if entry.Source, err = filepath.Rel(project.WorkingDir, file); err != nil {
+						return err
+					}
+				}
+				entry.Target = path.Join(c.Target, filepath.ToSlash(rel))
+				expanded[key+"/"+filepath.ToSlash(rel)] = entry
+			}
+		}
+		if s.LocalConfigs != nil {
+			s.LocalConfigs = expanded
+			project.Services[name] = s
+		}
+	}
+	return nil
+}
+
+func hasGlobMeta(path string) bool {
+	return strings.ContainsAny(path, `*?[`)
+}
+
+// UserMapExtension is the project extension mapping user names to numeric ids, for local_configs and sensitive
+// uid and gid to refer to
+const UserMapExtension = "x-user-map"
+
+// resolveUserNames sets local_configs and sensitive ResolvedUID and ResolvedGID, uid and gid being either numeric or
+// a name declared by x-user-map
+func resolveUserNames(project *types.Project) error {
+	users := map[string]int{}
+	declared, _ := project.Extensions[UserMapExtension].(map[string]any)
+	for name, value := range declared {
+		id, err := strconv.Atoi(fmt.Sprint(value))
+		if err != nil || id < 0 {
+			return fmt.Errorf("%s.%s: %v is not a valid user id: %w", UserMapExtension, name, value, errdefs.ErrInvalid)
+		}
+		users[name] = id
+	}
+	resolve := func(id string) (int, error) {
+		if id == "" {
+			return 0, nil
+		}
+		if n, err := strconv.Atoi(id); err == nil {
+			return n, nil
+		}
+		n, ok := users[id]
+		if !ok {
+			return 0, fmt.Errorf("user %q is not declared by %s: %w", id, UserMapExtension, errdefs.ErrInvalid)
+		}
+		return n, nil
+	}
+	for name, s := range project.Services {
+		for key, c := range s.LocalConfigs {
+			var err error
+			if c.ResolvedUID, err = resolve(c.UID); err != nil {
+				return fmt.Errorf("services.%s.local_configs.%s.uid: %w", name, key, err)
+			}
+			if c.ResolvedGID, err = resolve(c.GID); err != nil {
+				return fmt.Errorf("services.%s.local_configs.%s.gid: %w", name, key, err)
+			}
+			s.LocalConfigs[key] = c
+		}
+		for key, c := range s.Sensitive {
+			var err error
+			if c.ResolvedUID, err = resolve(c.UID); err != nil {
+				return fmt.Errorf("services.%s.sensitive.%s.uid: %w", name, key, err)
+			}
+			if c.ResolvedGID, err = resolve(c.GID); err != nil {
+				return fmt.Errorf("services.%s.sensitive.%s.gid: %w", name, key, err)
+			}
+			s.Sensitive[key] = c
+		}
+		project.Services[name] = s
+	}
+	return nil
+}
+
+// wrapPrebuildCommands applies wrapper to all prebuild commands, keeping track of the original command
+func wrapPrebuildCommands(project *types.Project, wrapper func(cmd string) string) {
+	for name, s := range project.Services {
+		for i, job := range s.Prebuild {
+			for j, cmd := range job.Commands {
+				if cmd.OriginalCommand == "" {
+					cmd.OriginalCommand = cmd.Command
+				}
+				cmd.Command = wrapper(cmd.OriginalCommand)
+				job.Commands[j] = cmd
+			}
+			s.Prebuild[i] = job
+		}
+		project.Services[name] = s
+	}
+}
+
+// applyFileDefaults sets uid, gid and mode of local_configs and sensitive entries not setting those from the service
+// file_defaults, then the project ones
+func applyFileDefaults(project *types.Project) {
+	for name, s := range project.Services {
+		var defaults []*types.FileDefaults
+		for _, d := range []*types.FileDefaults{s.FileDefaults, project.FileDefaults} {
+			if d != nil {
+				defaults = append(defaults, d)
+			}
+		}
+		if len(defaults) == 0 {
+			continue
+		}
+		apply := func(uid, gid *string, mode **types.FileMode) {
+			for _, d := range defaults {
+				if *uid == "" {
+					*uid = d.UID
+				}
+				if *gid == "" {
+					*gid = d.GID
+				}
+				if *mode == nil && d.Mode != nil {
+					m := *d.Mode
+					*mode = &m
+				}
+			}
+		}
+		for key, c := range s.LocalConfigs {
+			apply(&c.UID, &c.GID, &c.Mode)
+			s.LocalConfigs[key] = c
+		}
+		for key, c := range s.Sensitive {
+			apply(&c.UID, &c.GID, &c.Mode)
+			s.Sensitive[key] = c
+		}
+		project.Services[name] = s
+	}
+}
+
+// evaluatePrebuildConditions sets SkipReason of jobs which `if` condition evaluates false against project environment,
+// and of jobs needing those. Commands `if` conditions only comparing literals, as when they used interpolated
+// variables, are evaluated as well. Conditions which don't parse are left for checkPrebuildConditions to report
+func evaluatePrebuildConditions(project *types.Project) {
+	for name, s := range project.Services {
+		for i, job := range s.Prebuild {
+			if job.If != "" {
+				condition, err := types.ParsePrebuildCondition(job.If)
+				if err == nil && condition.Left.Result == nil && condition.Right.Result == nil {
+					if ok, _ := condition.Evaluate(nil, project.Environment); !ok {
+						job.SkipReason = fmt.Sprintf("if %q is false", job.If)
+					}
+				}
+			}
+			for j, cmd := range job.Commands {
+				if cmd.If == "" {
+					continue
+				}
+				condition, err := types.ParsePrebuildCondition(cmd.If)
+				if err != nil || !condition.Literal() {
+					continue
+				}
+				if ok, _ := condition.Evaluate(nil, nil); !ok {
+					job.Commands[j].SkipReason = fmt.Sprintf("if %q is false", cmd.If)
+				}
+			}
+			s.Prebuild[i] = job
+		}
+		skipped := map[string]bool{}
+		for _, job := range s.Prebuild {
+			skipped[job.Name] = job.SkipReason != ""
+		}
+		for changed := true; changed; {
+			changed = false
+			for i, job := range s.Prebuild {
+				if skipped[job.Name] {
+					continue
+				}
+				for _, need := range job.NeededJobs() {
+					if skipped[need] {
+						s.Prebuild[i].SkipReason = fmt.Sprintf("needs skipped job %q", need)
+						skipped[job.Name], changed = true, true
+						break
+					}
+				}
+			}
+		}
+		project.Services[name] = s
+	}
+}
+
+// locatePrebuildCommands sets Location of prebuild commands, once inherited and matrix jobs got expanded
+func locatePrebuildCommands(project *types.Project) {
+	for name, s := range project.Services {
+		for _, job := range s.Prebuild {
+			for j := range job.Commands {
+				job.Commands[j].Location = types.PrebuildCommandLocation{Service: name, Job: job.Name, Index: j}
+			}
+		}
+	}
+}
+
+// setCICDFileDefaults sets mode, uid and gid of local_configs and sensitive files not setting those, so consumers
+// don't have to know about defaults: DefaultCICDFileMode for local_configs, DefaultSensitiveFileMode for sensitive,
+// and root ownership
+func setCICDFileDefaults(project *types.Project) {
+	idOrRoot := func(id string) string {
+		if id == "" {
+			return "0"
+		}
+		return id
+	}
+	for name, s := range project.Services {
+		for key, c := range s.LocalConfigs {
+			if c.Mode == nil {
+				mode := types.FileMode(types.DefaultCICDFileMode)
+				c.Mode = &mode
+			}
+			c.UID, c.GID = idOrRoot(c.UID), idOrRoot(c.GID)
+			s.LocalConfigs[key] = c
+		}
+		for key, c := range s.Sensitive {
+			if c.Mode == nil {
+				mode := types.FileMode(types.DefaultSensitiveFileMode)
+				c.Mode = &mode
+			}
+			c.UID, c.GID = idOrRoot(c.UID), idOrRoot(c.GID)
+			s.Sensitive[key] = c
+		}
+		project.Services[name] = s
+	}
+}
+
+// resolveSensitiveFromLabel expands sensitive `from_label` selectors into the matching top-level secrets
+func resolveSensitiveFromLabel(project *types.Project) error {
+	for name, s := range project.Services {
+		for key, c := range s.Sensitive {
+			if c.FromLabel == "" {
+				continue
+			}
+			matched := false
+			for _, secret := range project.SecretNames() {
+				if !project.Secrets[secret].Labels.MatchesLabel(c.FromLabel) {
+					continue
+				}
+				matched = true
+				if !slices.ContainsFunc(c.Secrets, func(s types.SensitiveSecret) bool { return s.Source == secret }) {
+					c.Secrets = append(c.Secrets, types.SensitiveSecret{Source: secret})
+				}
+			}
+			if !matched {
+				return fmt.Errorf("services.%s.sensitive.%s: from_label %q doesn't match any secret: %w",
+					name, key, c.FromLabel, errdefs.ErrInvalid)
+			}
+			s.Sensitive[key] = c
+		}
+		project.Services[name] = s
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Diagnostic locates a cicdez validation error in the compose file declaring the attribute it is about, see
+// Options.Diagnostics
+type Diagnostic struct {
+	// Path is the attribute error is about, prebuild jobs and commands being designated by name when they have one,
+	// like services.web.prebuild.Build.commands.0
+	Path   string
+	File   string
+	Line   int
+	Column int
+	Err    error
+}
+
+func (d *Diagnostic) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", d.File, d.Line, d.Column, d.Err)
+}
+
+func (d *Diagnostic) Unwrap() error {
+	return d.Err
+}
+
+// Diagnostics returns the diagnostics err reports, in order
+func Diagnostics(err error) []*Diagnostic {
+	var diagnostics []*Diagnostic
+	switch e := err.(type) {
+	case *Diagnostic:
+		return []*Diagnostic{e}
+	case interface{ Unwrap() []error }:
+		for _, err := range e.Unwrap() {
+			diagnostics = append(diagnostics, Diagnostics(err)...)
+		}
+	case interface{ Unwrap() error }:
+		diagnostics = Diagnostics(e.Unwrap())
+	}
+	return diagnostics
+}
+
+type nodePosition struct {
+	line   int
+	column int
+}
+
+type sourcePosition struct {
+	file string
+	nodePosition
+}
+
+// sourceMap is the position of attributes in compose files by path. When an attribute is declared by multiple files,
+// the last one is recorded, as it overrides the others
+type sourceMap map[string]sourcePosition
+
+func (m sourceMap) record(file string, positions map[string]nodePosition) {
+	for path, position := range positions {
+		m[path] = sourcePosition{file: file, nodePosition: position}
+	}
+}
+
+var errorPathIndex = regexp.MustCompile(`\[(\d+)]`)
+
+// locate wraps the errors err joins as Diagnostic, when they start with the path of an attribute declared by a
+// compose file. The closest declared parent attribute is used when the attribute itself is not declared, as for
+// defaulted values
+func (m sourceMap) locate(err error) error {
+	if err == nil || len(m) == 0 {
+		return err
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var errs []error
+		for _, e := range joined.Unwrap() {
+			errs = append(errs, m.locate(e))
+		}
+		return errors.Join(errs...)
+	}
+	prefix, _, ok := strings.Cut(err.Error(), ": ")
+	if !ok || strings.ContainsAny(prefix, " \t") {
+		return err
+	}
+	path := errorPathIndex.ReplaceAllString(prefix, ".$1")
+	for p := path; p != ""; {
+		if position, ok := m[p]; ok {
+			return &Diagnostic{Path: path, File: position.file, Line: position.line, Column: position.column, Err: err}
+		}
+		i := strings.LastIndex(p, ".")
+		if i < 0 {
+			break
+		}
+		p = p[:i]
+	}
+	return err
+}
@@ -18,10 +18,45 @@ package loader
 
 import (
 	"fmt"
+	"maps"
+	"sync"
 
 	"github.com/compose-spec/compose-go/v2/types"
 )
 
+// environmentRecorder records the variables interpolation looks up, see Options.TrackEnvironmentSources. As files
+// are interpolated concurrently, it is safe for concurrent use
+type environmentRecorder struct {
+	mu      sync.Mutex
+	known   map[string]types.EnvironmentSource
+	sources map[string]types.EnvironmentSource
+}
+
+func newEnvironmentRecorder(known map[string]types.EnvironmentSource) *environmentRecorder {
+	return &environmentRecorder{known: known, sources: map[string]types.EnvironmentSource{}}
+}
+
+// wrap returns lookup recording the variables it resolves
+func (r *environmentRecorder) wrap(lookup func(key string) (string, bool)) func(key string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := lookup(key)
+		source := types.EnvironmentSource{Origin: types.EnvironmentOriginDefault}
+		if ok {
+			source = r.known[key]
+		}
+		r.mu.Lock()
+		r.sources[key] = source
+		r.mu.Unlock()
+		return value, ok
+	}
+}
+
+func (r *environmentRecorder) recorded() map[string]types.EnvironmentSource {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return maps.Clone(r.sources)
+}
+
 // ResolveEnvironment update the environment variables for the format {- VAR} (without interpolation)
 func ResolveEnvironment(dict map[string]any, environment types.Mapping) {
 	resolveServicesEnvironment(dict, environment)
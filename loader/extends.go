@@ -195,6 +195,7 @@ func getExtendsBaseFromFile(
 		if err != nil {
 			return nil, nil, err
 		}
+		rebaseLocalConfigSources(services, relworkingdir)
 
 		return services, processor, nil
 	}
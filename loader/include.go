@@ -66,11 +66,15 @@ func ApplyInclude(ctx context.Context, workingDir string, environment types.Mapp
 		}
 
 		var relworkingdir string
+		var remote bool
 		for i, p := range r.Path {
 			for _, loader := range options.ResourceLoaders {
 				if !loader.Accept(p) {
 					continue
 				}
+				if _, ok := loader.(localResourceLoader); !ok {
+					remote = true
+				}
 				path, err := loader.Load(ctx, p)
 				if err != nil {
 					return err
@@ -155,6 +159,13 @@ func ApplyInclude(ctx context.Context, workingDir string, environment types.Mapp
 		if err != nil {
 			return err
 		}
+		if remote {
+			if err := checkRemoteCICDPaths(imported, r.Path); err != nil {
+				return err
+			}
+		} else if services, ok := imported["services"].(map[string]any); ok {
+			rebaseLocalConfigSources(services, relworkingdir)
+		}
 		err = importResources(imported, model, processor)
 		if err != nil {
 			return err
@@ -29,6 +29,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/compose-spec/compose-go/v2/consts"
 	"github.com/compose-spec/compose-go/v2/errdefs"
@@ -68,7 +69,7 @@ type Options struct {
 	SkipResolveEnvironment bool
 	// SkipDefaultValues will ignore missing required attributes
 	SkipDefaultValues bool
-	// Interpolation options
+	// Interpolation options. As config files are interpolated concurrently, LookupValue must be safe for concurrent use
 	Interpolate *interp.Options
 	// Discard 'env_file' entries after resolving to 'environment' section
 	discardEnvFiles bool
@@ -84,6 +85,78 @@ type Options struct {
 	KnownExtensions map[string]any
 	// Metada for telemetry
 	Listeners []Listener
+	// MaxCICDNestingDepth limits nesting of prebuild, local_configs and sensitive attributes. Defaults to DefaultMaxCICDNestingDepth
+	MaxCICDNestingDepth int
+	// AllowHomeRelativeTargets accepts local_configs and sensitive targets, and prebuild container working directories,
+	// starting with `~`, for runtimes expanding those
+	AllowHomeRelativeTargets bool
+	// AllowAbsoluteLocalConfigSources accepts absolute local_configs sources, as long as those are inside the
+	// project directory. Otherwise, sources must be relative to the project directory
+	AllowAbsoluteLocalConfigSources bool
+	// CheckLocalConfigSizes stats local_configs sources declaring max_size when resolving paths, to reject
+	// those exceeding it. Inline content is always checked
+	CheckLocalConfigSizes bool
+	// RequireLocalConfigSources rejects local_configs sources which don't exist when resolving paths
+	RequireLocalConfigSources bool
+	// OnDeprecated is notified for every deprecated cicdez attribute, with the replacement it got migrated to.
+	// Deprecated attributes are logged as warnings when not set
+	OnDeprecated func(path string, replacement string)
+	// LintPrebuildTimeouts warns about prebuild jobs which commands timeouts sum up to more than job timeout
+	LintPrebuildTimeouts bool
+	// LintPrebuildSecrets warns about prebuild commands which look like they inline a secret value, matching
+	// PrebuildSecretPatterns
+	LintPrebuildSecrets bool
+	// PrebuildSecretPatterns are the patterns LintPrebuildSecrets looks for. Defaults to DefaultPrebuildSecretPatterns
+	PrebuildSecretPatterns []SecretPattern
+	// CICDValidationLevel selects which cicdez validations run, see CICDValidationStandard for the default. Individual
+	// toggles still apply within the selected level
+	CICDValidationLevel CICDValidationLevel
+	// ResolveUserNames resolves local_configs and sensitive uid and gid during normalization into ResolvedUID and
+	// ResolvedGID, names being looked up in the project `x-user-map` extension
+	ResolveUserNames bool
+	// ValidatePrebuildCommandNames rejects prebuild commands with a blank name, see WithPrebuildCommandNameValidation.
+	// Command names are always required to be unique within a job
+	ValidatePrebuildCommandNames bool
+	// PrebuildCommandWrapper decorates every prebuild command during normalization, original command being
+	// preserved as PrebuildCommand.OriginalCommand
+	PrebuildCommandWrapper func(cmd string) string
+	// RequireUniquePrebuildJobNames rejects prebuild job names declared by more than one service, for runners keying
+	// jobs by name only
+	RequireUniquePrebuildJobNames bool
+	// PrebuildJobFilter optionally selects the prebuild jobs to load, jobs it returns false for being removed once
+	// profiles are applied. Jobs needing a removed job are reported, unless consistency checks are skipped
+	PrebuildJobFilter func(service string, job types.PrebuildJob) bool
+	// PostValidate are custom rules invoked after built-in validation. Errors are reported along with built-in
+	// validation errors and abort the load
+	PostValidate []func(*types.Project) error
+	// AutoIncludeCICD optionally returns, for a service, the path to a file declaring its prebuild, local_configs
+	// and sensitive attributes, to be merged into service definition. Relative paths are resolved from project
+	// directory, and missing files are ignored
+	AutoIncludeCICD func(service string) (path string, ok bool)
+	// CICDAttributes selects how native cicdez attributes are loaded, see WithoutCICDAttributes
+	CICDAttributes CICDAttributesMode
+	// Diagnostics records attributes position in compose files, for cicdez validation errors to be reported as
+	// Diagnostic locating the attribute they are about
+	Diagnostics bool
+	// TrackEnvironmentSources records the variables interpolation resolves, with their source as set by
+	// ConfigDetails.EnvironmentSources, into Project.EnvironmentSources. Variables of included projects environment
+	// are not recorded
+	TrackEnvironmentSources bool
+	// PrebuildProfiles are the profiles prebuild jobs are selected by, instead of Profiles, see
+	// types.Project.WithPrebuildProfiles
+	PrebuildProfiles []string
+	// SkipSensitive strips sensitive entries from services, as for local development without access to secrets.
+	// Secrets sensitive entries declare inline are not declared either
+	SkipSensitive bool
+	// ServicesOnly restricts loading to the given services and those they refer to, as by extends or depends_on,
+	// other services being neither interpolated, validated nor normalized, see WithServicesOnly
+	ServicesOnly []string
+	// sources are the attributes position recorded for Diagnostics
+	sources sourceMap
+	// environment records variables for TrackEnvironmentSources
+	environment *environmentRecorder
+	// selectedServices are the services loaded for ServicesOnly, others being skipped as compose files are parsed
+	selectedServices map[string]bool
 }
 
 var versionWarning []string
@@ -171,22 +244,47 @@ func (l localResourceLoader) isDir(path string) bool {
 
 func (o *Options) clone() *Options {
 	return &Options{
-		SkipValidation:             o.SkipValidation,
-		SkipInterpolation:          o.SkipInterpolation,
-		SkipNormalization:          o.SkipNormalization,
-		ResolvePaths:               o.ResolvePaths,
-		ConvertWindowsPaths:        o.ConvertWindowsPaths,
-		SkipConsistencyCheck:       o.SkipConsistencyCheck,
-		SkipExtends:                o.SkipExtends,
-		SkipInclude:                o.SkipInclude,
-		Interpolate:                o.Interpolate,
-		discardEnvFiles:            o.discardEnvFiles,
-		projectName:                o.projectName,
-		projectNameImperativelySet: o.projectNameImperativelySet,
-		Profiles:                   o.Profiles,
-		ResourceLoaders:            o.ResourceLoaders,
-		KnownExtensions:            o.KnownExtensions,
-		Listeners:                  o.Listeners,
+		SkipValidation:                  o.SkipValidation,
+		SkipInterpolation:               o.SkipInterpolation,
+		SkipNormalization:               o.SkipNormalization,
+		ResolvePaths:                    o.ResolvePaths,
+		ConvertWindowsPaths:             o.ConvertWindowsPaths,
+		SkipConsistencyCheck:            o.SkipConsistencyCheck,
+		SkipExtends:                     o.SkipExtends,
+		SkipInclude:                     o.SkipInclude,
+		Interpolate:                     o.Interpolate,
+		discardEnvFiles:                 o.discardEnvFiles,
+		projectName:                     o.projectName,
+		projectNameImperativelySet:      o.projectNameImperativelySet,
+		Profiles:                        o.Profiles,
+		ResourceLoaders:                 o.ResourceLoaders,
+		KnownExtensions:                 o.KnownExtensions,
+		Listeners:                       o.Listeners,
+		MaxCICDNestingDepth:             o.MaxCICDNestingDepth,
+		AllowHomeRelativeTargets:        o.AllowHomeRelativeTargets,
+		AllowAbsoluteLocalConfigSources: o.AllowAbsoluteLocalConfigSources,
+		CheckLocalConfigSizes:           o.CheckLocalConfigSizes,
+		RequireLocalConfigSources:       o.RequireLocalConfigSources,
+		LintPrebuildTimeouts:            o.LintPrebuildTimeouts,
+		CICDValidationLevel:             o.CICDValidationLevel,
+		LintPrebuildSecrets:             o.LintPrebuildSecrets,
+		PrebuildSecretPatterns:          o.PrebuildSecretPatterns,
+		OnDeprecated:                    o.OnDeprecated,
+		ResolveUserNames:                o.ResolveUserNames,
+		PrebuildCommandWrapper:          o.PrebuildCommandWrapper,
+		ValidatePrebuildCommandNames:    o.ValidatePrebuildCommandNames,
+		PrebuildJobFilter:               o.PrebuildJobFilter,
+		RequireUniquePrebuildJobNames:   o.RequireUniquePrebuildJobNames,
+		PostValidate:                    slices.Clone(o.PostValidate),
+		AutoIncludeCICD:                 o.AutoIncludeCICD,
+		CICDAttributes:                  o.CICDAttributes,
+		Diagnostics:                     o.Diagnostics,
+		sources:                         o.sources,
+		TrackEnvironmentSources:         o.TrackEnvironmentSources,
+		environment:                     o.environment,
+		ServicesOnly:                    o.ServicesOnly,
+		PrebuildProfiles:                o.PrebuildProfiles,
+		SkipSensitive:                   o.SkipSensitive,
 	}
 }
 
@@ -257,6 +355,38 @@ func WithProfiles(profiles []string) func(*Options) {
 	}
 }
 
+// WithPrebuildProfiles sets profiles prebuild jobs are selected by, regardless of the profiles enabling services
+func WithPrebuildProfiles(profiles []string) func(*Options) {
+	return func(opts *Options) {
+		opts.PrebuildProfiles = profiles
+	}
+}
+
+// WithSkipSensitive sets the Options to strip sensitive entries from services
+func WithSkipSensitive(opts *Options) {
+	opts.SkipSensitive = true
+}
+
+// WithPrebuildCommandNameValidation sets the Options to reject prebuild commands with a blank name, which older
+// compose files may declare
+func WithPrebuildCommandNameValidation(enabled bool) func(*Options) {
+	return func(opts *Options) {
+		opts.ValidatePrebuildCommandNames = enabled
+	}
+}
+
+// WithoutCICDAttributes sets the Options to load files as standard compose files, native cicdez attributes being
+// rejected, or converted to their extension form when convert is set. See CICDAttributesRejected and
+// CICDAttributesAsExtensions
+func WithoutCICDAttributes(convert bool) func(*Options) {
+	return func(opts *Options) {
+		opts.CICDAttributes = CICDAttributesRejected
+		if convert {
+			opts.CICDAttributes = CICDAttributesAsExtensions
+		}
+	}
+}
+
 // PostProcessor is used to tweak compose model based on metadata extracted during yaml Unmarshal phase
 // that hardly can be implemented using go-yaml and mapstructure
 type PostProcessor interface {
@@ -364,6 +494,15 @@ func ToOptions(configDetails *types.ConfigDetails, options []func(*Options)) *Op
 		op(opts)
 	}
 	opts.ResourceLoaders = append(opts.ResourceLoaders, localResourceLoader{configDetails.WorkingDir})
+	if opts.Diagnostics {
+		opts.sources = sourceMap{}
+	}
+	if opts.TrackEnvironmentSources && opts.Interpolate != nil {
+		opts.environment = newEnvironmentRecorder(configDetails.EnvironmentSources)
+		interpolate := *opts.Interpolate
+		interpolate.LookupValue = opts.environment.wrap(interpolate.LookupValue)
+		opts.Interpolate = &interpolate
+	}
 	return opts
 }
 
@@ -374,11 +513,40 @@ func loadYamlModel(ctx context.Context, config types.ConfigDetails, opts *Option
 	)
 	workingDir, environment := config.WorkingDir, config.Environment
 
-	for _, file := range config.ConfigFiles {
-		dict, _, err = loadYamlFile(ctx, file, opts, workingDir, environment, ct, dict, included)
+	// files are parsed and interpolated concurrently, only merge, which depends on order, is sequential
+	documents, err := parseYamlFiles(config.ConfigFiles, opts)
+	if err != nil {
+		return nil, err
+	}
+	for i, file := range config.ConfigFiles {
+		dict, _, err = mergeYamlFile(ctx, file, documents[i], opts, workingDir, environment, ct, dict, included)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.AutoIncludeCICD != nil {
+		files, err := autoIncludeCICDFiles(dict, workingDir, opts.AutoIncludeCICD)
 		if err != nil {
 			return nil, err
 		}
+		documents, err := parseYamlFiles(files, opts)
+		if err != nil {
+			return nil, err
+		}
+		for i, file := range files {
+			dict, _, err = mergeYamlFile(ctx, file, documents[i], opts, workingDir, environment, ct, dict, included)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if opts.SkipSensitive {
+		stripSensitive(dict)
+	}
+	if err := declareSensitiveInlineSecrets(dict); err != nil {
+		return nil, err
 	}
 
 	if !opts.SkipDefaultValues {
@@ -409,6 +577,16 @@ func loadYamlModel(ctx context.Context, config types.ConfigDetails, opts *Option
 	return dict, nil
 }
 
+// yamlDocument is a compose file document, as prepared by parseYamlFile to be merged into the model
+type yamlDocument struct {
+	model     map[string]any
+	processor PostProcessor
+	// deprecated are the deprecated attributes which got migrated, notified once document is merged
+	deprecated [][2]string
+	// positions are the document attributes position, recorded for Options.Diagnostics
+	positions map[string]nodePosition
+}
+
 func loadYamlFile(ctx context.Context,
 	file types.ConfigFile,
 	opts *Options,
@@ -418,38 +596,193 @@ func loadYamlFile(ctx context.Context,
 	dict map[string]interface{},
 	included []string,
 ) (map[string]interface{}, PostProcessor, error) {
-	ctx = context.WithValue(ctx, consts.ComposeFileKey{}, file.Filename)
+	documents, err := parseYamlFile(file, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return mergeYamlFile(ctx, file, documents, opts, workingDir, environment, ct, dict, included)
+}
+
+// parseYamlFiles parses files concurrently, see parseYamlFile. Documents are returned in files order and, when
+// parsing of some files failed, the error of the first one is reported
+func parseYamlFiles(files []types.ConfigFile, opts *Options) ([][]yamlDocument, error) {
+	documents := make([][]yamlDocument, len(files))
+	errs := make([]error, len(files))
+	var wg sync.WaitGroup
+	for i, file := range files {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			documents[i], errs[i] = parseYamlFile(file, opts)
+		}()
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return documents, nil
+}
+
+// parseYamlFile reads and decodes file documents, converting cicdez attributes, checking them and interpolating
+// each document. This doesn't depend on the model documents are merged into, so files can be parsed concurrently
+func parseYamlFile(file types.ConfigFile, opts *Options) ([]yamlDocument, error) {
 	if file.Content == nil && file.Config == nil {
 		content, err := os.ReadFile(file.Filename)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 		file.Content = content
 	}
 
-	processRawYaml := func(raw interface{}, processor PostProcessor) error {
+	prepareRawYaml := func(raw interface{}, processor *ResetProcessor) (yamlDocument, error) {
+		doc := yamlDocument{}
+		invalid := func(err error) error {
+			return fmt.Errorf("validating %s: %w", file.Filename, err)
+		}
+		if processor != nil {
+			doc.processor = processor
+			if processor.positions != nil {
+				sources := sourceMap{}
+				sources.record(file.Filename, processor.positions)
+				doc.positions = processor.positions
+				invalid = func(err error) error {
+					if located := sources.locate(err); located != err {
+						return located
+					}
+					return fmt.Errorf("validating %s: %w", file.Filename, err)
+				}
+			}
+		}
+		level, err := opts.cicdValidationLevel()
+		if err != nil {
+			return doc, err
+		}
+		// nesting is limited before the document gets walked recursively
+		if level.includes(CICDValidationBasic) {
+			if err := checkCICDNestingDepth(raw, opts.maxCICDNestingDepth()); err != nil {
+				return doc, invalid(err)
+			}
+		}
 		converted, err := convertToStringKeysRecursive(raw, "")
 		if err != nil {
-			return err
+			return doc, err
 		}
 		cfg, ok := converted.(map[string]interface{})
 		if !ok {
-			return errors.New("top-level object must be a mapping")
+			return doc, errors.New("top-level object must be a mapping")
+		}
+		if opts.selectedServices != nil {
+			pruneServices(cfg, opts.selectedServices)
+		}
+
+		if err := applyCICDAttributesMode(cfg, opts.CICDAttributes); err != nil {
+			return doc, invalid(err)
+		}
+		if err := normalizePrebuildJobs(cfg); err != nil {
+			return doc, invalid(err)
+		}
+		err = migrateDeprecatedKeys(cfg, tree.NewPath(), func(path string, replacement string) {
+			doc.deprecated = append(doc.deprecated, [2]string{path, replacement})
+		})
+		if err != nil {
+			return doc, invalid(err)
+		}
+		if level.includes(CICDValidationBasic) {
+			if err := checkCICDModel(cfg); err != nil {
+				return doc, invalid(err)
+			}
 		}
 
 		if opts.Interpolate != nil && !opts.SkipInterpolation {
+			recordRawPrebuildCommands(cfg)
+			escapeLocalConfigsContent(cfg)
 			cfg, err = interp.Interpolate(cfg, *opts.Interpolate)
 			if err != nil {
-				return err
+				return doc, err
+			}
+		}
+		if level.includes(CICDValidationBasic) {
+			if err := checkPrebuildDurations(cfg); err != nil {
+				return doc, invalid(err)
 			}
 		}
 
 		fixEmptyNotNull(cfg)
+		doc.model = cfg
+		return doc, nil
+	}
+
+	if file.Config != nil {
+		doc, err := prepareRawYaml(file.Config, nil)
+		if err != nil {
+			return nil, err
+		}
+		return []yamlDocument{doc}, nil
+	}
+
+	var documents []yamlDocument
+	decoder := yaml.NewDecoder(bytes.NewReader(file.Content))
+	for {
+		var raw interface{}
+		reset := &ResetProcessor{target: &raw}
+		if opts.Diagnostics {
+			reset.positions = map[string]nodePosition{}
+		}
+		err := decoder.Decode(reset)
+		if err != nil && errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file.Filename, err)
+		}
+		doc, err := prepareRawYaml(raw, reset)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, doc)
+	}
+	return documents, nil
+}
+
+// mergeYamlFile merges file documents, as prepared by parseYamlFile, into dict, applying includes and extends. It
+// returns the post processor of the last document, nil when file is set as a Config
+func mergeYamlFile(ctx context.Context,
+	file types.ConfigFile,
+	documents []yamlDocument,
+	opts *Options,
+	workingDir string,
+	environment types.Mapping,
+	ct *cycleTracker,
+	dict map[string]interface{},
+	included []string,
+) (map[string]interface{}, PostProcessor, error) {
+	ctx = context.WithValue(ctx, consts.ComposeFileKey{}, file.Filename)
+
+	onDeprecated := opts.OnDeprecated
+	if onDeprecated == nil {
+		onDeprecated = func(path string, replacement string) {
+			logrus.Warnf("%s is deprecated, please use %s", path, replacement)
+		}
+	}
+
+	mergeDocument := func(doc yamlDocument) error {
+		for _, d := range doc.deprecated {
+			onDeprecated(d[0], d[1])
+		}
+		if opts.sources != nil {
+			opts.sources.record(file.Filename, doc.positions)
+		}
+		cfg, processor := doc.model, doc.processor
+		if processor == nil {
+			processor = NoopPostProcessor{}
+		}
 
 		// Process includes first so that extended services have all merged attributes
 		if !opts.SkipInclude {
 			included = append(included, file.Filename)
-			err = ApplyInclude(ctx, workingDir, environment, cfg, opts, included, processor)
+			err := ApplyInclude(ctx, workingDir, environment, cfg, opts, included, processor)
 			if err != nil {
 				return err
 			}
@@ -461,13 +794,14 @@ func loadYamlFile(ctx context.Context,
 
 		// Process extends after includes so base services are fully merged
 		if !opts.SkipExtends {
-			err = ApplyExtends(ctx, cfg, opts, ct, processor)
+			err := ApplyExtends(ctx, cfg, opts, ct, processor)
 			if err != nil {
 				return err
 			}
 
 		}
 
+		var err error
 		dict, err = override.Merge(dict, cfg)
 		if err != nil {
 			return err
@@ -501,28 +835,11 @@ func loadYamlFile(ctx context.Context,
 	}
 
 	var processor PostProcessor
-	if file.Config == nil {
-		r := bytes.NewReader(file.Content)
-		decoder := yaml.NewDecoder(r)
-		for {
-			var raw interface{}
-			reset := &ResetProcessor{target: &raw}
-			err := decoder.Decode(reset)
-			if err != nil && errors.Is(err, io.EOF) {
-				break
-			}
-			if err != nil {
-				return nil, nil, fmt.Errorf("failed to parse %s: %w", file.Filename, err)
-			}
-			processor = reset
-			if err := processRawYaml(raw, processor); err != nil {
-				return nil, nil, err
-			}
-		}
-	} else {
-		if err := processRawYaml(file.Config, NoopPostProcessor{}); err != nil {
+	for _, doc := range documents {
+		if err := mergeDocument(doc); err != nil {
 			return nil, nil, err
 		}
+		processor = doc.processor
 	}
 	return dict, processor, nil
 }
@@ -536,10 +853,24 @@ func load(ctx context.Context, configDetails types.ConfigDetails, opts *Options,
 		}
 	}
 
+	if len(opts.ServicesOnly) > 0 {
+		configDetails.ConfigFiles = slices.Clone(configDetails.ConfigFiles)
+		selected, err := selectServices(configDetails.ConfigFiles, opts.ServicesOnly)
+		if err != nil {
+			return nil, err
+		}
+		opts.selectedServices = selected
+	}
+
 	dict, err := loadYamlModel(ctx, configDetails, opts, &cycleTracker{}, nil)
 	if err != nil {
 		return nil, err
 	}
+	if len(opts.ServicesOnly) > 0 {
+		if err := filterServices(dict, opts.ServicesOnly); err != nil {
+			return nil, err
+		}
+	}
 
 	if len(dict) == 0 {
 		return nil, errors.New("empty compose file")
@@ -567,6 +898,9 @@ func ModelToProject(dict map[string]interface{}, opts *Options, configDetails ty
 		WorkingDir:  configDetails.WorkingDir,
 		Environment: configDetails.Environment,
 	}
+	if opts.environment != nil {
+		project.EnvironmentSources = opts.environment.recorded()
+	}
 	delete(dict, "name") // project name set by yaml must be identified by caller as opts.projectName
 
 	var err error
@@ -575,10 +909,46 @@ func ModelToProject(dict map[string]interface{}, opts *Options, configDetails ty
 		return nil, err
 	}
 
+	err = resolveSensitiveFormats(dict, opts.Profiles)
+	if err != nil {
+		return nil, err
+	}
+
 	err = Transform(dict, project)
 	if err != nil {
 		return nil, err
 	}
+	restoreRawPrebuildCommands(project)
+
+	if !opts.SkipNormalization {
+		if err := resolveInheritedPrebuild(project); err != nil {
+			return nil, err
+		}
+		if err := expandPrebuildMatrix(project); err != nil {
+			return nil, err
+		}
+		if err := expandLocalConfigSources(project); err != nil {
+			return nil, err
+		}
+		applyFileDefaults(project)
+		evaluatePrebuildConditions(project)
+		locatePrebuildCommands(project)
+		if opts.ResolveUserNames {
+			if err := resolveUserNames(project); err != nil {
+				return nil, err
+			}
+		}
+		if opts.PrebuildCommandWrapper != nil {
+			wrapPrebuildCommands(project, opts.PrebuildCommandWrapper)
+		}
+		if err := resolveSensitiveFromLabel(project); err != nil {
+			return nil, err
+		}
+		setCICDFileDefaults(project)
+	}
+	if opts.ResolvePaths {
+		resolveLocalConfigSources(project)
+	}
 
 	if opts.ConvertWindowsPaths {
 		for i, service := range project.Services {
@@ -589,15 +959,42 @@ func ModelToProject(dict map[string]interface{}, opts *Options, configDetails ty
 		}
 	}
 
+	project.PrebuildProfiles = opts.PrebuildProfiles
 	if project, err = project.WithProfiles(opts.Profiles); err != nil {
 		return nil, err
 	}
+	needsErr := filterPrebuildJobs(project, opts)
 
+	// report cicdez and custom rules errors along with compose ones, so user gets a complete list of issues
+	var errs []error
 	if !opts.SkipConsistencyCheck {
-		err := checkConsistency(project)
-		if err != nil {
-			return nil, err
+		errs = append(errs, checkConsistency(project))
+		level, _ := opts.cicdValidationLevel()
+		if level.includes(CICDValidationStandard) {
+			errs = append(errs, opts.sources.locate(needsErr), opts.sources.locate(checkCICDConsistency(project, opts)))
 		}
+		if level.includes(CICDValidationStrict) {
+			for _, lint := range append(lintPrebuildTimeouts(project), lintPrebuildSecrets(project, opts)...) {
+				errs = append(errs, opts.sources.locate(fmt.Errorf("%v: %w", lint, errdefs.ErrInvalid)))
+			}
+		} else {
+			var lints []error
+			if opts.LintPrebuildTimeouts {
+				lints = append(lints, lintPrebuildTimeouts(project)...)
+			}
+			if opts.LintPrebuildSecrets {
+				lints = append(lints, lintPrebuildSecrets(project, opts)...)
+			}
+			for _, lint := range lints {
+				logrus.Warn(lint)
+			}
+		}
+	}
+	for _, validate := range opts.PostValidate {
+		errs = append(errs, validate(project))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
 	}
 
 	if !opts.SkipResolveEnvironment {
@@ -31,6 +31,7 @@ import (
 	"gotest.tools/v3/assert"
 	is "gotest.tools/v3/assert/cmp"
 
+	"github.com/compose-spec/compose-go/v2/errdefs"
 	"github.com/compose-spec/compose-go/v2/types"
 )
 
@@ -2882,3 +2883,107 @@ services:
 	assert.NilError(t, err)
 	assert.Equal(t, p.Name, "test-with-empty-file")
 }
+
+func TestLoadManyFilesInOrder(t *testing.T) {
+	yamls := []string{"name: test-many-files\n"}
+	for i := range 40 {
+		yamls = append(yamls, fmt.Sprintf(`
+services:
+  web:
+    image: nginx:%d
+    environment:
+      FRAGMENT_%d: ${VALUE}
+  svc%d:
+    image: busybox
+`, i, i, i))
+	}
+	p, err := LoadWithContext(context.TODO(), buildConfigDetailsMultipleFiles(map[string]string{"VALUE": "set"}, yamls...))
+	assert.NilError(t, err)
+	assert.Equal(t, len(p.Services), 41)
+	web := p.Services["web"]
+	assert.Equal(t, web.Image, "nginx:39")
+	assert.Equal(t, len(web.Environment), 40)
+	assert.Equal(t, *web.Environment["FRAGMENT_0"], "set")
+
+	yamls[10] = "services: [invalid"
+	yamls[20] = "services: [invalid"
+	for range 10 {
+		_, err = LoadWithContext(context.TODO(), buildConfigDetailsMultipleFiles(nil, yamls...))
+		assert.ErrorContains(t, err, "failed to parse filename10.yml")
+	}
+}
+
+func TestLoadTrackEnvironmentSources(t *testing.T) {
+	details := buildConfigDetails(`
+name: test-environment-sources
+services:
+  web:
+    image: nginx:${TAG}
+    prebuild:
+      - name: Publish
+        runs-on: ${REGISTRY:-docker.io}/node:18
+        environment:
+          TOKEN: ${TOKEN}
+        commands:
+          - echo $$HOME
+`, map[string]string{"TAG": "1.27", "TOKEN": "secret"})
+	details.EnvironmentSources = map[string]types.EnvironmentSource{
+		"TAG": {Origin: types.EnvironmentOriginEnvFile, File: "/project/.env", Line: 3},
+	}
+	actual, err := LoadWithContext(context.TODO(), details, func(o *Options) {
+		o.TrackEnvironmentSources = true
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]types.EnvironmentSource{
+		"TAG":      {Origin: types.EnvironmentOriginEnvFile, File: "/project/.env", Line: 3},
+		"REGISTRY": {Origin: types.EnvironmentOriginDefault},
+		"TOKEN":    {},
+	}, actual.EnvironmentSources)
+
+	actual, err = LoadWithContext(context.TODO(), details)
+	assert.NilError(t, err)
+	assert.Check(t, actual.EnvironmentSources == nil)
+}
+
+func TestLoadServicesOnly(t *testing.T) {
+	base := `
+name: test-services-only
+services:
+  base:
+    image: alpine
+  db:
+    image: postgres
+  web:
+    extends: base
+    depends_on: [cache]
+  cache:
+    image: redis
+    network_mode: service:db
+  broken:
+    image: ${BROKEN_IMAGE:?image must be set}
+    unknown_attribute: true
+`
+	override := `
+services:
+  web:
+    links: [proxy]
+  proxy:
+    image: nginx
+`
+	actual, err := LoadWithContext(context.TODO(), buildConfigDetailsMultipleFiles(nil, base, override), WithServicesOnly("web"))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"cache", "db", "proxy", "web"}, actual.ServiceNames())
+	assert.Check(t, is.Equal("alpine", actual.Services["web"].Image))
+
+	_, err = LoadWithContext(context.TODO(), buildConfigDetails(base, nil), WithServicesOnly("broken"))
+	assert.ErrorContains(t, err, "image must be set")
+
+	_, err = LoadWithContext(context.TODO(), buildConfigDetails(base, nil), WithServicesOnly("unknown"))
+	assert.ErrorContains(t, err, "no such service: unknown")
+	assert.ErrorIs(t, err, errdefs.ErrNotFound)
+
+	actual, err = LoadWithContext(context.TODO(), buildConfigDetails(base, nil), WithServicesOnly("broken"),
+		WithSkipInterpolation, WithSkipValidation)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("${BROKEN_IMAGE:?image must be set}", actual.Services["broken"].Image))
+}
@@ -0,0 +1,202 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package loader
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/errdefs"
+	"github.com/compose-spec/compose-go/v2/types"
+	"go.yaml.in/yaml/v4"
+)
+
+// WithSkipInterpolation sets the Options to load values as declared, without interpolating variables
+func WithSkipInterpolation(opts *Options) {
+	opts.SkipInterpolation = true
+}
+
+// WithServicesOnly sets the Options to only load services names and the services those refer to, see
+// Options.ServicesOnly
+func WithServicesOnly(names ...string) func(*Options) {
+	return func(opts *Options) {
+		opts.ServicesOnly = names
+	}
+}
+
+// selectServices returns the services to be loaded for names, as the services declared by files names refer to,
+// directly or not. Files are read and set with their content, so they are not read again. Documents which don't
+// parse are ignored, for parseYamlFile to report them
+func selectServices(files []types.ConfigFile, names []string) (map[string]bool, error) {
+	references := map[string][]string{}
+	for i, file := range files {
+		var documents []any
+		switch {
+		case file.Config != nil:
+			documents = append(documents, file.Config)
+		default:
+			if file.Content == nil {
+				content, err := os.ReadFile(file.Filename)
+				if err != nil {
+					return nil, err
+				}
+				files[i].Content = content
+			}
+			decoder := yaml.NewDecoder(bytes.NewReader(files[i].Content))
+			for {
+				var raw any
+				if err := decoder.Decode(&raw); err != nil {
+					break
+				}
+				documents = append(documents, raw)
+			}
+		}
+		for _, raw := range documents {
+			converted, err := convertToStringKeysRecursive(raw, "")
+			if err != nil {
+				continue
+			}
+			dict, _ := converted.(map[string]any)
+			services, _ := dict["services"].(map[string]any)
+			for name, s := range services {
+				service, _ := s.(map[string]any)
+				references[name] = append(references[name], serviceReferences(service)...)
+			}
+		}
+	}
+	return referencedServices(references, names), nil
+}
+
+// filterServices removes from dict the services names don't refer to, directly or not, reporting names which are
+// not declared
+func filterServices(dict map[string]any, names []string) error {
+	services, _ := dict["services"].(map[string]any)
+	references := map[string][]string{}
+	for name, s := range services {
+		service, _ := s.(map[string]any)
+		references[name] = serviceReferences(service)
+	}
+	for _, name := range names {
+		if _, ok := services[name]; !ok {
+			return fmt.Errorf("no such service: %s: %w", name, errdefs.ErrNotFound)
+		}
+	}
+	pruneServices(dict, referencedServices(references, names))
+	return nil
+}
+
+// pruneServices removes from dict the services which are not selected
+func pruneServices(dict map[string]any, selected map[string]bool) {
+	services, ok := dict["services"].(map[string]any)
+	if !ok {
+		return
+	}
+	for name := range services {
+		if !selected[name] {
+			delete(services, name)
+		}
+	}
+}
+
+func referencedServices(references map[string][]string, names []string) map[string]bool {
+	selected := map[string]bool{}
+	var visit func(name string)
+	visit = func(name string) {
+		if selected[name] {
+			return
+		}
+		selected[name] = true
+		for _, ref := range references[name] {
+			visit(ref)
+		}
+	}
+	for _, name := range names {
+		visit(name)
+	}
+	return selected
+}
+
+// serviceReferences returns the services service refers to by depends_on, links, volumes_from, extends within the
+// same file, network_mode, ipc or pid set as `service:<name>`, and by prebuild jobs inherit_prebuild, services and
+// runs-on set as `service:<name>`
+func serviceReferences(service map[string]any) []string {
+	var refs []string
+	switch v := service["depends_on"].(type) {
+	case []any:
+		for _, dep := range v {
+			if s, ok := dep.(string); ok {
+				refs = append(refs, s)
+			}
+		}
+	case map[string]any:
+		for dep := range v {
+			refs = append(refs, dep)
+		}
+	}
+	switch v := service["extends"].(type) {
+	case string:
+		refs = append(refs, v)
+	case map[string]any:
+		if s, ok := v["service"].(string); ok && v["file"] == nil {
+			refs = append(refs, s)
+		}
+	}
+	for _, key := range []string{"links", "volumes_from"} {
+		values, _ := service[key].([]any)
+		for _, value := range values {
+			s, ok := value.(string)
+			if !ok || strings.HasPrefix(s, "container:") {
+				continue
+			}
+			name, _, _ := strings.Cut(strings.TrimPrefix(s, types.ServicePrefix), ":")
+			refs = append(refs, name)
+		}
+	}
+	for _, key := range []string{"network_mode", "ipc", "pid"} {
+		if s, ok := service[key].(string); ok {
+			if name, ok := strings.CutPrefix(s, types.ServicePrefix); ok {
+				refs = append(refs, name)
+			}
+		}
+	}
+	if s, ok := service["inherit_prebuild"].(string); ok {
+		refs = append(refs, s)
+	}
+	for _, key := range []string{"prebuild", types.CICDExtensionPrebuild} {
+		jobs, _ := service[key].([]any)
+		if prebuild, ok := service[key].(map[string]any); ok {
+			jobs, _ = prebuild["jobs"].([]any)
+		}
+		for _, j := range jobs {
+			job, _ := j.(map[string]any)
+			if s, ok := job["runs-on"].(string); ok {
+				if name, ok := strings.CutPrefix(s, types.ServicePrefix); ok {
+					refs = append(refs, name)
+				}
+			}
+			services, _ := job["services"].([]any)
+			for _, s := range services {
+				if name, ok := s.(string); ok {
+					refs = append(refs, name)
+				}
+			}
+		}
+	}
+	return refs
+}
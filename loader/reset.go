@@ -29,12 +29,14 @@ type ResetProcessor struct {
 	target       interface{}
 	paths        []tree.Path
 	visitedNodes map[*yaml.Node][]string
+	// positions records, when set, the position of nodes by path, see Options.Diagnostics
+	positions map[string]nodePosition
 }
 
 // UnmarshalYAML implement yaml.Unmarshaler
 func (p *ResetProcessor) UnmarshalYAML(value *yaml.Node) error {
 	p.visitedNodes = make(map[*yaml.Node][]string)
-	resolved, err := p.resolveReset(value, tree.NewPath())
+	resolved, err := p.resolveReset(value, tree.NewPath(), tree.NewPath())
 	p.visitedNodes = nil
 	if err != nil {
 		return err
@@ -42,12 +44,14 @@ func (p *ResetProcessor) UnmarshalYAML(value *yaml.Node) error {
 	return resolved.Decode(p.target)
 }
 
-// resolveReset detects `!reset` tag being set on yaml nodes and record position in the yaml tree
-func (p *ResetProcessor) resolveReset(node *yaml.Node, path tree.Path) (*yaml.Node, error) {
+// resolveReset detects `!reset` tag being set on yaml nodes and record position in the yaml tree. named is path
+// with sequence items declaring a name, as prebuild jobs do, designated by their name rather than index
+func (p *ResetProcessor) resolveReset(node *yaml.Node, path tree.Path, named tree.Path) (*yaml.Node, error) {
 	pathStr := path.String()
 	// If the path contains "<<", removing the "<<" element and merging the path
 	if strings.Contains(pathStr, ".<<") {
 		path = tree.NewPath(strings.Replace(pathStr, ".<<", "", 1))
+		named = tree.NewPath(strings.Replace(named.String(), ".<<", "", 1))
 	}
 
 	// If the node is an alias, We need to process the alias field in order to consider the !override and !reset tags
@@ -56,7 +60,7 @@ func (p *ResetProcessor) resolveReset(node *yaml.Node, path tree.Path) (*yaml.No
 			return nil, err
 		}
 
-		return p.resolveReset(node.Alias, path)
+		return p.resolveReset(node.Alias, path, named)
 	}
 
 	if node.Tag == "!reset" {
@@ -74,7 +78,9 @@ func (p *ResetProcessor) resolveReset(node *yaml.Node, path tree.Path) (*yaml.No
 		var nodes []*yaml.Node
 		for idx, v := range node.Content {
 			next := path.Next(strconv.Itoa(idx))
-			resolved, err := p.resolveReset(v, next)
+			nextNamed := named.Next(nodeName(v, strconv.Itoa(idx)))
+			p.recordPosition(v, next, nextNamed)
+			resolved, err := p.resolveReset(v, next, nextNamed)
 			if err != nil {
 				return nil, err
 			}
@@ -94,7 +100,8 @@ func (p *ResetProcessor) resolveReset(node *yaml.Node, path tree.Path) (*yaml.No
 				}
 				keys[key] = v.Line
 			} else {
-				resolved, err := p.resolveReset(v, path.Next(key))
+				p.recordPosition(node.Content[idx-1], path.Next(key), named.Next(key))
+				resolved, err := p.resolveReset(v, path.Next(key), named.Next(key))
 				if err != nil {
 					return nil, err
 				}
@@ -108,6 +115,29 @@ func (p *ResetProcessor) resolveReset(node *yaml.Node, path tree.Path) (*yaml.No
 	return node, nil
 }
 
+// recordPosition records node position as the one of path, and of named path
+func (p *ResetProcessor) recordPosition(node *yaml.Node, path tree.Path, named tree.Path) {
+	if p.positions == nil {
+		return
+	}
+	position := nodePosition{line: node.Line, column: node.Column}
+	p.positions[path.String()] = position
+	p.positions[named.String()] = position
+}
+
+// nodeName returns the name a mapping node declares, or defaultName
+func nodeName(node *yaml.Node, defaultName string) string {
+	if node.Kind != yaml.MappingNode {
+		return defaultName
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == "name" && node.Content[i+1].Kind == yaml.ScalarNode && node.Content[i+1].Value != "" {
+			return node.Content[i+1].Value
+		}
+	}
+	return defaultName
+}
+
 // Apply finds the go attributes matching recorded paths and reset them to zero value
 func (p *ResetProcessor) Apply(target any) error {
 	return p.applyNullOverrides(target, tree.NewPath())
@@ -65,6 +65,11 @@ func init() {
 	mergeSpecials["services.*.logging"] = mergeLogging
 	mergeSpecials["services.*.models"] = mergeModels
 	mergeSpecials["services.*.networks"] = mergeNetworks
+	mergeSpecials["services.*.local_configs"] = mergeLocalConfigs
+	mergeSpecials["services.*.prebuild"] = mergeByName
+	mergeSpecials["services.*.prebuild.[].commands"] = mergeByName
+	mergeSpecials["services.*.sensitive"] = mergeSensitive
+	mergeSpecials["services.*.sensitive.*.on_change"] = override
 	mergeSpecials["services.*.sysctls"] = mergeToSequence
 	mergeSpecials["services.*.tmpfs"] = mergeToSequence
 	mergeSpecials["services.*.ulimits.*"] = mergeUlimit
@@ -302,6 +307,77 @@ func copyMap(m map[string]any) map[string]any {
 	return c
 }
 
+// prebuild jobs, and commands within a job, are merged by name: an overriding item is merged into the original
+// one in place and new items are appended. Loader expands commands set as a plain string to commands named after
+// those before files get merged, so a repeated short command is merged as well. A plain string merged as-is has no
+// name and is appended
+func mergeByName(c any, o any, p tree.Path) (any, error) {
+	items, ok := c.([]any)
+	if !ok {
+		return o, nil
+	}
+	others, ok := o.([]any)
+	if !ok {
+		return nil, fmt.Errorf("cannot override %s", p)
+	}
+	index := map[any]int{}
+	for i, item := range items {
+		if m, ok := item.(map[string]any); ok && m["name"] != nil {
+			index[m["name"]] = i
+		}
+	}
+	for _, other := range others {
+		m, ok := other.(map[string]any)
+		if i, exists := index[m["name"]]; ok && exists {
+			merged, err := MergeYaml(items[i], other, p.Next(tree.PathMatchList))
+			if err != nil {
+				return nil, err
+			}
+			items[i] = merged
+			continue
+		}
+		items = append(items, other)
+	}
+	return items, nil
+}
+
+// local configs are merged by target: an overriding entry replaces an entry with another name but the same target,
+// so a file is never rendered twice, while entries with the same name are merged as mappings
+func mergeLocalConfigs(c any, o any, p tree.Path) (any, error) {
+	return mergeByTarget(c, o, p, func(string) any { return nil })
+}
+
+// sensitive entries are merged by target the same way local configs are, target defaulting to /run/secrets/<name>
+func mergeSensitive(c any, o any, p tree.Path) (any, error) {
+	return mergeByTarget(c, o, p, func(name string) any { return "/run/secrets/" + name })
+}
+
+func mergeByTarget(c any, o any, p tree.Path, defaultTarget func(name string) any) (any, error) {
+	entries, ok := c.(map[string]any)
+	if !ok {
+		return o, nil
+	}
+	others, ok := o.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("cannot override %s", p)
+	}
+	target := func(name string, entry any) any {
+		if m, ok := entry.(map[string]any); ok && m["target"] != nil {
+			return m["target"]
+		}
+		return defaultTarget(name)
+	}
+	for name, other := range others {
+		t := target(name, other)
+		for existing, entry := range entries {
+			if existing != name && t != nil && target(existing, entry) == t {
+				delete(entries, existing)
+			}
+		}
+	}
+	return mergeMappings(entries, others, p)
+}
+
 func override(_ any, other any, _ tree.Path) (any, error) {
 	return other, nil
 }
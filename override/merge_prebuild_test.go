@@ -0,0 +1,250 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package override
+
+import (
+	"testing"
+)
+
+func TestMergePrebuildJobsByName(t *testing.T) {
+	assertMergeYaml(t, `
+services:
+  test:
+    image: foo
+    prebuild:
+      - name: Lint
+        commands:
+          - name: Vet
+            command: go vet ./...
+      - name: Test
+        commands:
+          - name: Unit
+            command: go test ./...
+`, `
+services:
+  test:
+    prebuild:
+      - name: Test
+        commands:
+          - name: Race
+            command: go test -race ./...
+      - name: Build
+        commands:
+          - name: Compile
+            command: go build ./...
+`, `
+services:
+  test:
+    image: foo
+    prebuild:
+      - name: Lint
+        commands:
+          - name: Vet
+            command: go vet ./...
+      - name: Test
+        commands:
+          - name: Unit
+            command: go test ./...
+          - name: Race
+            command: go test -race ./...
+      - name: Build
+        commands:
+          - name: Compile
+            command: go build ./...
+`)
+}
+
+func TestMergePrebuildJobPartialOverride(t *testing.T) {
+	assertMergeYaml(t, `
+services:
+  test:
+    image: foo
+    prebuild:
+      - name: Test
+        runs-on: golang:1.21
+        commands:
+          - name: Unit
+            command: go test ./...
+            workdir: /src
+          - go vet ./...
+`, `
+services:
+  test:
+    prebuild:
+      - name: Test
+        runs-on: golang:1.22
+        commands:
+          - name: Unit
+            command: go test -short ./...
+          - go vet ./...
+`, `
+services:
+  test:
+    image: foo
+    prebuild:
+      - name: Test
+        runs-on: golang:1.22
+        commands:
+          - name: Unit
+            command: go test -short ./...
+            workdir: /src
+          - go vet ./...
+          - go vet ./...
+`)
+}
+
+func TestMergeLocalConfigsByTarget(t *testing.T) {
+	assertMergeYaml(t, `
+services:
+  test:
+    image: foo
+    local_configs:
+      nginx:
+        source: ./nginx.conf
+        target: /etc/nginx/nginx.conf
+        mode: 0440
+      motd:
+        content: hello
+        target: /etc/motd
+`, `
+services:
+  test:
+    local_configs:
+      nginx:
+        source: ./nginx.prod.conf
+      banner:
+        content: welcome
+        target: /etc/motd
+      app:
+        content: debug=false
+        target: /etc/app.conf
+`, `
+services:
+  test:
+    image: foo
+    local_configs:
+      nginx:
+        source: ./nginx.prod.conf
+        target: /etc/nginx/nginx.conf
+        mode: 0440
+      banner:
+        content: welcome
+        target: /etc/motd
+      app:
+        content: debug=false
+        target: /etc/app.conf
+`)
+}
+
+func TestMergeSensitiveByTarget(t *testing.T) {
+	assertMergeYaml(t, `
+services:
+  test:
+    image: foo
+    sensitive:
+      api_key:
+        secrets:
+          - source: api_key
+      db:
+        format: env
+        target: /run/secrets/db.env
+        secrets:
+          - source: db_password
+`, `
+services:
+  test:
+    sensitive:
+      token:
+        target: /run/secrets/api_key
+        secrets:
+          - source: api_token
+      db:
+        mode: 0400
+`, `
+services:
+  test:
+    image: foo
+    sensitive:
+      token:
+        target: /run/secrets/api_key
+        secrets:
+          - source: api_token
+      db:
+        format: env
+        target: /run/secrets/db.env
+        mode: 0400
+        secrets:
+          - source: db_password
+`)
+}
+
+func TestMergePrebuildShortCommands(t *testing.T) {
+	// as expanded by loader
+	assertMergeYaml(t, `
+services:
+  test:
+    image: foo
+    prebuild:
+      - name: Build
+        commands:
+          - name: make
+            command: make
+`, `
+services:
+  test:
+    prebuild:
+      - name: Build
+        commands:
+          - name: make
+            command: make
+          - name: make test
+            command: make test
+`, `
+services:
+  test:
+    image: foo
+    prebuild:
+      - name: Build
+        commands:
+          - name: make
+            command: make
+          - name: make test
+            command: make test
+`)
+	// as declared
+	assertMergeYaml(t, `
+services:
+  test:
+    image: foo
+    prebuild:
+      - name: Build
+        commands: ["make"]
+`, `
+services:
+  test:
+    prebuild:
+      - name: Build
+        commands: ["make"]
+`, `
+services:
+  test:
+    image: foo
+    prebuild:
+      - name: Build
+        commands: ["make", "make"]
+`)
+}
@@ -34,20 +34,23 @@ func ResolveRelativePaths(project map[string]any, base string, remotes []RemoteR
 		remotes:    remotes,
 	}
 	r.resolvers = map[tree.Path]resolver{
-		"services.*.build.context":               r.absContextPath,
-		"services.*.build.additional_contexts.*": r.absContextPath,
-		"services.*.build.ssh.*":                 r.maybeUnixPath,
-		"services.*.env_file.*.path":             r.absPath,
-		"services.*.label_file.*":                r.absPath,
-		"services.*.extends.file":                r.absExtendsPath,
-		"services.*.develop.watch.*.path":        r.absSymbolicLink,
-		"services.*.volumes.*":                   r.absVolumeMount,
-		"configs.*.file":                         r.maybeUnixPath,
-		"secrets.*.file":                         r.maybeUnixPath,
-		"include.path":                           r.absPath,
-		"include.project_directory":              r.absPath,
-		"include.env_file":                       r.absPath,
-		"volumes.*":                              r.volumeDriverOpts,
+		"services.*.build.context":                         r.absContextPath,
+		"services.*.build.additional_contexts.*":           r.absContextPath,
+		"services.*.build.ssh.*":                           r.maybeUnixPath,
+		"services.*.env_file.*.path":                       r.absPath,
+		"services.*.label_file.*":                          r.absPath,
+		"services.*.extends.file":                          r.absExtendsPath,
+		"services.*.develop.watch.*.path":                  r.absSymbolicLink,
+		"services.*.volumes.*":                             r.absVolumeMount,
+		"services.*.prebuild.*.commands.*.allowed_paths":   r.absPath,
+		"services.*.prebuild.*.commands.*.env_file.*.path": r.absPath,
+		"services.*.sensitive.*.secrets.*.file":            r.maybeUnixPath,
+		"configs.*.file":                                   r.maybeUnixPath,
+		"secrets.*.file":                                   r.maybeUnixPath,
+		"include.path":                                     r.absPath,
+		"include.project_directory":                        r.absPath,
+		"include.env_file":                                 r.absPath,
+		"volumes.*":                                        r.volumeDriverOpts,
 	}
 	_, err := r.resolveRelativePaths(project, tree.NewPath())
 	return err
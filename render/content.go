@@ -0,0 +1,110 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/errdefs"
+	"github.com/compose-spec/compose-go/v2/types"
+)
+
+// RenderSensitive renders a sensitive entry as the content of its target file, secrets values being set by
+// source. All secrets listed by the entry must have a value
+func RenderSensitive(sensitive types.SensitiveConfig, values map[string]string) ([]byte, error) {
+	var secrets []ResolvedSecret
+	for _, secret := range sensitive.SortedSecrets() {
+		value, ok := values[secret.Source]
+		if !ok {
+			return nil, fmt.Errorf("secret %s: %w", secret.Source, errdefs.ErrNotFound)
+		}
+		secrets = append(secrets, ResolvedSecret{Name: sensitive.SecretName(secret), Value: value})
+	}
+	return Content(sensitive, secrets)
+}
+
+// Content renders resolved secrets of a sensitive entry as the content of its target file, according to entry
+// format. Output of env, json and raw formats ends with a newline according to SensitiveConfig.HasTrailingNewline,
+// while template format output is set by template, executed with secrets values by name
+func Content(sensitive types.SensitiveConfig, secrets []ResolvedSecret) ([]byte, error) {
+	var buf bytes.Buffer
+	switch sensitive.OutputFormat() {
+	case types.SensitiveFormatEnv:
+		lines := make([]string, len(secrets))
+		for i, secret := range secrets {
+			lines[i] = secret.Name + "=" + dotenvValue(secret.Value)
+		}
+		buf.WriteString(strings.Join(lines, "\n"))
+	case types.SensitiveFormatJSON:
+		buf.WriteByte('{')
+		for i, secret := range secrets {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			name, _ := json.Marshal(secret.Name)
+			value, _ := json.Marshal(secret.Value)
+			buf.Write(name)
+			buf.WriteByte(':')
+			buf.Write(value)
+		}
+		buf.WriteByte('}')
+	case types.SensitiveFormatRaw:
+		if len(secrets) != 1 {
+			return nil, fmt.Errorf("raw format requires exactly one secret, got %d: %w", len(secrets), errdefs.ErrInvalid)
+		}
+		buf.WriteString(secrets[0].Value)
+	case types.SensitiveFormatTemplate:
+		tmpl, err := sensitive.ParseTemplate()
+		if err != nil {
+			return nil, err
+		}
+		if err := sensitive.CheckTemplatePlaceholders(tmpl); err != nil {
+			return nil, err
+		}
+		values := map[string]string{}
+		for _, secret := range secrets {
+			values[secret.Name] = secret.Value
+		}
+		if err := tmpl.Execute(&buf, values); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q: %w", sensitive.Format, errdefs.ErrInvalid)
+	}
+	if sensitive.HasTrailingNewline() {
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes(), nil
+}
+
+var dotenvPlainValue = regexp.MustCompile(`^[a-zA-Z0-9_./:@%+=,-]*$`)
+
+var dotenvEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// dotenvValue writes value so it is parsed back as-is from a dotenv file, double-quoting and escaping it when
+// it contains special characters
+func dotenvValue(value string) string {
+	if dotenvPlainValue.MatchString(value) {
+		return value
+	}
+	return `"` + dotenvEscaper.Replace(value) + `"`
+}
@@ -0,0 +1,127 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package render
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/dotenv"
+	"github.com/compose-spec/compose-go/v2/errdefs"
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestContentTrailingNewline(t *testing.T) {
+	secrets := []ResolvedSecret{
+		{Name: "API_KEY", Value: "abc"},
+		{Name: "DB_USER", Value: "admin"},
+	}
+	yes, no := true, false
+	tests := []struct {
+		format          types.SensitiveFormat
+		trailingNewline *bool
+		secrets         []ResolvedSecret
+		expected        string
+	}{
+		{format: "env", secrets: secrets, expected: "API_KEY=abc\nDB_USER=admin\n"},
+		{format: "env", trailingNewline: &no, secrets: secrets, expected: "API_KEY=abc\nDB_USER=admin"},
+		{format: "json", secrets: secrets, expected: `{"API_KEY":"abc","DB_USER":"admin"}` + "\n"},
+		{format: "json", trailingNewline: &no, secrets: secrets, expected: `{"API_KEY":"abc","DB_USER":"admin"}`},
+		{format: "raw", secrets: secrets[:1], expected: "abc"},
+		{format: "raw", trailingNewline: &yes, secrets: secrets[:1], expected: "abc\n"},
+	}
+	for _, tt := range tests {
+		actual, err := Content(types.SensitiveConfig{Format: tt.format, TrailingNewline: tt.trailingNewline}, tt.secrets)
+		assert.NilError(t, err)
+		assert.Equal(t, tt.expected, string(actual))
+	}
+}
+
+func TestRenderSensitiveEnv(t *testing.T) {
+	sensitive := types.SensitiveConfig{
+		Format: types.SensitiveFormatEnv,
+		Secrets: []types.SensitiveSecret{
+			{Source: "api_key", Name: "API_KEY"},
+			{Source: "db_password"},
+			{Source: "certificate", Name: "CERT"},
+		},
+	}
+	values := map[string]string{
+		"api_key":     "abc123",
+		"db_password": `p@ss "word" $HOME`,
+		"certificate": "-----BEGIN-----\nMIIB\n-----END-----",
+	}
+	actual, err := RenderSensitive(sensitive, values)
+	assert.NilError(t, err)
+	assert.Equal(t, `API_KEY=abc123
+db_password="p@ss \"word\" \$HOME"
+CERT="-----BEGIN-----\nMIIB\n-----END-----"
+`, string(actual))
+
+	parsed, err := dotenv.UnmarshalBytesWithLookup(actual, nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]string{
+		"API_KEY":     values["api_key"],
+		"db_password": values["db_password"],
+		"CERT":        values["certificate"],
+	}, parsed)
+}
+
+func TestRenderSensitiveRaw(t *testing.T) {
+	sensitive := types.SensitiveConfig{
+		Format:  types.SensitiveFormatRaw,
+		Secrets: []types.SensitiveSecret{{Source: "api_key"}},
+	}
+	actual, err := RenderSensitive(sensitive, map[string]string{"api_key": "abc123"})
+	assert.NilError(t, err)
+	assert.Equal(t, "abc123", string(actual))
+
+	_, err = RenderSensitive(sensitive, map[string]string{"db_password": "secret"})
+	assert.ErrorIs(t, err, errdefs.ErrNotFound)
+	assert.ErrorContains(t, err, "secret api_key")
+
+	sensitive.Secrets = append(sensitive.Secrets, types.SensitiveSecret{Source: "db_password"})
+	_, err = RenderSensitive(sensitive, map[string]string{"api_key": "abc123", "db_password": "secret"})
+	assert.ErrorContains(t, err, "raw format requires exactly one secret, got 2")
+}
+
+func TestRenderSensitiveTemplate(t *testing.T) {
+	sensitive := types.SensitiveConfig{
+		Format: types.SensitiveFormatTemplate,
+		TemplateContent: `[databases]
+app = host=db user={{ .DB_USER }} password={{ .DB_PASSWORD }}
+{{ if .ADMIN_PASSWORD }}admin_password = {{ .ADMIN_PASSWORD }}{{ end }}
+`,
+		Secrets: []types.SensitiveSecret{
+			{Source: "db_user", Name: "DB_USER"},
+			{Source: "db_password", Name: "DB_PASSWORD"},
+			{Source: "admin_password", Name: "ADMIN_PASSWORD"},
+		},
+	}
+	values := map[string]string{"db_user": "app", "db_password": "s3cr3t", "admin_password": "r00t"}
+	actual, err := RenderSensitive(sensitive, values)
+	assert.NilError(t, err)
+	assert.Equal(t, `[databases]
+app = host=db user=app password=s3cr3t
+admin_password = r00t
+`, string(actual))
+
+	sensitive.Secrets = sensitive.Secrets[:2]
+	_, err = RenderSensitive(sensitive, values)
+	assert.Error(t, err, "template refers to undeclared secrets ADMIN_PASSWORD: invalid compose project")
+	assert.ErrorIs(t, err, errdefs.ErrInvalid)
+}
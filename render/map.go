@@ -0,0 +1,42 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package render
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/compose-spec/compose-go/v2/types"
+)
+
+// RenderSensitiveToMap renders service sensitive entries in memory, by target, applying each entry format and
+// options as RenderFilesTar does. This is meant for tests of secrets consumption, which don't need files written
+func RenderSensitiveToMap(ctx context.Context, service types.ServiceConfig, resolver SecretResolver) (map[string]string, error) {
+	rendered := map[string]string{}
+	for name, c := range service.Sensitive {
+		secrets, err := ResolveSecrets(ctx, resolver, c)
+		if err != nil {
+			return nil, fmt.Errorf("sensitive %s: %w", name, err)
+		}
+		content, err := Content(c, secrets)
+		if err != nil {
+			return nil, fmt.Errorf("sensitive %s: %w", name, err)
+		}
+		rendered[c.Target] = string(content)
+	}
+	return rendered, nil
+}
@@ -0,0 +1,63 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package render
+
+import (
+	"context"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/errdefs"
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+)
+
+func TestRenderSensitiveToMap(t *testing.T) {
+	service := types.ServiceConfig{
+		Name: "web",
+		Sensitive: map[string]types.SensitiveConfig{
+			"app": {
+				Target: "/run/secrets/app.env",
+				Format: types.SensitiveFormatEnv,
+				Secrets: []types.SensitiveSecret{
+					{Source: "db_password", Name: "DB_PASSWORD"},
+					{Source: "api_key", Name: "API_KEY"},
+				},
+			},
+			"token": {
+				Target:  "/run/secrets/token",
+				Secrets: []types.SensitiveSecret{{Source: "api_key"}},
+			},
+		},
+	}
+	values := map[string]string{"db_password": "s3cr3t", "api_key": "abc123"}
+	resolver := ResolverFunc(func(_ context.Context, source string) (string, bool, error) {
+		v, ok := values[source]
+		return v, ok, nil
+	})
+
+	actual, err := RenderSensitiveToMap(context.TODO(), service, resolver)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, actual, map[string]string{
+		"/run/secrets/app.env": "DB_PASSWORD=s3cr3t\nAPI_KEY=abc123\n",
+		"/run/secrets/token":   "abc123",
+	})
+
+	delete(values, "db_password")
+	_, err = RenderSensitiveToMap(context.TODO(), service, resolver)
+	assert.ErrorContains(t, err, "sensitive app: secret db_password")
+	assert.ErrorIs(t, err, errdefs.ErrNotFound)
+}
@@ -0,0 +1,87 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package render produces the files described by cicdez attributes, resolving secret values from a backend
+package render
+
+import (
+	"context"
+	"sync"
+)
+
+// SecretResolver resolves the value of a top-level secret by source name.
+// found is false when backend has no value for this secret
+type SecretResolver interface {
+	Resolve(ctx context.Context, source string) (value string, found bool, err error)
+}
+
+// ResolverFunc adapts a function as a SecretResolver
+type ResolverFunc func(ctx context.Context, source string) (string, bool, error)
+
+func (f ResolverFunc) Resolve(ctx context.Context, source string) (string, bool, error) {
+	return f(ctx, source)
+}
+
+type cachedSecret struct {
+	ready chan struct{}
+	value string
+	found bool
+	err   error
+}
+
+type cachingResolver struct {
+	inner SecretResolver
+	mu    sync.Mutex
+	cache map[string]*cachedSecret
+}
+
+// NewCachingResolver memoizes values resolved by inner, including not found results, so each source is resolved
+// once. Errors are not cached and get retried on next call. Returned resolver is safe for concurrent use, concurrent
+// calls for the same source waiting for a single inner resolution.
+func NewCachingResolver(inner SecretResolver) SecretResolver {
+	return &cachingResolver{
+		inner: inner,
+		cache: map[string]*cachedSecret{},
+	}
+}
+
+func (r *cachingResolver) Resolve(ctx context.Context, source string) (string, bool, error) {
+	r.mu.Lock()
+	entry, ok := r.cache[source]
+	if !ok {
+		entry = &cachedSecret{ready: make(chan struct{})}
+		r.cache[source] = entry
+	}
+	r.mu.Unlock()
+
+	if ok {
+		select {
+		case <-entry.ready:
+			return entry.value, entry.found, entry.err
+		case <-ctx.Done():
+			return "", false, ctx.Err()
+		}
+	}
+
+	entry.value, entry.found, entry.err = r.inner.Resolve(ctx, source)
+	if entry.err != nil {
+		r.mu.Lock()
+		delete(r.cache, source)
+		r.mu.Unlock()
+	}
+	close(entry.ready)
+	return entry.value, entry.found, entry.err
+}
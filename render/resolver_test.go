@@ -0,0 +1,84 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package render
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestCachingResolverConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+	inner := ResolverFunc(func(_ context.Context, source string) (string, bool, error) {
+		mu.Lock()
+		calls[source]++
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		if source == "missing" {
+			return "", false, nil
+		}
+		return "value-of-" + source, true, nil
+	})
+	resolver := NewCachingResolver(inner)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		for _, source := range []string{"db_password", "api_key", "missing"} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				value, found, err := resolver.Resolve(context.TODO(), source)
+				assert.Check(t, err)
+				if source == "missing" {
+					assert.Check(t, !found)
+					return
+				}
+				assert.Check(t, found)
+				assert.Check(t, is.Equal("value-of-"+source, value))
+			}()
+		}
+	}
+	wg.Wait()
+	assert.DeepEqual(t, map[string]int{"db_password": 1, "api_key": 1, "missing": 1}, calls)
+}
+
+func TestCachingResolverRetriesErrors(t *testing.T) {
+	var calls atomic.Int32
+	inner := ResolverFunc(func(_ context.Context, source string) (string, bool, error) {
+		if calls.Add(1) == 1 {
+			return "", false, errors.New("backend unavailable")
+		}
+		return "secret", true, nil
+	})
+	resolver := NewCachingResolver(inner)
+
+	_, _, err := resolver.Resolve(context.TODO(), "db_password")
+	assert.ErrorContains(t, err, "backend unavailable")
+	value, found, err := resolver.Resolve(context.TODO(), "db_password")
+	assert.NilError(t, err)
+	assert.Check(t, found)
+	assert.Check(t, is.Equal("secret", value))
+	assert.Check(t, is.Equal(int32(2), calls.Load()))
+}
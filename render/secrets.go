@@ -0,0 +1,72 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package render
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/compose-spec/compose-go/v2/errdefs"
+	"github.com/compose-spec/compose-go/v2/types"
+)
+
+// ResolvedSecret is a secret value, with the name it is exposed with in the sensitive output
+type ResolvedSecret struct {
+	Name  string
+	Value string
+}
+
+// ResolveOptions configures secrets resolution
+type ResolveOptions struct {
+	// AllowDefaults uses secrets `default` value when not found by resolver. This is meant for local development
+	// and should never be set in production
+	AllowDefaults bool
+}
+
+// WithSensitiveDefaults makes ResolveSecrets use secrets `default` value when not found by resolver
+func WithSensitiveDefaults(o *ResolveOptions) {
+	o.AllowDefaults = true
+}
+
+// ResolveSecrets resolves secrets of a sensitive entry, in render order. Values are checked against secrets
+// `validate` constraints, so a misconfigured secret is reported before any file gets written
+func ResolveSecrets(ctx context.Context, resolver SecretResolver, sensitive types.SensitiveConfig, options ...func(*ResolveOptions)) ([]ResolvedSecret, error) {
+	opts := ResolveOptions{}
+	for _, option := range options {
+		option(&opts)
+	}
+	var resolved []ResolvedSecret
+	for _, secret := range sensitive.SortedSecrets() {
+		value, found, err := resolver.Resolve(ctx, secret.Source)
+		if err != nil {
+			return nil, fmt.Errorf("secret %s: %w", secret.Source, err)
+		}
+		if !found && opts.AllowDefaults && secret.Default != nil {
+			value, found = *secret.Default, true
+		}
+		if !found {
+			return nil, fmt.Errorf("secret %s: %w", secret.Source, errdefs.ErrNotFound)
+		}
+		if secret.Validate != nil {
+			if err := secret.Validate.Check(value); err != nil {
+				return nil, fmt.Errorf("secret %s: %w", secret.Source, err)
+			}
+		}
+		resolved = append(resolved, ResolvedSecret{Name: sensitive.SecretName(secret), Value: value})
+	}
+	return resolved, nil
+}
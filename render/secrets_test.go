@@ -0,0 +1,94 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package render
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/errdefs"
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+)
+
+func mapResolver(values map[string]string) SecretResolver {
+	return ResolverFunc(func(_ context.Context, source string) (string, bool, error) {
+		value, ok := values[source]
+		return value, ok, nil
+	})
+}
+
+func TestResolveSecretsValidate(t *testing.T) {
+	sensitive := types.SensitiveConfig{
+		Secrets: []types.SensitiveSecret{
+			{
+				Source:   "api_key",
+				Name:     "API_KEY",
+				Validate: &types.SensitiveSecretValidation{MinLength: 16, Pattern: "^[A-Za-z0-9]+$"},
+			},
+			{Source: "db_user"},
+		},
+	}
+
+	resolved, err := ResolveSecrets(context.TODO(), mapResolver(map[string]string{
+		"api_key": "abcdefghij0123456789",
+		"db_user": "admin",
+	}), sensitive)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, resolved, []ResolvedSecret{
+		{Name: "API_KEY", Value: "abcdefghij0123456789"},
+		{Name: "db_user", Value: "admin"},
+	})
+
+	_, err = ResolveSecrets(context.TODO(), mapResolver(map[string]string{
+		"api_key": "short",
+		"db_user": "admin",
+	}), sensitive)
+	assert.Error(t, err, "secret api_key: value must be at least 16 characters long: invalid compose project")
+
+	_, err = ResolveSecrets(context.TODO(), mapResolver(map[string]string{
+		"api_key": "abcdefghij-0123456789",
+		"db_user": "admin",
+	}), sensitive)
+	assert.ErrorContains(t, err, `secret api_key: value doesn't match pattern "^[A-Za-z0-9]+$"`)
+	assert.Check(t, !strings.Contains(err.Error(), "abcdefghij"), "error must not leak secret value")
+
+	_, err = ResolveSecrets(context.TODO(), mapResolver(map[string]string{"api_key": "abcdefghij0123456789"}), sensitive)
+	assert.ErrorIs(t, err, errdefs.ErrNotFound)
+}
+
+func TestResolveSecretsDefault(t *testing.T) {
+	placeholder := "dev-placeholder"
+	sensitive := types.SensitiveConfig{
+		Secrets: []types.SensitiveSecret{
+			{Source: "api_key", Default: &placeholder},
+			{Source: "db_user", Default: &placeholder},
+		},
+	}
+	resolver := mapResolver(map[string]string{"db_user": "admin"})
+
+	resolved, err := ResolveSecrets(context.TODO(), resolver, sensitive, WithSensitiveDefaults)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, resolved, []ResolvedSecret{
+		{Name: "api_key", Value: "dev-placeholder"},
+		{Name: "db_user", Value: "admin"},
+	})
+
+	_, err = ResolveSecrets(context.TODO(), resolver, sensitive)
+	assert.ErrorIs(t, err, errdefs.ErrNotFound)
+}
@@ -0,0 +1,115 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package render
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+)
+
+type tarFile struct {
+	target string
+	uid    string
+	gid    string
+	// resolvedUID and resolvedGID are used when uid or gid are names
+	resolvedUID int
+	resolvedGID int
+	mode        os.FileMode
+	content     []byte
+}
+
+// RenderFilesTar renders service sensitive entries and local configs as a tar stream, one entry per target
+// sorted by path, with headers set by configured mode, uid and gid, named ones being set by ResolvedUID and
+// ResolvedGID. Secrets are all resolved before the stream is produced, so a missing or invalid value doesn't
+// result in a partial archive
+func RenderFilesTar(ctx context.Context, service types.ServiceConfig, resolver SecretResolver) (io.Reader, error) {
+	var files []tarFile
+	for name, c := range service.Sensitive {
+		secrets, err := ResolveSecrets(ctx, resolver, c)
+		if err != nil {
+			return nil, fmt.Errorf("sensitive %s: %w", name, err)
+		}
+		content, err := Content(c, secrets)
+		if err != nil {
+			return nil, fmt.Errorf("sensitive %s: %w", name, err)
+		}
+		files = append(files, tarFile{target: c.Target, uid: c.UID, gid: c.GID, resolvedUID: c.ResolvedUID,
+			resolvedGID: c.ResolvedGID, mode: c.FileMode(), content: content})
+	}
+	for name, c := range service.LocalConfigs {
+		var content []byte
+		if c.Source != "" {
+			b, err := os.ReadFile(c.SourcePath())
+			if err != nil {
+				return nil, fmt.Errorf("local config %s: %w", name, err)
+			}
+			content = b
+		} else {
+			s, err := c.RenderContent(service.Environment.ToMapping())
+			if err != nil {
+				return nil, fmt.Errorf("local config %s: %w", name, err)
+			}
+			content = []byte(s)
+		}
+		files = append(files, tarFile{target: c.Target, uid: c.UID, gid: c.GID, resolvedUID: c.ResolvedUID,
+			resolvedGID: c.ResolvedGID, mode: c.Mode.OSFileMode(), content: content})
+	}
+	slices.SortFunc(files, func(a, b tarFile) int {
+		return strings.Compare(a.target, b.target)
+	})
+
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+	for _, f := range files {
+		err := w.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     f.target,
+			Size:     int64(len(f.content)),
+			Mode:     int64(f.mode),
+			Uid:      tarID(f.uid, f.resolvedUID),
+			Gid:      tarID(f.gid, f.resolvedGID),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(f.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
+
+// tarID parses a uid or gid, which defaults to root when not set. Names, like `appuser`, are set as resolved by
+// loader Options.ResolveUserNames
+func tarID(id string, resolved int) int {
+	if n, err := strconv.Atoi(id); err == nil {
+		return n
+	}
+	return resolved
+}
@@ -0,0 +1,105 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package render
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+)
+
+func TestRenderFilesTar(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "nginx.conf")
+	assert.NilError(t, os.WriteFile(source, []byte("worker_processes 1;"), 0o600))
+
+	mode := types.FileMode(0o400)
+	service := types.ServiceConfig{
+		Name: "web",
+		Sensitive: map[string]types.SensitiveConfig{
+			"api": {
+				Target:  "/run/secrets/api.env",
+				Format:  types.SensitiveFormatEnv,
+				Secrets: []types.SensitiveSecret{{Source: "api_key", Name: "API_KEY"}},
+				UID:     "1000",
+				GID:     "1001",
+				Mode:    &mode,
+			},
+			"token": {
+				Target:  "/run/secrets/token",
+				Secrets: []types.SensitiveSecret{{Source: "api_key"}},
+			},
+		},
+		LocalConfigs: map[string]types.LocalConfigConfig{
+			"nginx": {Source: source, Target: "/etc/nginx/nginx.conf"},
+			"motd":  {Content: "hello", Target: "/etc/motd", UID: "0"},
+		},
+	}
+	resolver := ResolverFunc(func(_ context.Context, source string) (string, bool, error) {
+		return "secret", source == "api_key", nil
+	})
+
+	r, err := RenderFilesTar(context.TODO(), service, resolver)
+	assert.NilError(t, err)
+
+	type entry struct {
+		name    string
+		mode    int64
+		uid     int
+		gid     int
+		content string
+	}
+	var actual []entry
+	tr := tar.NewReader(r)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NilError(t, err)
+		content, err := io.ReadAll(tr)
+		assert.NilError(t, err)
+		actual = append(actual, entry{name: h.Name, mode: h.Mode, uid: h.Uid, gid: h.Gid, content: string(content)})
+	}
+	assert.DeepEqual(t, []entry{
+		{name: "/etc/motd", mode: 0o444, content: "hello"},
+		{name: "/etc/nginx/nginx.conf", mode: 0o444, content: "worker_processes 1;"},
+		{name: "/run/secrets/api.env", mode: 0o400, uid: 1000, gid: 1001, content: "API_KEY=secret\n"},
+		{name: "/run/secrets/token", mode: 0o400, content: "secret"},
+	}, actual, cmp.AllowUnexported(entry{}))
+}
+
+func TestRenderFilesTarNamedIDs(t *testing.T) {
+	service := types.ServiceConfig{
+		Name: "web",
+		LocalConfigs: map[string]types.LocalConfigConfig{
+			"app": {Content: "hello", Target: "/etc/c", UID: "appuser", GID: "appgroup", ResolvedUID: 1000, ResolvedGID: 1001},
+		},
+	}
+	r, err := RenderFilesTar(context.TODO(), service, nil)
+	assert.NilError(t, err)
+	h, err := tar.NewReader(r).Next()
+	assert.NilError(t, err)
+	assert.Equal(t, h.Uid, 1000)
+	assert.Equal(t, h.Gid, 1001)
+}
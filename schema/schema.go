@@ -46,6 +46,45 @@ func durationFormatChecker(input any) error {
 //go:embed compose-spec.json
 var Schema string
 
+// cicdExtensions maps extension forms of cicdez attributes, as produced by types.WithCICDAsExtensions, to the schema
+// of the native attribute, for the top-level and services
+var cicdExtensions = map[string]map[string]string{
+	"properties": {
+		"x-stages":        "#/properties/stages",
+		"x-file-defaults": "#/definitions/file_defaults",
+	},
+	"service": {
+		"x-prebuild":      "#/definitions/service/properties/prebuild",
+		"x-local-configs": "#/definitions/service/properties/local_configs",
+		"x-sensitive":     "#/definitions/service/properties/sensitive",
+		"x-file-defaults": "#/definitions/file_defaults",
+	},
+}
+
+// ExtendedSchema returns the JSON schema of the compose dialect supported by this library, for editors and external
+// validators to offer completion of it. Schema already declares native cicdez attributes; this document also
+// declares their extension form, as `x-prebuild`, which Schema accepts as any extension
+func ExtendedSchema() ([]byte, error) {
+	var document map[string]any
+	if err := json.Unmarshal([]byte(Schema), &document); err != nil {
+		return nil, err
+	}
+	definitions, _ := document["definitions"].(map[string]any)
+	service, _ := definitions["service"].(map[string]any)
+	owners := map[string]any{"properties": document["properties"], "service": service["properties"]}
+	for owner, extensions := range cicdExtensions {
+		properties, ok := owners[owner].(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("compose schema has no %s properties", owner)
+		}
+		for extension, ref := range extensions {
+			properties[extension] = map[string]any{"$ref": ref}
+		}
+	}
+	document["title"] = "Compose Specification, cicdez dialect"
+	return json.MarshalIndent(document, "", "  ")
+}
+
 // Validate uses the jsonschema to validate the configuration
 func Validate(config map[string]interface{}) error {
 	compiler := jsonschema.NewCompiler()
@@ -138,12 +177,31 @@ func getMostSpecificError(err *jsonschema.ValidationError) *jsonschema.Validatio
 	return mostSpecificError
 }
 
+// specificity ranks errors by depth, additional properties errors first. On a tie within cicdez attributes, a type
+// mismatch ranks after other errors, so that a mapping matching the object branch of a `oneOf` reports what's wrong
+// with it rather than not being of the alternative scalar type. Other attributes keep the first error reported
 func specificity(err *jsonschema.ValidationError) int {
 	if err == nil {
 		return -1
 	}
+	depth := len(err.InstanceLocation)
 	if _, ok := err.ErrorKind.(*kind.AdditionalProperties); ok {
-		return len(err.InstanceLocation) + 1
+		depth++
+	}
+	if _, ok := err.ErrorKind.(*kind.Type); ok || !isCICDLocation(err.InstanceLocation) {
+		return 2 * depth
+	}
+	return 2*depth + 1
+}
+
+// isCICDLocation tells if location is within a service prebuild, local_configs or sensitive attribute
+func isCICDLocation(location []string) bool {
+	if len(location) < 3 || location[0] != "services" {
+		return false
+	}
+	switch location[2] {
+	case "prebuild", "local_configs", "sensitive":
+		return true
 	}
-	return len(err.InstanceLocation)
+	return false
 }
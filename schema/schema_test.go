@@ -21,6 +21,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/santhosh-tekuri/jsonschema/v6"
 	"go.yaml.in/yaml/v4"
 	"gotest.tools/v3/assert"
@@ -370,6 +371,37 @@ func TestValidateCicdezFieldsCombined(t *testing.T) {
 	assert.NilError(t, Validate(config))
 }
 
+func TestValidateMostSpecificError(t *testing.T) {
+	tests := []struct {
+		name     string
+		service  map[string]any
+		expected string
+	}{
+		{
+			name:     "prebuild command",
+			service:  map[string]any{"prebuild": []any{map[string]any{"name": "Build", "commands": []any{map[string]any{"name": "Compile"}}}}},
+			expected: "services.web.prebuild.0.commands.0 missing property 'command'",
+		},
+		{
+			name:     "ulimits",
+			service:  map[string]any{"ulimits": map[string]any{"nofile": map[string]any{"soft": 1}}},
+			expected: "services.web.ulimits.nofile must be a integer or string",
+		},
+		{
+			name:     "env_file",
+			service:  map[string]any{"env_file": []any{map[string]any{"required": true}}},
+			expected: "services.web.env_file.0 must be a string",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.service["image"] = "busybox"
+			err := Validate(map[string]any{"services": map[string]any{"web": tt.service}})
+			assert.Error(t, err, tt.expected)
+		})
+	}
+}
+
 func TestSchema(t *testing.T) {
 	compiler := jsonschema.NewCompiler()
 	json, err := jsonschema.UnmarshalJSON(strings.NewReader(Schema))
@@ -380,3 +412,47 @@ func TestSchema(t *testing.T) {
 	_, err = compiler.Compile("compose-spec.json")
 	assert.NilError(t, err)
 }
+
+func TestExtendedSchema(t *testing.T) {
+	extended, err := ExtendedSchema()
+	assert.NilError(t, err)
+	for _, extension := range []string{
+		types.CICDExtensionPrebuild, types.CICDExtensionLocalConfigs, types.CICDExtensionSensitive,
+		types.CICDExtensionStages, types.CICDExtensionFileDefaults,
+	} {
+		assert.Check(t, strings.Contains(string(extended), `"`+extension+`": {`), extension)
+	}
+	compiler := jsonschema.NewCompiler()
+	document, err := jsonschema.UnmarshalJSON(strings.NewReader(string(extended)))
+	assert.NilError(t, err)
+	assert.NilError(t, compiler.AddResource("compose-spec.json", document))
+	compiler.DefaultDraft(jsonschema.Draft7)
+	compiler.RegisterFormat(&jsonschema.Format{Name: "duration", Validate: durationFormatChecker})
+	schema, err := compiler.Compile("compose-spec.json")
+	assert.NilError(t, err)
+
+	valid := map[string]any{
+		"x-stages": []any{"test"},
+		"services": map[string]any{
+			"web": map[string]any{
+				"image": "nginx",
+				"x-prebuild": []any{
+					map[string]any{"name": "Test", "commands": []any{"go test ./..."}},
+				},
+				"x-file-defaults": map[string]any{"uid": "1000"},
+			},
+		},
+	}
+	assert.NilError(t, schema.Validate(valid))
+
+	invalid := map[string]any{
+		"services": map[string]any{
+			"web": map[string]any{
+				"image":      "nginx",
+				"x-prebuild": []any{map[string]any{"name": "Test"}},
+			},
+		},
+	}
+	assert.Check(t, schema.Validate(invalid) != nil)
+	assert.NilError(t, Validate(invalid))
+}
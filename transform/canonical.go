@@ -41,6 +41,12 @@ func init() {
 	transformers["services.*.networks"] = transformStringSliceToMap
 	transformers["services.*.models"] = transformStringSliceToMap
 	transformers["services.*.volumes.*"] = transformVolumeMount
+	transformers["services.*.prebuild.*.container.volumes.*"] = transformVolumeMount
+	transformers["services.*.prebuild.*.commands.*"] = transformPrebuildCommand
+	transformers["services.*.prebuild.*.commands.*.env_file"] = transformEnvFile
+	transformers["services.*.prebuild.*.concurrency"] = transformPrebuildConcurrency
+	transformers["services.*.prebuild.*.needs.*"] = transformPrebuildNeed
+	transformers["services.*.sensitive.*.on_change"] = transformSensitiveOnChange
 	transformers["services.*.dns"] = transformStringOrList
 	transformers["services.*.devices.*"] = transformDeviceMapping
 	transformers["services.*.secrets.*"] = transformFileMount
@@ -26,11 +26,12 @@ var DefaultValues = map[tree.Path]Func{}
 func init() {
 	DefaultValues["services.*.build"] = defaultBuildContext
 	DefaultValues["services.*.secrets.*"] = defaultSecretMount
-	DefaultValues["services.*.sensitive.*"] = defaultSensitiveTarget
+	DefaultValues["services.*.sensitive.*"] = defaultSensitive
 	DefaultValues["services.*.ports.*"] = portDefaults
 	DefaultValues["services.*.deploy.resources.reservations.devices.*"] = deviceRequestDefaults
 	DefaultValues["services.*.gpus.*"] = deviceRequestDefaults
 	DefaultValues["services.*.volumes.*.bind"] = defaultVolumeBind
+	DefaultValues["services.*.prebuild.*.container.volumes.*.bind"] = defaultVolumeBind
 }
 
 // RegisterDefaultValue registers a custom transformer for the given path pattern
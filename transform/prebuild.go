@@ -0,0 +1,63 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package transform
+
+import (
+	"fmt"
+
+	"github.com/compose-spec/compose-go/v2/tree"
+)
+
+func transformPrebuildCommand(data any, p tree.Path, ignoreParseError bool) (any, error) {
+	switch v := data.(type) {
+	case map[string]any:
+		return transformMapping(v, p, ignoreParseError)
+	case string:
+		return map[string]any{
+			"name":    v,
+			"command": v,
+		}, nil
+	default:
+		return data, fmt.Errorf("%s: invalid type %T for prebuild command", p, v)
+	}
+}
+
+func transformPrebuildConcurrency(data any, p tree.Path, _ bool) (any, error) {
+	switch v := data.(type) {
+	case map[string]any:
+		return v, nil
+	case string:
+		return map[string]any{
+			"group": v,
+		}, nil
+	default:
+		return data, fmt.Errorf("%s: invalid type %T for prebuild concurrency", p, v)
+	}
+}
+
+func transformPrebuildNeed(data any, p tree.Path, _ bool) (any, error) {
+	switch v := data.(type) {
+	case map[string]any:
+		return v, nil
+	case string:
+		return map[string]any{
+			"job": v,
+		}, nil
+	default:
+		return data, fmt.Errorf("%s: invalid type %T for prebuild need", p, v)
+	}
+}
@@ -48,15 +48,31 @@ func defaultSecretMount(data any, p tree.Path, _ bool) (any, error) {
 	}
 }
 
-func defaultSensitiveTarget(data any, p tree.Path, _ bool) (any, error) {
+func defaultSensitive(data any, p tree.Path, _ bool) (any, error) {
 	switch v := data.(type) {
 	case map[string]any:
 		if _, ok := v["target"]; !ok {
 			name := p.Last()
 			v["target"] = fmt.Sprintf("/run/secrets/%s", name)
 		}
+		if _, ok := v["format"]; !ok {
+			v["format"] = "raw"
+		}
 		return v, nil
 	default:
 		return nil, fmt.Errorf("%s: unsupported type %T", p, data)
 	}
 }
+
+func transformSensitiveOnChange(data any, p tree.Path, _ bool) (any, error) {
+	switch v := data.(type) {
+	case map[string]any:
+		return v, nil
+	case string:
+		return map[string]any{
+			"action": v,
+		}, nil
+	default:
+		return data, fmt.Errorf("%s: invalid type %T for sensitive on_change", p, v)
+	}
+}
@@ -0,0 +1,109 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"os"
+	"path"
+	"slices"
+	"strings"
+)
+
+// FileArtifactKind is the service attribute a FileArtifact is declared by
+type FileArtifactKind string
+
+const (
+	FileArtifactConfig      FileArtifactKind = "configs"
+	FileArtifactSecret      FileArtifactKind = "secrets"
+	FileArtifactLocalConfig FileArtifactKind = "local_configs"
+	FileArtifactSensitive   FileArtifactKind = "sensitive"
+)
+
+// FileArtifact is a file which must exist in a service container, see ServiceConfig.FileArtifacts
+type FileArtifact struct {
+	Target string           `json:"target"`
+	UID    string           `json:"uid,omitempty"`
+	GID    string           `json:"gid,omitempty"`
+	Mode   os.FileMode      `json:"mode"`
+	Kind   FileArtifactKind `json:"kind"`
+	// Name is the entry key for local_configs and sensitive, the referenced top-level resource for configs and secrets
+	Name string `json:"name"`
+	// Source is the host file a local config is read from, empty for other kinds and inline content
+	Source string `json:"source,omitempty"`
+}
+
+// FileArtifacts lists the files service needs in its container, sorted by target: configs and secrets mounts, with
+// targets defaulting as docker compose does, local_configs and sensitive entries. Configs and secrets not setting a
+// mode are world readable, as local configs are
+func (s ServiceConfig) FileArtifacts() []FileArtifact {
+	var artifacts []FileArtifact
+	for _, c := range s.Configs {
+		artifacts = append(artifacts, FileArtifact{
+			Target: c.ContainerTarget(), UID: c.UID, GID: c.GID, Mode: c.Mode.OSFileMode(), Kind: FileArtifactConfig, Name: c.Source,
+		})
+	}
+	for _, c := range s.Secrets {
+		artifacts = append(artifacts, FileArtifact{
+			Target: c.ContainerTarget(), UID: c.UID, GID: c.GID, Mode: c.Mode.OSFileMode(), Kind: FileArtifactSecret, Name: c.Source,
+		})
+	}
+	for name, c := range s.LocalConfigs {
+		artifact := FileArtifact{
+			Target: c.Target, UID: c.UID, GID: c.GID, Mode: c.Mode.OSFileMode(), Kind: FileArtifactLocalConfig, Name: name,
+		}
+		if c.Source != "" {
+			artifact.Source = c.SourcePath()
+		}
+		artifacts = append(artifacts, artifact)
+	}
+	for name, c := range s.Sensitive {
+		target := c.Target
+		if target == "" {
+			target = "/run/secrets/" + name
+		}
+		artifacts = append(artifacts, FileArtifact{
+			Target: target, UID: c.UID, GID: c.GID, Mode: c.FileMode(), Kind: FileArtifactSensitive, Name: name,
+		})
+	}
+	slices.SortFunc(artifacts, func(a, b FileArtifact) int {
+		return strings.Compare(a.Target, b.Target)
+	})
+	return artifacts
+}
+
+// ContainerTarget is the path config is mounted to in the container, defaulting to /<source> as docker compose does
+func (c ServiceConfigObjConfig) ContainerTarget() string {
+	switch {
+	case c.Target == "":
+		return "/" + c.Source
+	case !path.IsAbs(c.Target):
+		return "/" + c.Target
+	}
+	return c.Target
+}
+
+// ContainerTarget is the path secret is mounted to in the container, relative targets being within /run/secrets as
+// docker compose does
+func (s ServiceSecretConfig) ContainerTarget() string {
+	switch {
+	case s.Target == "":
+		return "/run/secrets/" + s.Source
+	case !path.IsAbs(s.Target):
+		return path.Join("/run/secrets", s.Target)
+	}
+	return s.Target
+}
@@ -0,0 +1,57 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestServiceFileArtifacts(t *testing.T) {
+	mode := FileMode(0o440)
+	s := ServiceConfig{
+		Name: "web",
+		Configs: []ServiceConfigObjConfig{
+			{Source: "nginx"},
+			{Source: "mime", Target: "etc/mime.types", UID: "101", Mode: &mode},
+		},
+		Secrets: []ServiceSecretConfig{
+			{Source: "db_password"},
+			{Source: "tls_key", Target: "tls/key.pem"},
+		},
+		LocalConfigs: map[string]LocalConfigConfig{
+			"app":    {Source: "./app.conf", ResolvedSource: "/project/app.conf", Target: "/etc/app.conf", UID: "0", GID: "0"},
+			"inline": {Content: "debug=true", Target: "/etc/debug.conf", Mode: &mode},
+		},
+		Sensitive: map[string]SensitiveConfig{
+			"token": {GID: "1000"},
+			"env":   {Target: "/run/secrets/app.env", Mode: &mode},
+		},
+	}
+	assert.DeepEqual(t, s.FileArtifacts(), []FileArtifact{
+		{Target: "/etc/app.conf", UID: "0", GID: "0", Mode: 0o444, Kind: FileArtifactLocalConfig, Name: "app", Source: "/project/app.conf"},
+		{Target: "/etc/debug.conf", Mode: 0o440, Kind: FileArtifactLocalConfig, Name: "inline"},
+		{Target: "/etc/mime.types", UID: "101", Mode: 0o440, Kind: FileArtifactConfig, Name: "mime"},
+		{Target: "/nginx", Mode: 0o444, Kind: FileArtifactConfig, Name: "nginx"},
+		{Target: "/run/secrets/app.env", Mode: 0o440, Kind: FileArtifactSensitive, Name: "env"},
+		{Target: "/run/secrets/db_password", Mode: 0o444, Kind: FileArtifactSecret, Name: "db_password"},
+		{Target: "/run/secrets/tls/key.pem", Mode: 0o444, Kind: FileArtifactSecret, Name: "tls_key"},
+		{Target: "/run/secrets/token", GID: "1000", Mode: 0o400, Kind: FileArtifactSensitive, Name: "token"},
+	})
+	assert.Assert(t, ServiceConfig{}.FileArtifacts() == nil)
+}
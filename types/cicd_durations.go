@@ -0,0 +1,95 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"strconv"
+	"time"
+
+	"go.yaml.in/yaml/v4"
+)
+
+// DurationFormat is the canonical form cicdez durations get marshalled with, see CanonicalDurations
+type DurationFormat string
+
+const (
+	// DurationFormatGo marshals durations using Go duration notation, like `1m30s`
+	DurationFormatGo DurationFormat = "go"
+	// DurationFormatSeconds marshals durations as a number of seconds, like `90s`
+	DurationFormatSeconds DurationFormat = "seconds"
+)
+
+// CanonicalDurations makes MarshalYAML write all cicdez durations using format, so generated files are consistent
+// whatever notation was used in source files
+func CanonicalDurations(format DurationFormat) func(*marshallOptions) {
+	return func(o *marshallOptions) {
+		o.durationFormat = format
+	}
+}
+
+// Format returns d written in format, Go duration notation for an unknown format
+func (format DurationFormat) Format(d time.Duration) string {
+	if format == DurationFormatSeconds {
+		return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+	}
+	return d.String()
+}
+
+// canonicalCICDDurations rewrites, within a marshalled project, cicdez durations using format
+func canonicalCICDDurations(node *yaml.Node, format DurationFormat) {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	for _, service := range nodeValues(nodeValue(node, "services")) {
+		for _, key := range []string{"prebuild", CICDExtensionPrebuild} {
+			prebuild := nodeValue(service, key)
+			if prebuild == nil || prebuild.Kind != yaml.SequenceNode {
+				continue
+			}
+			for _, job := range prebuild.Content {
+				formatDuration(nodeValue(job, "timeout"), format)
+				commands := nodeValue(job, "commands")
+				if commands == nil || commands.Kind != yaml.SequenceNode {
+					continue
+				}
+				for _, command := range commands.Content {
+					formatDuration(nodeValue(command, "estimated_duration"), format)
+					formatDuration(nodeValue(command, "timeout"), format)
+					backoff := nodeValue(command, "retry_backoff")
+					formatDuration(nodeValue(backoff, "initial"), format)
+					formatDuration(nodeValue(backoff, "max"), format)
+				}
+			}
+		}
+		for _, key := range []string{"sensitive", CICDExtensionSensitive} {
+			for _, sensitive := range nodeValues(nodeValue(service, key)) {
+				formatDuration(nodeValue(sensitive, "ttl"), format)
+			}
+		}
+	}
+}
+
+func formatDuration(node *yaml.Node, format DurationFormat) {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return
+	}
+	d, err := time.ParseDuration(node.Value)
+	if err != nil {
+		return
+	}
+	node.Value = format.Format(d)
+}
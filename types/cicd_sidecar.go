@@ -0,0 +1,65 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"fmt"
+
+	"go.yaml.in/yaml/v4"
+)
+
+// CICDSidecarFilename is the conventional name of the file declaring cicdez attributes of a service, next to the
+// main compose file
+func CICDSidecarFilename(service string) string {
+	return fmt.Sprintf("prebuild.%s.yaml", service)
+}
+
+type cicdSidecar struct {
+	Prebuild     []PrebuildJob                `yaml:"prebuild,omitempty"`
+	LocalConfigs map[string]LocalConfigConfig `yaml:"local_configs,omitempty"`
+	Sensitive    map[string]SensitiveConfig   `yaml:"sensitive,omitempty"`
+}
+
+// ExtractCICDPerService splits project into a main compose file without cicdez attributes, and a file per service
+// declaring those, indexed by CICDSidecarFilename. Sidecars are meant to be stored next to the main file, so
+// relative paths keep resolving from project directory
+func (p *Project) ExtractCICDPerService() ([]byte, map[string][]byte, error) {
+	stripped := p.deepCopy()
+	sidecars := map[string][]byte{}
+	for _, name := range stripped.ServiceNames() {
+		s := stripped.Services[name]
+		if len(s.Prebuild) == 0 && len(s.LocalConfigs) == 0 && len(s.Sensitive) == 0 {
+			continue
+		}
+		sidecar, err := yaml.Marshal(cicdSidecar{
+			Prebuild:     s.Prebuild,
+			LocalConfigs: s.LocalConfigs,
+			Sensitive:    s.Sensitive,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		sidecars[CICDSidecarFilename(name)] = sidecar
+		s.Prebuild, s.LocalConfigs, s.Sensitive = nil, nil, nil
+		stripped.Services[name] = s
+	}
+	main, err := stripped.MarshalYAML()
+	if err != nil {
+		return nil, nil, err
+	}
+	return main, sidecars, nil
+}
@@ -33,6 +33,26 @@ type ConfigDetails struct {
 	WorkingDir  string
 	ConfigFiles []ConfigFile
 	Environment Mapping
+	// EnvironmentSources optionally tells where Environment variables are set, see loader Options.TrackEnvironmentSources
+	EnvironmentSources map[string]EnvironmentSource
+}
+
+const (
+	// EnvironmentOriginOS is the origin of variables set by the OS environment
+	EnvironmentOriginOS = "os"
+	// EnvironmentOriginEnvFile is the origin of variables set by an env file
+	EnvironmentOriginEnvFile = "env_file"
+	// EnvironmentOriginDefault is the origin of variables not set, interpolation falling back to their default value
+	EnvironmentOriginDefault = "default"
+)
+
+// EnvironmentSource is where a variable used by interpolation gets its value from. Origin is empty for variables
+// without a known source, as set programmatically
+type EnvironmentSource struct {
+	Origin string `yaml:"origin,omitempty" json:"origin,omitempty"`
+	// File and Line locate the declaration of variables set by an env file
+	File string `yaml:"file,omitempty" json:"file,omitempty"`
+	Line int    `yaml:"line,omitempty" json:"line,omitempty"`
 }
 
 // LookupEnv provides a lookup function for environment variables
@@ -106,9 +126,33 @@ type Models map[string]ModelConfig
 // Extensions is a map of custom extension
 type Extensions map[string]any
 
+// DeepCopy copies extensions into t, nested maps and sequences included so that t shares no state with e
 func (e Extensions) DeepCopy(t Extensions) {
 	for k, v := range e {
-		t[k] = v
+		t[k] = deepCopyValue(v)
+	}
+}
+
+func deepCopyValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			m[k] = deepCopyValue(e)
+		}
+		return m
+	case Extensions:
+		m := make(Extensions, len(v))
+		v.DeepCopy(m)
+		return m
+	case []any:
+		s := make([]any, len(v))
+		for i, e := range v {
+			s[i] = deepCopyValue(e)
+		}
+		return s
+	default:
+		return v
 	}
 }
 
@@ -2,46 +2,231 @@
 
 package types
 
+// deriveDeepCopyPrebuildJob recursively copies the contents of src into dst.
+func deriveDeepCopyPrebuildJob(dst, src *PrebuildJob) {
+	dst.Name = src.Name
+	dst.RunsOn = src.RunsOn
+	dst.ImagePullPolicy = src.ImagePullPolicy
+	if src.Matrix != nil {
+		dst.Matrix = make(map[string][]string, len(src.Matrix))
+		deriveDeepCopy(dst.Matrix, src.Matrix)
+	} else {
+		dst.Matrix = nil
+	}
+	if src.Needs == nil {
+		dst.Needs = nil
+	} else {
+		if dst.Needs != nil {
+			if len(src.Needs) > len(dst.Needs) {
+				if cap(dst.Needs) >= len(src.Needs) {
+					dst.Needs = (dst.Needs)[:len(src.Needs)]
+				} else {
+					dst.Needs = make([]PrebuildNeed, len(src.Needs))
+				}
+			} else if len(src.Needs) < len(dst.Needs) {
+				dst.Needs = (dst.Needs)[:len(src.Needs)]
+			}
+		} else {
+			dst.Needs = make([]PrebuildNeed, len(src.Needs))
+		}
+		deriveDeepCopy_(dst.Needs, src.Needs)
+	}
+	dst.Stage = src.Stage
+	if src.Profiles == nil {
+		dst.Profiles = nil
+	} else {
+		if dst.Profiles != nil {
+			if len(src.Profiles) > len(dst.Profiles) {
+				if cap(dst.Profiles) >= len(src.Profiles) {
+					dst.Profiles = (dst.Profiles)[:len(src.Profiles)]
+				} else {
+					dst.Profiles = make([]string, len(src.Profiles))
+				}
+			} else if len(src.Profiles) < len(dst.Profiles) {
+				dst.Profiles = (dst.Profiles)[:len(src.Profiles)]
+			}
+		} else {
+			dst.Profiles = make([]string, len(src.Profiles))
+		}
+		copy(dst.Profiles, src.Profiles)
+	}
+	dst.Skip = src.Skip
+	dst.If = src.If
+	dst.SkipReason = src.SkipReason
+	if src.WhenChanged == nil {
+		dst.WhenChanged = nil
+	} else {
+		if dst.WhenChanged != nil {
+			if len(src.WhenChanged) > len(dst.WhenChanged) {
+				if cap(dst.WhenChanged) >= len(src.WhenChanged) {
+					dst.WhenChanged = (dst.WhenChanged)[:len(src.WhenChanged)]
+				} else {
+					dst.WhenChanged = make([]string, len(src.WhenChanged))
+				}
+			} else if len(src.WhenChanged) < len(dst.WhenChanged) {
+				dst.WhenChanged = (dst.WhenChanged)[:len(src.WhenChanged)]
+			}
+		} else {
+			dst.WhenChanged = make([]string, len(src.WhenChanged))
+		}
+		copy(dst.WhenChanged, src.WhenChanged)
+	}
+	if src.RequiresEnv == nil {
+		dst.RequiresEnv = nil
+	} else {
+		if dst.RequiresEnv != nil {
+			if len(src.RequiresEnv) > len(dst.RequiresEnv) {
+				if cap(dst.RequiresEnv) >= len(src.RequiresEnv) {
+					dst.RequiresEnv = (dst.RequiresEnv)[:len(src.RequiresEnv)]
+				} else {
+					dst.RequiresEnv = make([]string, len(src.RequiresEnv))
+				}
+			} else if len(src.RequiresEnv) < len(dst.RequiresEnv) {
+				dst.RequiresEnv = (dst.RequiresEnv)[:len(src.RequiresEnv)]
+			}
+		} else {
+			dst.RequiresEnv = make([]string, len(src.RequiresEnv))
+		}
+		copy(dst.RequiresEnv, src.RequiresEnv)
+	}
+	if src.Environment != nil {
+		dst.Environment = make(map[string]*string, len(src.Environment))
+		deriveDeepCopy_1(dst.Environment, src.Environment)
+	} else {
+		dst.Environment = nil
+	}
+	if src.Timeout == nil {
+		dst.Timeout = nil
+	} else {
+		dst.Timeout = new(Duration)
+		*dst.Timeout = *src.Timeout
+	}
+	if src.Concurrency == nil {
+		dst.Concurrency = nil
+	} else {
+		dst.Concurrency = new(PrebuildConcurrency)
+		deriveDeepCopy_2(dst.Concurrency, src.Concurrency)
+	}
+	if src.Services == nil {
+		dst.Services = nil
+	} else {
+		if dst.Services != nil {
+			if len(src.Services) > len(dst.Services) {
+				if cap(dst.Services) >= len(src.Services) {
+					dst.Services = (dst.Services)[:len(src.Services)]
+				} else {
+					dst.Services = make([]string, len(src.Services))
+				}
+			} else if len(src.Services) < len(dst.Services) {
+				dst.Services = (dst.Services)[:len(src.Services)]
+			}
+		} else {
+			dst.Services = make([]string, len(src.Services))
+		}
+		copy(dst.Services, src.Services)
+	}
+	if src.Cache == nil {
+		dst.Cache = nil
+	} else {
+		dst.Cache = new(PrebuildCache)
+		deriveDeepCopy_3(dst.Cache, src.Cache)
+	}
+	if src.Container == nil {
+		dst.Container = nil
+	} else {
+		dst.Container = new(PrebuildContainer)
+		deriveDeepCopy_4(dst.Container, src.Container)
+	}
+	if src.Commands == nil {
+		dst.Commands = nil
+	} else {
+		if dst.Commands != nil {
+			if len(src.Commands) > len(dst.Commands) {
+				if cap(dst.Commands) >= len(src.Commands) {
+					dst.Commands = (dst.Commands)[:len(src.Commands)]
+				} else {
+					dst.Commands = make([]PrebuildCommand, len(src.Commands))
+				}
+			} else if len(src.Commands) < len(dst.Commands) {
+				dst.Commands = (dst.Commands)[:len(src.Commands)]
+			}
+		} else {
+			dst.Commands = make([]PrebuildCommand, len(src.Commands))
+		}
+		deriveDeepCopy_5(dst.Commands, src.Commands)
+	}
+	if src.Extensions != nil {
+		dst.Extensions = make(map[string]any, len(src.Extensions))
+		src.Extensions.DeepCopy(dst.Extensions)
+	} else {
+		dst.Extensions = nil
+	}
+}
+
 // deriveDeepCopyProject recursively copies the contents of src into dst.
 func deriveDeepCopyProject(dst, src *Project) {
 	dst.Name = src.Name
 	dst.WorkingDir = src.WorkingDir
 	if src.Services != nil {
 		dst.Services = make(map[string]ServiceConfig, len(src.Services))
-		deriveDeepCopy(dst.Services, src.Services)
+		deriveDeepCopy_6(dst.Services, src.Services)
 	} else {
 		dst.Services = nil
 	}
 	if src.Networks != nil {
 		dst.Networks = make(map[string]NetworkConfig, len(src.Networks))
-		deriveDeepCopy_(dst.Networks, src.Networks)
+		deriveDeepCopy_7(dst.Networks, src.Networks)
 	} else {
 		dst.Networks = nil
 	}
 	if src.Volumes != nil {
 		dst.Volumes = make(map[string]VolumeConfig, len(src.Volumes))
-		deriveDeepCopy_1(dst.Volumes, src.Volumes)
+		deriveDeepCopy_8(dst.Volumes, src.Volumes)
 	} else {
 		dst.Volumes = nil
 	}
 	if src.Secrets != nil {
 		dst.Secrets = make(map[string]SecretConfig, len(src.Secrets))
-		deriveDeepCopy_2(dst.Secrets, src.Secrets)
+		deriveDeepCopy_9(dst.Secrets, src.Secrets)
 	} else {
 		dst.Secrets = nil
 	}
 	if src.Configs != nil {
 		dst.Configs = make(map[string]ConfigObjConfig, len(src.Configs))
-		deriveDeepCopy_3(dst.Configs, src.Configs)
+		deriveDeepCopy_10(dst.Configs, src.Configs)
 	} else {
 		dst.Configs = nil
 	}
 	if src.Models != nil {
 		dst.Models = make(map[string]ModelConfig, len(src.Models))
-		deriveDeepCopy_4(dst.Models, src.Models)
+		deriveDeepCopy_11(dst.Models, src.Models)
 	} else {
 		dst.Models = nil
 	}
+	if src.Stages == nil {
+		dst.Stages = nil
+	} else {
+		if dst.Stages != nil {
+			if len(src.Stages) > len(dst.Stages) {
+				if cap(dst.Stages) >= len(src.Stages) {
+					dst.Stages = (dst.Stages)[:len(src.Stages)]
+				} else {
+					dst.Stages = make([]string, len(src.Stages))
+				}
+			} else if len(src.Stages) < len(dst.Stages) {
+				dst.Stages = (dst.Stages)[:len(src.Stages)]
+			}
+		} else {
+			dst.Stages = make([]string, len(src.Stages))
+		}
+		copy(dst.Stages, src.Stages)
+	}
+	if src.FileDefaults == nil {
+		dst.FileDefaults = nil
+	} else {
+		dst.FileDefaults = new(FileDefaults)
+		deriveDeepCopy_12(dst.FileDefaults, src.FileDefaults)
+	}
 	if src.Extensions != nil {
 		dst.Extensions = make(map[string]any, len(src.Extensions))
 		src.Extensions.DeepCopy(dst.Extensions)
@@ -68,13 +253,19 @@ func deriveDeepCopyProject(dst, src *Project) {
 	}
 	if src.Environment != nil {
 		dst.Environment = make(map[string]string, len(src.Environment))
-		deriveDeepCopy_5(dst.Environment, src.Environment)
+		deriveDeepCopy_13(dst.Environment, src.Environment)
 	} else {
 		dst.Environment = nil
 	}
+	if src.EnvironmentSources != nil {
+		dst.EnvironmentSources = make(map[string]EnvironmentSource, len(src.EnvironmentSources))
+		deriveDeepCopy_14(dst.EnvironmentSources, src.EnvironmentSources)
+	} else {
+		dst.EnvironmentSources = nil
+	}
 	if src.DisabledServices != nil {
 		dst.DisabledServices = make(map[string]ServiceConfig, len(src.DisabledServices))
-		deriveDeepCopy(dst.DisabledServices, src.DisabledServices)
+		deriveDeepCopy_6(dst.DisabledServices, src.DisabledServices)
 	} else {
 		dst.DisabledServices = nil
 	}
@@ -96,6 +287,30 @@ func deriveDeepCopyProject(dst, src *Project) {
 		}
 		copy(dst.Profiles, src.Profiles)
 	}
+	if src.PrebuildProfiles == nil {
+		dst.PrebuildProfiles = nil
+	} else {
+		if dst.PrebuildProfiles != nil {
+			if len(src.PrebuildProfiles) > len(dst.PrebuildProfiles) {
+				if cap(dst.PrebuildProfiles) >= len(src.PrebuildProfiles) {
+					dst.PrebuildProfiles = (dst.PrebuildProfiles)[:len(src.PrebuildProfiles)]
+				} else {
+					dst.PrebuildProfiles = make([]string, len(src.PrebuildProfiles))
+				}
+			} else if len(src.PrebuildProfiles) < len(dst.PrebuildProfiles) {
+				dst.PrebuildProfiles = (dst.PrebuildProfiles)[:len(src.PrebuildProfiles)]
+			}
+		} else {
+			dst.PrebuildProfiles = make([]string, len(src.PrebuildProfiles))
+		}
+		copy(dst.PrebuildProfiles, src.PrebuildProfiles)
+	}
+	if src.DisabledPrebuildJobs != nil {
+		dst.DisabledPrebuildJobs = make(map[string][]PrebuildJob, len(src.DisabledPrebuildJobs))
+		deriveDeepCopy_15(dst.DisabledPrebuildJobs, src.DisabledPrebuildJobs)
+	} else {
+		dst.DisabledPrebuildJobs = nil
+	}
 }
 
 // deriveDeepCopyService recursively copies the contents of src into dst.
@@ -121,7 +336,7 @@ func deriveDeepCopyService(dst, src *ServiceConfig) {
 	}
 	if src.Annotations != nil {
 		dst.Annotations = make(map[string]string, len(src.Annotations))
-		deriveDeepCopy_5(dst.Annotations, src.Annotations)
+		deriveDeepCopy_13(dst.Annotations, src.Annotations)
 	} else {
 		dst.Annotations = nil
 	}
@@ -135,7 +350,7 @@ func deriveDeepCopyService(dst, src *ServiceConfig) {
 		dst.Build = nil
 	} else {
 		dst.Build = new(BuildConfig)
-		deriveDeepCopy_6(dst.Build, src.Build)
+		deriveDeepCopy_16(dst.Build, src.Build)
 	}
 	if src.Prebuild == nil {
 		dst.Prebuild = nil
@@ -153,19 +368,19 @@ func deriveDeepCopyService(dst, src *ServiceConfig) {
 		} else {
 			dst.Prebuild = make([]PrebuildJob, len(src.Prebuild))
 		}
-		deriveDeepCopy_7(dst.Prebuild, src.Prebuild)
+		deriveDeepCopy_17(dst.Prebuild, src.Prebuild)
 	}
 	if src.Develop == nil {
 		dst.Develop = nil
 	} else {
 		dst.Develop = new(DevelopConfig)
-		deriveDeepCopy_8(dst.Develop, src.Develop)
+		deriveDeepCopy_18(dst.Develop, src.Develop)
 	}
 	if src.BlkioConfig == nil {
 		dst.BlkioConfig = nil
 	} else {
 		dst.BlkioConfig = new(BlkioConfig)
-		deriveDeepCopy_9(dst.BlkioConfig, src.BlkioConfig)
+		deriveDeepCopy_19(dst.BlkioConfig, src.BlkioConfig)
 	}
 	if src.CapAdd == nil {
 		dst.CapAdd = nil
@@ -248,11 +463,11 @@ func deriveDeepCopyService(dst, src *ServiceConfig) {
 		} else {
 			dst.Configs = make([]ServiceConfigObjConfig, len(src.Configs))
 		}
-		deriveDeepCopy_10(dst.Configs, src.Configs)
+		deriveDeepCopy_20(dst.Configs, src.Configs)
 	}
 	if src.LocalConfigs != nil {
 		dst.LocalConfigs = make(map[string]LocalConfigConfig, len(src.LocalConfigs))
-		deriveDeepCopy_11(dst.LocalConfigs, src.LocalConfigs)
+		deriveDeepCopy_21(dst.LocalConfigs, src.LocalConfigs)
 	} else {
 		dst.LocalConfigs = nil
 	}
@@ -261,11 +476,11 @@ func deriveDeepCopyService(dst, src *ServiceConfig) {
 		dst.CredentialSpec = nil
 	} else {
 		dst.CredentialSpec = new(CredentialSpecConfig)
-		deriveDeepCopy_12(dst.CredentialSpec, src.CredentialSpec)
+		deriveDeepCopy_22(dst.CredentialSpec, src.CredentialSpec)
 	}
 	if src.DependsOn != nil {
 		dst.DependsOn = make(map[string]ServiceDependency, len(src.DependsOn))
-		deriveDeepCopy_13(dst.DependsOn, src.DependsOn)
+		deriveDeepCopy_23(dst.DependsOn, src.DependsOn)
 	} else {
 		dst.DependsOn = nil
 	}
@@ -273,7 +488,7 @@ func deriveDeepCopyService(dst, src *ServiceConfig) {
 		dst.Deploy = nil
 	} else {
 		dst.Deploy = new(DeployConfig)
-		deriveDeepCopy_14(dst.Deploy, src.Deploy)
+		deriveDeepCopy_24(dst.Deploy, src.Deploy)
 	}
 	if src.DeviceCgroupRules == nil {
 		dst.DeviceCgroupRules = nil
@@ -309,7 +524,7 @@ func deriveDeepCopyService(dst, src *ServiceConfig) {
 		} else {
 			dst.Devices = make([]DeviceMapping, len(src.Devices))
 		}
-		deriveDeepCopy_15(dst.Devices, src.Devices)
+		deriveDeepCopy_25(dst.Devices, src.Devices)
 	}
 	if src.DNS == nil {
 		dst.DNS = nil
@@ -389,11 +604,11 @@ func deriveDeepCopyService(dst, src *ServiceConfig) {
 		dst.Provider = nil
 	} else {
 		dst.Provider = new(ServiceProviderConfig)
-		deriveDeepCopy_16(dst.Provider, src.Provider)
+		deriveDeepCopy_26(dst.Provider, src.Provider)
 	}
 	if src.Environment != nil {
 		dst.Environment = make(map[string]*string, len(src.Environment))
-		deriveDeepCopy_17(dst.Environment, src.Environment)
+		deriveDeepCopy_1(dst.Environment, src.Environment)
 	} else {
 		dst.Environment = nil
 	}
@@ -459,10 +674,16 @@ func deriveDeepCopyService(dst, src *ServiceConfig) {
 	}
 	if src.ExtraHosts != nil {
 		dst.ExtraHosts = make(map[string][]string, len(src.ExtraHosts))
-		deriveDeepCopy_18(dst.ExtraHosts, src.ExtraHosts)
+		deriveDeepCopy(dst.ExtraHosts, src.ExtraHosts)
 	} else {
 		dst.ExtraHosts = nil
 	}
+	if src.FileDefaults == nil {
+		dst.FileDefaults = nil
+	} else {
+		dst.FileDefaults = new(FileDefaults)
+		deriveDeepCopy_12(dst.FileDefaults, src.FileDefaults)
+	}
 	if src.GroupAdd == nil {
 		dst.GroupAdd = nil
 	} else {
@@ -497,16 +718,17 @@ func deriveDeepCopyService(dst, src *ServiceConfig) {
 		} else {
 			dst.Gpus = make([]DeviceRequest, len(src.Gpus))
 		}
-		deriveDeepCopy_19(dst.Gpus, src.Gpus)
+		deriveDeepCopy_27(dst.Gpus, src.Gpus)
 	}
 	dst.Hostname = src.Hostname
 	if src.HealthCheck == nil {
 		dst.HealthCheck = nil
 	} else {
 		dst.HealthCheck = new(HealthCheckConfig)
-		deriveDeepCopy_20(dst.HealthCheck, src.HealthCheck)
+		deriveDeepCopy_28(dst.HealthCheck, src.HealthCheck)
 	}
 	dst.Image = src.Image
+	dst.InheritPrebuild = src.InheritPrebuild
 	if src.Init == nil {
 		dst.Init = nil
 	} else {
@@ -517,7 +739,7 @@ func deriveDeepCopyService(dst, src *ServiceConfig) {
 	dst.Isolation = src.Isolation
 	if src.Labels != nil {
 		dst.Labels = make(map[string]string, len(src.Labels))
-		deriveDeepCopy_5(dst.Labels, src.Labels)
+		deriveDeepCopy_13(dst.Labels, src.Labels)
 	} else {
 		dst.Labels = nil
 	}
@@ -541,7 +763,7 @@ func deriveDeepCopyService(dst, src *ServiceConfig) {
 	}
 	if src.CustomLabels != nil {
 		dst.CustomLabels = make(map[string]string, len(src.CustomLabels))
-		deriveDeepCopy_5(dst.CustomLabels, src.CustomLabels)
+		deriveDeepCopy_13(dst.CustomLabels, src.CustomLabels)
 	} else {
 		dst.CustomLabels = nil
 	}
@@ -567,12 +789,12 @@ func deriveDeepCopyService(dst, src *ServiceConfig) {
 		dst.Logging = nil
 	} else {
 		dst.Logging = new(LoggingConfig)
-		deriveDeepCopy_21(dst.Logging, src.Logging)
+		deriveDeepCopy_29(dst.Logging, src.Logging)
 	}
 	dst.LogDriver = src.LogDriver
 	if src.LogOpt != nil {
 		dst.LogOpt = make(map[string]string, len(src.LogOpt))
-		deriveDeepCopy_5(dst.LogOpt, src.LogOpt)
+		deriveDeepCopy_13(dst.LogOpt, src.LogOpt)
 	} else {
 		dst.LogOpt = nil
 	}
@@ -583,7 +805,7 @@ func deriveDeepCopyService(dst, src *ServiceConfig) {
 	dst.MacAddress = src.MacAddress
 	if src.Models != nil {
 		dst.Models = make(map[string]*ServiceModelConfig, len(src.Models))
-		deriveDeepCopy_22(dst.Models, src.Models)
+		deriveDeepCopy_30(dst.Models, src.Models)
 	} else {
 		dst.Models = nil
 	}
@@ -591,7 +813,7 @@ func deriveDeepCopyService(dst, src *ServiceConfig) {
 	dst.NetworkMode = src.NetworkMode
 	if src.Networks != nil {
 		dst.Networks = make(map[string]*ServiceNetworkConfig, len(src.Networks))
-		deriveDeepCopy_23(dst.Networks, src.Networks)
+		deriveDeepCopy_31(dst.Networks, src.Networks)
 	} else {
 		dst.Networks = nil
 	}
@@ -616,7 +838,7 @@ func deriveDeepCopyService(dst, src *ServiceConfig) {
 		} else {
 			dst.Ports = make([]ServicePortConfig, len(src.Ports))
 		}
-		deriveDeepCopy_24(dst.Ports, src.Ports)
+		deriveDeepCopy_32(dst.Ports, src.Ports)
 	}
 	dst.Privileged = src.Privileged
 	dst.PullPolicy = src.PullPolicy
@@ -645,11 +867,11 @@ func deriveDeepCopyService(dst, src *ServiceConfig) {
 		} else {
 			dst.Secrets = make([]ServiceSecretConfig, len(src.Secrets))
 		}
-		deriveDeepCopy_25(dst.Secrets, src.Secrets)
+		deriveDeepCopy_33(dst.Secrets, src.Secrets)
 	}
 	if src.Sensitive != nil {
 		dst.Sensitive = make(map[string]SensitiveConfig, len(src.Sensitive))
-		deriveDeepCopy_26(dst.Sensitive, src.Sensitive)
+		deriveDeepCopy_34(dst.Sensitive, src.Sensitive)
 	} else {
 		dst.Sensitive = nil
 	}
@@ -682,13 +904,13 @@ func deriveDeepCopyService(dst, src *ServiceConfig) {
 	dst.StopSignal = src.StopSignal
 	if src.StorageOpt != nil {
 		dst.StorageOpt = make(map[string]string, len(src.StorageOpt))
-		deriveDeepCopy_5(dst.StorageOpt, src.StorageOpt)
+		deriveDeepCopy_13(dst.StorageOpt, src.StorageOpt)
 	} else {
 		dst.StorageOpt = nil
 	}
 	if src.Sysctls != nil {
 		dst.Sysctls = make(map[string]string, len(src.Sysctls))
-		deriveDeepCopy_5(dst.Sysctls, src.Sysctls)
+		deriveDeepCopy_13(dst.Sysctls, src.Sysctls)
 	} else {
 		dst.Sysctls = nil
 	}
@@ -713,7 +935,7 @@ func deriveDeepCopyService(dst, src *ServiceConfig) {
 	dst.Tty = src.Tty
 	if src.Ulimits != nil {
 		dst.Ulimits = make(map[string]*UlimitsConfig, len(src.Ulimits))
-		deriveDeepCopy_27(dst.Ulimits, src.Ulimits)
+		deriveDeepCopy_35(dst.Ulimits, src.Ulimits)
 	} else {
 		dst.Ulimits = nil
 	}
@@ -738,7 +960,7 @@ func deriveDeepCopyService(dst, src *ServiceConfig) {
 		} else {
 			dst.Volumes = make([]ServiceVolumeConfig, len(src.Volumes))
 		}
-		deriveDeepCopy_28(dst.Volumes, src.Volumes)
+		deriveDeepCopy_36(dst.Volumes, src.Volumes)
 	}
 	if src.VolumesFrom == nil {
 		dst.VolumesFrom = nil
@@ -775,7 +997,7 @@ func deriveDeepCopyService(dst, src *ServiceConfig) {
 		} else {
 			dst.PostStart = make([]ServiceHook, len(src.PostStart))
 		}
-		deriveDeepCopy_29(dst.PostStart, src.PostStart)
+		deriveDeepCopy_37(dst.PostStart, src.PostStart)
 	}
 	if src.PreStop == nil {
 		dst.PreStop = nil
@@ -793,7 +1015,7 @@ func deriveDeepCopyService(dst, src *ServiceConfig) {
 		} else {
 			dst.PreStop = make([]ServiceHook, len(src.PreStop))
 		}
-		deriveDeepCopy_29(dst.PreStop, src.PreStop)
+		deriveDeepCopy_37(dst.PreStop, src.PreStop)
 	}
 	if src.Extensions != nil {
 		dst.Extensions = make(map[string]any, len(src.Extensions))
@@ -804,7 +1026,167 @@ func deriveDeepCopyService(dst, src *ServiceConfig) {
 }
 
 // deriveDeepCopy recursively copies the contents of src into dst.
-func deriveDeepCopy(dst, src map[string]ServiceConfig) {
+func deriveDeepCopy(dst, src map[string][]string) {
+	for src_key, src_value := range src {
+		if src_value == nil {
+			dst[src_key] = nil
+		}
+		if src_value == nil {
+			dst[src_key] = nil
+		} else {
+			if dst[src_key] != nil {
+				if len(src_value) > len(dst[src_key]) {
+					if cap(dst[src_key]) >= len(src_value) {
+						dst[src_key] = (dst[src_key])[:len(src_value)]
+					} else {
+						dst[src_key] = make([]string, len(src_value))
+					}
+				} else if len(src_value) < len(dst[src_key]) {
+					dst[src_key] = (dst[src_key])[:len(src_value)]
+				}
+			} else {
+				dst[src_key] = make([]string, len(src_value))
+			}
+			copy(dst[src_key], src_value)
+		}
+	}
+}
+
+// deriveDeepCopy_ recursively copies the contents of src into dst.
+func deriveDeepCopy_(dst, src []PrebuildNeed) {
+	for src_i, src_value := range src {
+		func() {
+			field := new(PrebuildNeed)
+			deriveDeepCopy_38(field, &src_value)
+			dst[src_i] = *field
+		}()
+	}
+}
+
+// deriveDeepCopy_1 recursively copies the contents of src into dst.
+func deriveDeepCopy_1(dst, src map[string]*string) {
+	for src_key, src_value := range src {
+		if src_value == nil {
+			dst[src_key] = nil
+		}
+		if src_value == nil {
+			dst[src_key] = nil
+		} else {
+			dst[src_key] = new(string)
+			*dst[src_key] = *src_value
+		}
+	}
+}
+
+// deriveDeepCopy_2 recursively copies the contents of src into dst.
+func deriveDeepCopy_2(dst, src *PrebuildConcurrency) {
+	dst.Group = src.Group
+	dst.CancelInProgress = src.CancelInProgress
+	if src.Extensions != nil {
+		dst.Extensions = make(map[string]any, len(src.Extensions))
+		src.Extensions.DeepCopy(dst.Extensions)
+	} else {
+		dst.Extensions = nil
+	}
+}
+
+// deriveDeepCopy_3 recursively copies the contents of src into dst.
+func deriveDeepCopy_3(dst, src *PrebuildCache) {
+	dst.Key = src.Key
+	if src.Paths == nil {
+		dst.Paths = nil
+	} else {
+		if dst.Paths != nil {
+			if len(src.Paths) > len(dst.Paths) {
+				if cap(dst.Paths) >= len(src.Paths) {
+					dst.Paths = (dst.Paths)[:len(src.Paths)]
+				} else {
+					dst.Paths = make([]string, len(src.Paths))
+				}
+			} else if len(src.Paths) < len(dst.Paths) {
+				dst.Paths = (dst.Paths)[:len(src.Paths)]
+			}
+		} else {
+			dst.Paths = make([]string, len(src.Paths))
+		}
+		copy(dst.Paths, src.Paths)
+	}
+	if src.Extensions != nil {
+		dst.Extensions = make(map[string]any, len(src.Extensions))
+		src.Extensions.DeepCopy(dst.Extensions)
+	} else {
+		dst.Extensions = nil
+	}
+}
+
+// deriveDeepCopy_4 recursively copies the contents of src into dst.
+func deriveDeepCopy_4(dst, src *PrebuildContainer) {
+	dst.Image = src.Image
+	if src.Volumes == nil {
+		dst.Volumes = nil
+	} else {
+		if dst.Volumes != nil {
+			if len(src.Volumes) > len(dst.Volumes) {
+				if cap(dst.Volumes) >= len(src.Volumes) {
+					dst.Volumes = (dst.Volumes)[:len(src.Volumes)]
+				} else {
+					dst.Volumes = make([]ServiceVolumeConfig, len(src.Volumes))
+				}
+			} else if len(src.Volumes) < len(dst.Volumes) {
+				dst.Volumes = (dst.Volumes)[:len(src.Volumes)]
+			}
+		} else {
+			dst.Volumes = make([]ServiceVolumeConfig, len(src.Volumes))
+		}
+		deriveDeepCopy_36(dst.Volumes, src.Volumes)
+	}
+	if src.Environment != nil {
+		dst.Environment = make(map[string]*string, len(src.Environment))
+		deriveDeepCopy_1(dst.Environment, src.Environment)
+	} else {
+		dst.Environment = nil
+	}
+	dst.WorkingDir = src.WorkingDir
+	dst.User = src.User
+	if src.Networks == nil {
+		dst.Networks = nil
+	} else {
+		if dst.Networks != nil {
+			if len(src.Networks) > len(dst.Networks) {
+				if cap(dst.Networks) >= len(src.Networks) {
+					dst.Networks = (dst.Networks)[:len(src.Networks)]
+				} else {
+					dst.Networks = make([]string, len(src.Networks))
+				}
+			} else if len(src.Networks) < len(dst.Networks) {
+				dst.Networks = (dst.Networks)[:len(src.Networks)]
+			}
+		} else {
+			dst.Networks = make([]string, len(src.Networks))
+		}
+		copy(dst.Networks, src.Networks)
+	}
+	if src.Extensions != nil {
+		dst.Extensions = make(map[string]any, len(src.Extensions))
+		src.Extensions.DeepCopy(dst.Extensions)
+	} else {
+		dst.Extensions = nil
+	}
+}
+
+// deriveDeepCopy_5 recursively copies the contents of src into dst.
+func deriveDeepCopy_5(dst, src []PrebuildCommand) {
+	for src_i, src_value := range src {
+		func() {
+			field := new(PrebuildCommand)
+			deriveDeepCopy_39(field, &src_value)
+			dst[src_i] = *field
+		}()
+	}
+}
+
+// deriveDeepCopy_6 recursively copies the contents of src into dst.
+func deriveDeepCopy_6(dst, src map[string]ServiceConfig) {
 	for src_key, src_value := range src {
 		func() {
 			field := new(ServiceConfig)
@@ -814,70 +1196,122 @@ func deriveDeepCopy(dst, src map[string]ServiceConfig) {
 	}
 }
 
-// deriveDeepCopy_ recursively copies the contents of src into dst.
-func deriveDeepCopy_(dst, src map[string]NetworkConfig) {
+// deriveDeepCopy_7 recursively copies the contents of src into dst.
+func deriveDeepCopy_7(dst, src map[string]NetworkConfig) {
 	for src_key, src_value := range src {
 		func() {
 			field := new(NetworkConfig)
-			deriveDeepCopy_30(field, &src_value)
+			deriveDeepCopy_40(field, &src_value)
 			dst[src_key] = *field
 		}()
 	}
 }
 
-// deriveDeepCopy_1 recursively copies the contents of src into dst.
-func deriveDeepCopy_1(dst, src map[string]VolumeConfig) {
+// deriveDeepCopy_8 recursively copies the contents of src into dst.
+func deriveDeepCopy_8(dst, src map[string]VolumeConfig) {
 	for src_key, src_value := range src {
 		func() {
 			field := new(VolumeConfig)
-			deriveDeepCopy_31(field, &src_value)
+			deriveDeepCopy_41(field, &src_value)
 			dst[src_key] = *field
 		}()
 	}
 }
 
-// deriveDeepCopy_2 recursively copies the contents of src into dst.
-func deriveDeepCopy_2(dst, src map[string]SecretConfig) {
+// deriveDeepCopy_9 recursively copies the contents of src into dst.
+func deriveDeepCopy_9(dst, src map[string]SecretConfig) {
 	for src_key, src_value := range src {
 		func() {
 			field := new(SecretConfig)
-			deriveDeepCopy_32(field, &src_value)
+			deriveDeepCopy_42(field, &src_value)
 			dst[src_key] = *field
 		}()
 	}
 }
 
-// deriveDeepCopy_3 recursively copies the contents of src into dst.
-func deriveDeepCopy_3(dst, src map[string]ConfigObjConfig) {
+// deriveDeepCopy_10 recursively copies the contents of src into dst.
+func deriveDeepCopy_10(dst, src map[string]ConfigObjConfig) {
 	for src_key, src_value := range src {
 		func() {
 			field := new(ConfigObjConfig)
-			deriveDeepCopy_33(field, &src_value)
+			deriveDeepCopy_43(field, &src_value)
 			dst[src_key] = *field
 		}()
 	}
 }
 
-// deriveDeepCopy_4 recursively copies the contents of src into dst.
-func deriveDeepCopy_4(dst, src map[string]ModelConfig) {
+// deriveDeepCopy_11 recursively copies the contents of src into dst.
+func deriveDeepCopy_11(dst, src map[string]ModelConfig) {
 	for src_key, src_value := range src {
 		func() {
 			field := new(ModelConfig)
-			deriveDeepCopy_34(field, &src_value)
+			deriveDeepCopy_44(field, &src_value)
 			dst[src_key] = *field
 		}()
 	}
 }
 
-// deriveDeepCopy_5 recursively copies the contents of src into dst.
-func deriveDeepCopy_5(dst, src map[string]string) {
+// deriveDeepCopy_12 recursively copies the contents of src into dst.
+func deriveDeepCopy_12(dst, src *FileDefaults) {
+	dst.UID = src.UID
+	dst.GID = src.GID
+	if src.Mode == nil {
+		dst.Mode = nil
+	} else {
+		dst.Mode = new(FileMode)
+		*dst.Mode = *src.Mode
+	}
+	if src.Extensions != nil {
+		dst.Extensions = make(map[string]any, len(src.Extensions))
+		src.Extensions.DeepCopy(dst.Extensions)
+	} else {
+		dst.Extensions = nil
+	}
+}
+
+// deriveDeepCopy_13 recursively copies the contents of src into dst.
+func deriveDeepCopy_13(dst, src map[string]string) {
 	for src_key, src_value := range src {
 		dst[src_key] = src_value
 	}
 }
 
-// deriveDeepCopy_6 recursively copies the contents of src into dst.
-func deriveDeepCopy_6(dst, src *BuildConfig) {
+// deriveDeepCopy_14 recursively copies the contents of src into dst.
+func deriveDeepCopy_14(dst, src map[string]EnvironmentSource) {
+	for src_key, src_value := range src {
+		dst[src_key] = src_value
+	}
+}
+
+// deriveDeepCopy_15 recursively copies the contents of src into dst.
+func deriveDeepCopy_15(dst, src map[string][]PrebuildJob) {
+	for src_key, src_value := range src {
+		if src_value == nil {
+			dst[src_key] = nil
+		}
+		if src_value == nil {
+			dst[src_key] = nil
+		} else {
+			if dst[src_key] != nil {
+				if len(src_value) > len(dst[src_key]) {
+					if cap(dst[src_key]) >= len(src_value) {
+						dst[src_key] = (dst[src_key])[:len(src_value)]
+					} else {
+						dst[src_key] = make([]PrebuildJob, len(src_value))
+					}
+				} else if len(src_value) < len(dst[src_key]) {
+					dst[src_key] = (dst[src_key])[:len(src_value)]
+				}
+			} else {
+				dst[src_key] = make([]PrebuildJob, len(src_value))
+			}
+			deriveDeepCopy_17(dst[src_key], src_value)
+		}
+	}
+}
+
+// deriveDeepCopy_16 recursively copies the contents of src into dst.
+func deriveDeepCopy_16(dst, src *BuildConfig) {
 	dst.Context = src.Context
 	dst.Dockerfile = src.Dockerfile
 	dst.DockerfileInline = src.DockerfileInline
@@ -901,7 +1335,7 @@ func deriveDeepCopy_6(dst, src *BuildConfig) {
 	}
 	if src.Args != nil {
 		dst.Args = make(map[string]*string, len(src.Args))
-		deriveDeepCopy_17(dst.Args, src.Args)
+		deriveDeepCopy_1(dst.Args, src.Args)
 	} else {
 		dst.Args = nil
 	}
@@ -927,7 +1361,7 @@ func deriveDeepCopy_6(dst, src *BuildConfig) {
 	}
 	if src.Labels != nil {
 		dst.Labels = make(map[string]string, len(src.Labels))
-		deriveDeepCopy_5(dst.Labels, src.Labels)
+		deriveDeepCopy_13(dst.Labels, src.Labels)
 	} else {
 		dst.Labels = nil
 	}
@@ -988,14 +1422,14 @@ func deriveDeepCopy_6(dst, src *BuildConfig) {
 	}
 	if src.AdditionalContexts != nil {
 		dst.AdditionalContexts = make(map[string]string, len(src.AdditionalContexts))
-		deriveDeepCopy_5(dst.AdditionalContexts, src.AdditionalContexts)
+		deriveDeepCopy_13(dst.AdditionalContexts, src.AdditionalContexts)
 	} else {
 		dst.AdditionalContexts = nil
 	}
 	dst.Pull = src.Pull
 	if src.ExtraHosts != nil {
 		dst.ExtraHosts = make(map[string][]string, len(src.ExtraHosts))
-		deriveDeepCopy_18(dst.ExtraHosts, src.ExtraHosts)
+		deriveDeepCopy(dst.ExtraHosts, src.ExtraHosts)
 	} else {
 		dst.ExtraHosts = nil
 	}
@@ -1018,7 +1452,7 @@ func deriveDeepCopy_6(dst, src *BuildConfig) {
 		} else {
 			dst.Secrets = make([]ServiceSecretConfig, len(src.Secrets))
 		}
-		deriveDeepCopy_25(dst.Secrets, src.Secrets)
+		deriveDeepCopy_33(dst.Secrets, src.Secrets)
 	}
 	dst.ShmSize = src.ShmSize
 	if src.Tags == nil {
@@ -1041,7 +1475,7 @@ func deriveDeepCopy_6(dst, src *BuildConfig) {
 	}
 	if src.Ulimits != nil {
 		dst.Ulimits = make(map[string]*UlimitsConfig, len(src.Ulimits))
-		deriveDeepCopy_27(dst.Ulimits, src.Ulimits)
+		deriveDeepCopy_35(dst.Ulimits, src.Ulimits)
 	} else {
 		dst.Ulimits = nil
 	}
@@ -1072,19 +1506,19 @@ func deriveDeepCopy_6(dst, src *BuildConfig) {
 	}
 }
 
-// deriveDeepCopy_7 recursively copies the contents of src into dst.
-func deriveDeepCopy_7(dst, src []PrebuildJob) {
+// deriveDeepCopy_17 recursively copies the contents of src into dst.
+func deriveDeepCopy_17(dst, src []PrebuildJob) {
 	for src_i, src_value := range src {
 		func() {
 			field := new(PrebuildJob)
-			deriveDeepCopy_35(field, &src_value)
+			deriveDeepCopyPrebuildJob(field, &src_value)
 			dst[src_i] = *field
 		}()
 	}
 }
 
-// deriveDeepCopy_8 recursively copies the contents of src into dst.
-func deriveDeepCopy_8(dst, src *DevelopConfig) {
+// deriveDeepCopy_18 recursively copies the contents of src into dst.
+func deriveDeepCopy_18(dst, src *DevelopConfig) {
 	if src.Watch == nil {
 		dst.Watch = nil
 	} else {
@@ -1101,7 +1535,7 @@ func deriveDeepCopy_8(dst, src *DevelopConfig) {
 		} else {
 			dst.Watch = make([]Trigger, len(src.Watch))
 		}
-		deriveDeepCopy_36(dst.Watch, src.Watch)
+		deriveDeepCopy_45(dst.Watch, src.Watch)
 	}
 	if src.Extensions != nil {
 		dst.Extensions = make(map[string]any, len(src.Extensions))
@@ -1111,8 +1545,8 @@ func deriveDeepCopy_8(dst, src *DevelopConfig) {
 	}
 }
 
-// deriveDeepCopy_9 recursively copies the contents of src into dst.
-func deriveDeepCopy_9(dst, src *BlkioConfig) {
+// deriveDeepCopy_19 recursively copies the contents of src into dst.
+func deriveDeepCopy_19(dst, src *BlkioConfig) {
 	dst.Weight = src.Weight
 	if src.WeightDevice == nil {
 		dst.WeightDevice = nil
@@ -1130,7 +1564,7 @@ func deriveDeepCopy_9(dst, src *BlkioConfig) {
 		} else {
 			dst.WeightDevice = make([]WeightDevice, len(src.WeightDevice))
 		}
-		deriveDeepCopy_37(dst.WeightDevice, src.WeightDevice)
+		deriveDeepCopy_46(dst.WeightDevice, src.WeightDevice)
 	}
 	if src.DeviceReadBps == nil {
 		dst.DeviceReadBps = nil
@@ -1148,7 +1582,7 @@ func deriveDeepCopy_9(dst, src *BlkioConfig) {
 		} else {
 			dst.DeviceReadBps = make([]ThrottleDevice, len(src.DeviceReadBps))
 		}
-		deriveDeepCopy_38(dst.DeviceReadBps, src.DeviceReadBps)
+		deriveDeepCopy_47(dst.DeviceReadBps, src.DeviceReadBps)
 	}
 	if src.DeviceReadIOps == nil {
 		dst.DeviceReadIOps = nil
@@ -1166,7 +1600,7 @@ func deriveDeepCopy_9(dst, src *BlkioConfig) {
 		} else {
 			dst.DeviceReadIOps = make([]ThrottleDevice, len(src.DeviceReadIOps))
 		}
-		deriveDeepCopy_38(dst.DeviceReadIOps, src.DeviceReadIOps)
+		deriveDeepCopy_47(dst.DeviceReadIOps, src.DeviceReadIOps)
 	}
 	if src.DeviceWriteBps == nil {
 		dst.DeviceWriteBps = nil
@@ -1184,7 +1618,7 @@ func deriveDeepCopy_9(dst, src *BlkioConfig) {
 		} else {
 			dst.DeviceWriteBps = make([]ThrottleDevice, len(src.DeviceWriteBps))
 		}
-		deriveDeepCopy_38(dst.DeviceWriteBps, src.DeviceWriteBps)
+		deriveDeepCopy_47(dst.DeviceWriteBps, src.DeviceWriteBps)
 	}
 	if src.DeviceWriteIOps == nil {
 		dst.DeviceWriteIOps = nil
@@ -1202,7 +1636,7 @@ func deriveDeepCopy_9(dst, src *BlkioConfig) {
 		} else {
 			dst.DeviceWriteIOps = make([]ThrottleDevice, len(src.DeviceWriteIOps))
 		}
-		deriveDeepCopy_38(dst.DeviceWriteIOps, src.DeviceWriteIOps)
+		deriveDeepCopy_47(dst.DeviceWriteIOps, src.DeviceWriteIOps)
 	}
 	if src.Extensions != nil {
 		dst.Extensions = make(map[string]any, len(src.Extensions))
@@ -1212,30 +1646,30 @@ func deriveDeepCopy_9(dst, src *BlkioConfig) {
 	}
 }
 
-// deriveDeepCopy_10 recursively copies the contents of src into dst.
-func deriveDeepCopy_10(dst, src []ServiceConfigObjConfig) {
+// deriveDeepCopy_20 recursively copies the contents of src into dst.
+func deriveDeepCopy_20(dst, src []ServiceConfigObjConfig) {
 	for src_i, src_value := range src {
 		func() {
 			field := new(ServiceConfigObjConfig)
-			deriveDeepCopy_39(field, &src_value)
+			deriveDeepCopy_48(field, &src_value)
 			dst[src_i] = *field
 		}()
 	}
 }
 
-// deriveDeepCopy_11 recursively copies the contents of src into dst.
-func deriveDeepCopy_11(dst, src map[string]LocalConfigConfig) {
+// deriveDeepCopy_21 recursively copies the contents of src into dst.
+func deriveDeepCopy_21(dst, src map[string]LocalConfigConfig) {
 	for src_key, src_value := range src {
 		func() {
 			field := new(LocalConfigConfig)
-			deriveDeepCopy_40(field, &src_value)
+			deriveDeepCopy_49(field, &src_value)
 			dst[src_key] = *field
 		}()
 	}
 }
 
-// deriveDeepCopy_12 recursively copies the contents of src into dst.
-func deriveDeepCopy_12(dst, src *CredentialSpecConfig) {
+// deriveDeepCopy_22 recursively copies the contents of src into dst.
+func deriveDeepCopy_22(dst, src *CredentialSpecConfig) {
 	dst.Config = src.Config
 	dst.File = src.File
 	dst.Registry = src.Registry
@@ -1247,19 +1681,19 @@ func deriveDeepCopy_12(dst, src *CredentialSpecConfig) {
 	}
 }
 
-// deriveDeepCopy_13 recursively copies the contents of src into dst.
-func deriveDeepCopy_13(dst, src map[string]ServiceDependency) {
+// deriveDeepCopy_23 recursively copies the contents of src into dst.
+func deriveDeepCopy_23(dst, src map[string]ServiceDependency) {
 	for src_key, src_value := range src {
 		func() {
 			field := new(ServiceDependency)
-			deriveDeepCopy_41(field, &src_value)
+			deriveDeepCopy_50(field, &src_value)
 			dst[src_key] = *field
 		}()
 	}
 }
 
-// deriveDeepCopy_14 recursively copies the contents of src into dst.
-func deriveDeepCopy_14(dst, src *DeployConfig) {
+// deriveDeepCopy_24 recursively copies the contents of src into dst.
+func deriveDeepCopy_24(dst, src *DeployConfig) {
 	dst.Mode = src.Mode
 	if src.Replicas == nil {
 		dst.Replicas = nil
@@ -1269,7 +1703,7 @@ func deriveDeepCopy_14(dst, src *DeployConfig) {
 	}
 	if src.Labels != nil {
 		dst.Labels = make(map[string]string, len(src.Labels))
-		deriveDeepCopy_5(dst.Labels, src.Labels)
+		deriveDeepCopy_13(dst.Labels, src.Labels)
 	} else {
 		dst.Labels = nil
 	}
@@ -1277,28 +1711,28 @@ func deriveDeepCopy_14(dst, src *DeployConfig) {
 		dst.UpdateConfig = nil
 	} else {
 		dst.UpdateConfig = new(UpdateConfig)
-		deriveDeepCopy_42(dst.UpdateConfig, src.UpdateConfig)
+		deriveDeepCopy_51(dst.UpdateConfig, src.UpdateConfig)
 	}
 	if src.RollbackConfig == nil {
 		dst.RollbackConfig = nil
 	} else {
 		dst.RollbackConfig = new(UpdateConfig)
-		deriveDeepCopy_42(dst.RollbackConfig, src.RollbackConfig)
+		deriveDeepCopy_51(dst.RollbackConfig, src.RollbackConfig)
 	}
 	func() {
 		field := new(Resources)
-		deriveDeepCopy_43(field, &src.Resources)
+		deriveDeepCopy_52(field, &src.Resources)
 		dst.Resources = *field
 	}()
 	if src.RestartPolicy == nil {
 		dst.RestartPolicy = nil
 	} else {
 		dst.RestartPolicy = new(RestartPolicy)
-		deriveDeepCopy_44(dst.RestartPolicy, src.RestartPolicy)
+		deriveDeepCopy_53(dst.RestartPolicy, src.RestartPolicy)
 	}
 	func() {
 		field := new(Placement)
-		deriveDeepCopy_45(field, &src.Placement)
+		deriveDeepCopy_54(field, &src.Placement)
 		dst.Placement = *field
 	}()
 	dst.EndpointMode = src.EndpointMode
@@ -1310,23 +1744,23 @@ func deriveDeepCopy_14(dst, src *DeployConfig) {
 	}
 }
 
-// deriveDeepCopy_15 recursively copies the contents of src into dst.
-func deriveDeepCopy_15(dst, src []DeviceMapping) {
+// deriveDeepCopy_25 recursively copies the contents of src into dst.
+func deriveDeepCopy_25(dst, src []DeviceMapping) {
 	for src_i, src_value := range src {
 		func() {
 			field := new(DeviceMapping)
-			deriveDeepCopy_46(field, &src_value)
+			deriveDeepCopy_55(field, &src_value)
 			dst[src_i] = *field
 		}()
 	}
 }
 
-// deriveDeepCopy_16 recursively copies the contents of src into dst.
-func deriveDeepCopy_16(dst, src *ServiceProviderConfig) {
+// deriveDeepCopy_26 recursively copies the contents of src into dst.
+func deriveDeepCopy_26(dst, src *ServiceProviderConfig) {
 	dst.Type = src.Type
 	if src.Options != nil {
 		dst.Options = make(map[string][]string, len(src.Options))
-		deriveDeepCopy_18(dst.Options, src.Options)
+		deriveDeepCopy(dst.Options, src.Options)
 	} else {
 		dst.Options = nil
 	}
@@ -1338,61 +1772,19 @@ func deriveDeepCopy_16(dst, src *ServiceProviderConfig) {
 	}
 }
 
-// deriveDeepCopy_17 recursively copies the contents of src into dst.
-func deriveDeepCopy_17(dst, src map[string]*string) {
-	for src_key, src_value := range src {
-		if src_value == nil {
-			dst[src_key] = nil
-		}
-		if src_value == nil {
-			dst[src_key] = nil
-		} else {
-			dst[src_key] = new(string)
-			*dst[src_key] = *src_value
-		}
-	}
-}
-
-// deriveDeepCopy_18 recursively copies the contents of src into dst.
-func deriveDeepCopy_18(dst, src map[string][]string) {
-	for src_key, src_value := range src {
-		if src_value == nil {
-			dst[src_key] = nil
-		}
-		if src_value == nil {
-			dst[src_key] = nil
-		} else {
-			if dst[src_key] != nil {
-				if len(src_value) > len(dst[src_key]) {
-					if cap(dst[src_key]) >= len(src_value) {
-						dst[src_key] = (dst[src_key])[:len(src_value)]
-					} else {
-						dst[src_key] = make([]string, len(src_value))
-					}
-				} else if len(src_value) < len(dst[src_key]) {
-					dst[src_key] = (dst[src_key])[:len(src_value)]
-				}
-			} else {
-				dst[src_key] = make([]string, len(src_value))
-			}
-			copy(dst[src_key], src_value)
-		}
-	}
-}
-
-// deriveDeepCopy_19 recursively copies the contents of src into dst.
-func deriveDeepCopy_19(dst, src []DeviceRequest) {
+// deriveDeepCopy_27 recursively copies the contents of src into dst.
+func deriveDeepCopy_27(dst, src []DeviceRequest) {
 	for src_i, src_value := range src {
 		func() {
 			field := new(DeviceRequest)
-			deriveDeepCopy_47(field, &src_value)
+			deriveDeepCopy_56(field, &src_value)
 			dst[src_i] = *field
 		}()
 	}
 }
 
-// deriveDeepCopy_20 recursively copies the contents of src into dst.
-func deriveDeepCopy_20(dst, src *HealthCheckConfig) {
+// deriveDeepCopy_28 recursively copies the contents of src into dst.
+func deriveDeepCopy_28(dst, src *HealthCheckConfig) {
 	if src.Test == nil {
 		dst.Test = nil
 	} else {
@@ -1450,12 +1842,12 @@ func deriveDeepCopy_20(dst, src *HealthCheckConfig) {
 	}
 }
 
-// deriveDeepCopy_21 recursively copies the contents of src into dst.
-func deriveDeepCopy_21(dst, src *LoggingConfig) {
+// deriveDeepCopy_29 recursively copies the contents of src into dst.
+func deriveDeepCopy_29(dst, src *LoggingConfig) {
 	dst.Driver = src.Driver
 	if src.Options != nil {
 		dst.Options = make(map[string]string, len(src.Options))
-		deriveDeepCopy_5(dst.Options, src.Options)
+		deriveDeepCopy_13(dst.Options, src.Options)
 	} else {
 		dst.Options = nil
 	}
@@ -1467,8 +1859,8 @@ func deriveDeepCopy_21(dst, src *LoggingConfig) {
 	}
 }
 
-// deriveDeepCopy_22 recursively copies the contents of src into dst.
-func deriveDeepCopy_22(dst, src map[string]*ServiceModelConfig) {
+// deriveDeepCopy_30 recursively copies the contents of src into dst.
+func deriveDeepCopy_30(dst, src map[string]*ServiceModelConfig) {
 	for src_key, src_value := range src {
 		if src_value == nil {
 			dst[src_key] = nil
@@ -1477,13 +1869,61 @@ func deriveDeepCopy_22(dst, src map[string]*ServiceModelConfig) {
 			dst[src_key] = nil
 		} else {
 			dst[src_key] = new(ServiceModelConfig)
-			deriveDeepCopy_48(dst[src_key], src_value)
+			deriveDeepCopy_57(dst[src_key], src_value)
 		}
 	}
 }
 
-// deriveDeepCopy_23 recursively copies the contents of src into dst.
-func deriveDeepCopy_23(dst, src map[string]*ServiceNetworkConfig) {
+// deriveDeepCopy_31 recursively copies the contents of src into dst.
+func deriveDeepCopy_31(dst, src map[string]*ServiceNetworkConfig) {
+	for src_key, src_value := range src {
+		if src_value == nil {
+			dst[src_key] = nil
+		}
+		if src_value == nil {
+			dst[src_key] = nil
+		} else {
+			dst[src_key] = new(ServiceNetworkConfig)
+			deriveDeepCopy_58(dst[src_key], src_value)
+		}
+	}
+}
+
+// deriveDeepCopy_32 recursively copies the contents of src into dst.
+func deriveDeepCopy_32(dst, src []ServicePortConfig) {
+	for src_i, src_value := range src {
+		func() {
+			field := new(ServicePortConfig)
+			deriveDeepCopy_59(field, &src_value)
+			dst[src_i] = *field
+		}()
+	}
+}
+
+// deriveDeepCopy_33 recursively copies the contents of src into dst.
+func deriveDeepCopy_33(dst, src []ServiceSecretConfig) {
+	for src_i, src_value := range src {
+		func() {
+			field := new(ServiceSecretConfig)
+			deriveDeepCopy_60(field, &src_value)
+			dst[src_i] = *field
+		}()
+	}
+}
+
+// deriveDeepCopy_34 recursively copies the contents of src into dst.
+func deriveDeepCopy_34(dst, src map[string]SensitiveConfig) {
+	for src_key, src_value := range src {
+		func() {
+			field := new(SensitiveConfig)
+			deriveDeepCopy_61(field, &src_value)
+			dst[src_key] = *field
+		}()
+	}
+}
+
+// deriveDeepCopy_35 recursively copies the contents of src into dst.
+func deriveDeepCopy_35(dst, src map[string]*UlimitsConfig) {
 	for src_key, src_value := range src {
 		if src_value == nil {
 			dst[src_key] = nil
@@ -1491,95 +1931,183 @@ func deriveDeepCopy_23(dst, src map[string]*ServiceNetworkConfig) {
 		if src_value == nil {
 			dst[src_key] = nil
 		} else {
-			dst[src_key] = new(ServiceNetworkConfig)
-			deriveDeepCopy_49(dst[src_key], src_value)
+			dst[src_key] = new(UlimitsConfig)
+			deriveDeepCopy_62(dst[src_key], src_value)
+		}
+	}
+}
+
+// deriveDeepCopy_36 recursively copies the contents of src into dst.
+func deriveDeepCopy_36(dst, src []ServiceVolumeConfig) {
+	for src_i, src_value := range src {
+		func() {
+			field := new(ServiceVolumeConfig)
+			deriveDeepCopy_63(field, &src_value)
+			dst[src_i] = *field
+		}()
+	}
+}
+
+// deriveDeepCopy_37 recursively copies the contents of src into dst.
+func deriveDeepCopy_37(dst, src []ServiceHook) {
+	for src_i, src_value := range src {
+		func() {
+			field := new(ServiceHook)
+			deriveDeepCopy_64(field, &src_value)
+			dst[src_i] = *field
+		}()
+	}
+}
+
+// deriveDeepCopy_38 recursively copies the contents of src into dst.
+func deriveDeepCopy_38(dst, src *PrebuildNeed) {
+	dst.Job = src.Job
+	dst.Status = src.Status
+	if src.Extensions != nil {
+		dst.Extensions = make(map[string]any, len(src.Extensions))
+		src.Extensions.DeepCopy(dst.Extensions)
+	} else {
+		dst.Extensions = nil
+	}
+}
+
+// deriveDeepCopy_39 recursively copies the contents of src into dst.
+func deriveDeepCopy_39(dst, src *PrebuildCommand) {
+	dst.Name = src.Name
+	dst.Command = src.Command
+	dst.Group = src.Group
+	dst.OriginalCommand = src.OriginalCommand
+	dst.RawCommand = src.RawCommand
+	dst.WasInterpolated = src.WasInterpolated
+	dst.Location = src.Location
+	dst.Register = src.Register
+	dst.If = src.If
+	dst.SkipReason = src.SkipReason
+	if src.Environment != nil {
+		dst.Environment = make(map[string]*string, len(src.Environment))
+		deriveDeepCopy_1(dst.Environment, src.Environment)
+	} else {
+		dst.Environment = nil
+	}
+	if src.EnvFiles == nil {
+		dst.EnvFiles = nil
+	} else {
+		if dst.EnvFiles != nil {
+			if len(src.EnvFiles) > len(dst.EnvFiles) {
+				if cap(dst.EnvFiles) >= len(src.EnvFiles) {
+					dst.EnvFiles = (dst.EnvFiles)[:len(src.EnvFiles)]
+				} else {
+					dst.EnvFiles = make([]EnvFile, len(src.EnvFiles))
+				}
+			} else if len(src.EnvFiles) < len(dst.EnvFiles) {
+				dst.EnvFiles = (dst.EnvFiles)[:len(src.EnvFiles)]
+			}
+		} else {
+			dst.EnvFiles = make([]EnvFile, len(src.EnvFiles))
+		}
+		copy(dst.EnvFiles, src.EnvFiles)
+	}
+	if src.Platforms == nil {
+		dst.Platforms = nil
+	} else {
+		if dst.Platforms != nil {
+			if len(src.Platforms) > len(dst.Platforms) {
+				if cap(dst.Platforms) >= len(src.Platforms) {
+					dst.Platforms = (dst.Platforms)[:len(src.Platforms)]
+				} else {
+					dst.Platforms = make([]string, len(src.Platforms))
+				}
+			} else if len(src.Platforms) < len(dst.Platforms) {
+				dst.Platforms = (dst.Platforms)[:len(src.Platforms)]
+			}
+		} else {
+			dst.Platforms = make([]string, len(src.Platforms))
+		}
+		copy(dst.Platforms, src.Platforms)
+	}
+	dst.WorkingDir = src.WorkingDir
+	if src.Shell == nil {
+		dst.Shell = nil
+	} else {
+		if dst.Shell != nil {
+			if len(src.Shell) > len(dst.Shell) {
+				if cap(dst.Shell) >= len(src.Shell) {
+					dst.Shell = (dst.Shell)[:len(src.Shell)]
+				} else {
+					dst.Shell = make([]string, len(src.Shell))
+				}
+			} else if len(src.Shell) < len(dst.Shell) {
+				dst.Shell = (dst.Shell)[:len(src.Shell)]
+			}
+		} else {
+			dst.Shell = make([]string, len(src.Shell))
 		}
+		copy(dst.Shell, src.Shell)
 	}
-}
-
-// deriveDeepCopy_24 recursively copies the contents of src into dst.
-func deriveDeepCopy_24(dst, src []ServicePortConfig) {
-	for src_i, src_value := range src {
-		func() {
-			field := new(ServicePortConfig)
-			deriveDeepCopy_50(field, &src_value)
-			dst[src_i] = *field
-		}()
+	if src.EstimatedDuration == nil {
+		dst.EstimatedDuration = nil
+	} else {
+		dst.EstimatedDuration = new(Duration)
+		*dst.EstimatedDuration = *src.EstimatedDuration
 	}
-}
-
-// deriveDeepCopy_25 recursively copies the contents of src into dst.
-func deriveDeepCopy_25(dst, src []ServiceSecretConfig) {
-	for src_i, src_value := range src {
-		func() {
-			field := new(ServiceSecretConfig)
-			deriveDeepCopy_51(field, &src_value)
-			dst[src_i] = *field
-		}()
+	if src.Timeout == nil {
+		dst.Timeout = nil
+	} else {
+		dst.Timeout = new(Duration)
+		*dst.Timeout = *src.Timeout
 	}
-}
-
-// deriveDeepCopy_26 recursively copies the contents of src into dst.
-func deriveDeepCopy_26(dst, src map[string]SensitiveConfig) {
-	for src_key, src_value := range src {
-		func() {
-			field := new(SensitiveConfig)
-			deriveDeepCopy_52(field, &src_value)
-			dst[src_key] = *field
-		}()
+	dst.ContinueOnError = src.ContinueOnError
+	if src.Retries == nil {
+		dst.Retries = nil
+	} else {
+		dst.Retries = new(int)
+		*dst.Retries = *src.Retries
 	}
-}
-
-// deriveDeepCopy_27 recursively copies the contents of src into dst.
-func deriveDeepCopy_27(dst, src map[string]*UlimitsConfig) {
-	for src_key, src_value := range src {
-		if src_value == nil {
-			dst[src_key] = nil
-		}
-		if src_value == nil {
-			dst[src_key] = nil
+	if src.RetryBackoff == nil {
+		dst.RetryBackoff = nil
+	} else {
+		dst.RetryBackoff = new(PrebuildRetryBackoff)
+		deriveDeepCopy_65(dst.RetryBackoff, src.RetryBackoff)
+	}
+	if src.AllowedPaths == nil {
+		dst.AllowedPaths = nil
+	} else {
+		if dst.AllowedPaths != nil {
+			if len(src.AllowedPaths) > len(dst.AllowedPaths) {
+				if cap(dst.AllowedPaths) >= len(src.AllowedPaths) {
+					dst.AllowedPaths = (dst.AllowedPaths)[:len(src.AllowedPaths)]
+				} else {
+					dst.AllowedPaths = make([]string, len(src.AllowedPaths))
+				}
+			} else if len(src.AllowedPaths) < len(dst.AllowedPaths) {
+				dst.AllowedPaths = (dst.AllowedPaths)[:len(src.AllowedPaths)]
+			}
 		} else {
-			dst[src_key] = new(UlimitsConfig)
-			deriveDeepCopy_53(dst[src_key], src_value)
+			dst.AllowedPaths = make([]string, len(src.AllowedPaths))
 		}
+		copy(dst.AllowedPaths, src.AllowedPaths)
 	}
-}
-
-// deriveDeepCopy_28 recursively copies the contents of src into dst.
-func deriveDeepCopy_28(dst, src []ServiceVolumeConfig) {
-	for src_i, src_value := range src {
-		func() {
-			field := new(ServiceVolumeConfig)
-			deriveDeepCopy_54(field, &src_value)
-			dst[src_i] = *field
-		}()
-	}
-}
-
-// deriveDeepCopy_29 recursively copies the contents of src into dst.
-func deriveDeepCopy_29(dst, src []ServiceHook) {
-	for src_i, src_value := range src {
-		func() {
-			field := new(ServiceHook)
-			deriveDeepCopy_55(field, &src_value)
-			dst[src_i] = *field
-		}()
+	if src.Extensions != nil {
+		dst.Extensions = make(map[string]any, len(src.Extensions))
+		src.Extensions.DeepCopy(dst.Extensions)
+	} else {
+		dst.Extensions = nil
 	}
 }
 
-// deriveDeepCopy_30 recursively copies the contents of src into dst.
-func deriveDeepCopy_30(dst, src *NetworkConfig) {
+// deriveDeepCopy_40 recursively copies the contents of src into dst.
+func deriveDeepCopy_40(dst, src *NetworkConfig) {
 	dst.Name = src.Name
 	dst.Driver = src.Driver
 	if src.DriverOpts != nil {
 		dst.DriverOpts = make(map[string]string, len(src.DriverOpts))
-		deriveDeepCopy_5(dst.DriverOpts, src.DriverOpts)
+		deriveDeepCopy_13(dst.DriverOpts, src.DriverOpts)
 	} else {
 		dst.DriverOpts = nil
 	}
 	func() {
 		field := new(IPAMConfig)
-		deriveDeepCopy_56(field, &src.Ipam)
+		deriveDeepCopy_66(field, &src.Ipam)
 		dst.Ipam = *field
 	}()
 	dst.External = src.External
@@ -1587,13 +2115,13 @@ func deriveDeepCopy_30(dst, src *NetworkConfig) {
 	dst.Attachable = src.Attachable
 	if src.Labels != nil {
 		dst.Labels = make(map[string]string, len(src.Labels))
-		deriveDeepCopy_5(dst.Labels, src.Labels)
+		deriveDeepCopy_13(dst.Labels, src.Labels)
 	} else {
 		dst.Labels = nil
 	}
 	if src.CustomLabels != nil {
 		dst.CustomLabels = make(map[string]string, len(src.CustomLabels))
-		deriveDeepCopy_5(dst.CustomLabels, src.CustomLabels)
+		deriveDeepCopy_13(dst.CustomLabels, src.CustomLabels)
 	} else {
 		dst.CustomLabels = nil
 	}
@@ -1617,26 +2145,26 @@ func deriveDeepCopy_30(dst, src *NetworkConfig) {
 	}
 }
 
-// deriveDeepCopy_31 recursively copies the contents of src into dst.
-func deriveDeepCopy_31(dst, src *VolumeConfig) {
+// deriveDeepCopy_41 recursively copies the contents of src into dst.
+func deriveDeepCopy_41(dst, src *VolumeConfig) {
 	dst.Name = src.Name
 	dst.Driver = src.Driver
 	if src.DriverOpts != nil {
 		dst.DriverOpts = make(map[string]string, len(src.DriverOpts))
-		deriveDeepCopy_5(dst.DriverOpts, src.DriverOpts)
+		deriveDeepCopy_13(dst.DriverOpts, src.DriverOpts)
 	} else {
 		dst.DriverOpts = nil
 	}
 	dst.External = src.External
 	if src.Labels != nil {
 		dst.Labels = make(map[string]string, len(src.Labels))
-		deriveDeepCopy_5(dst.Labels, src.Labels)
+		deriveDeepCopy_13(dst.Labels, src.Labels)
 	} else {
 		dst.Labels = nil
 	}
 	if src.CustomLabels != nil {
 		dst.CustomLabels = make(map[string]string, len(src.CustomLabels))
-		deriveDeepCopy_5(dst.CustomLabels, src.CustomLabels)
+		deriveDeepCopy_13(dst.CustomLabels, src.CustomLabels)
 	} else {
 		dst.CustomLabels = nil
 	}
@@ -1648,8 +2176,8 @@ func deriveDeepCopy_31(dst, src *VolumeConfig) {
 	}
 }
 
-// deriveDeepCopy_32 recursively copies the contents of src into dst.
-func deriveDeepCopy_32(dst, src *SecretConfig) {
+// deriveDeepCopy_42 recursively copies the contents of src into dst.
+func deriveDeepCopy_42(dst, src *SecretConfig) {
 	dst.Name = src.Name
 	dst.File = src.File
 	dst.Environment = src.Environment
@@ -1658,14 +2186,14 @@ func deriveDeepCopy_32(dst, src *SecretConfig) {
 	dst.External = src.External
 	if src.Labels != nil {
 		dst.Labels = make(map[string]string, len(src.Labels))
-		deriveDeepCopy_5(dst.Labels, src.Labels)
+		deriveDeepCopy_13(dst.Labels, src.Labels)
 	} else {
 		dst.Labels = nil
 	}
 	dst.Driver = src.Driver
 	if src.DriverOpts != nil {
 		dst.DriverOpts = make(map[string]string, len(src.DriverOpts))
-		deriveDeepCopy_5(dst.DriverOpts, src.DriverOpts)
+		deriveDeepCopy_13(dst.DriverOpts, src.DriverOpts)
 	} else {
 		dst.DriverOpts = nil
 	}
@@ -1678,8 +2206,8 @@ func deriveDeepCopy_32(dst, src *SecretConfig) {
 	}
 }
 
-// deriveDeepCopy_33 recursively copies the contents of src into dst.
-func deriveDeepCopy_33(dst, src *ConfigObjConfig) {
+// deriveDeepCopy_43 recursively copies the contents of src into dst.
+func deriveDeepCopy_43(dst, src *ConfigObjConfig) {
 	dst.Name = src.Name
 	dst.File = src.File
 	dst.Environment = src.Environment
@@ -1688,14 +2216,14 @@ func deriveDeepCopy_33(dst, src *ConfigObjConfig) {
 	dst.External = src.External
 	if src.Labels != nil {
 		dst.Labels = make(map[string]string, len(src.Labels))
-		deriveDeepCopy_5(dst.Labels, src.Labels)
+		deriveDeepCopy_13(dst.Labels, src.Labels)
 	} else {
 		dst.Labels = nil
 	}
 	dst.Driver = src.Driver
 	if src.DriverOpts != nil {
 		dst.DriverOpts = make(map[string]string, len(src.DriverOpts))
-		deriveDeepCopy_5(dst.DriverOpts, src.DriverOpts)
+		deriveDeepCopy_13(dst.DriverOpts, src.DriverOpts)
 	} else {
 		dst.DriverOpts = nil
 	}
@@ -1708,8 +2236,8 @@ func deriveDeepCopy_33(dst, src *ConfigObjConfig) {
 	}
 }
 
-// deriveDeepCopy_34 recursively copies the contents of src into dst.
-func deriveDeepCopy_34(dst, src *ModelConfig) {
+// deriveDeepCopy_44 recursively copies the contents of src into dst.
+func deriveDeepCopy_44(dst, src *ModelConfig) {
 	dst.Name = src.Name
 	dst.Model = src.Model
 	dst.ContextSize = src.ContextSize
@@ -1739,71 +2267,41 @@ func deriveDeepCopy_34(dst, src *ModelConfig) {
 	}
 }
 
-// deriveDeepCopy_35 recursively copies the contents of src into dst.
-func deriveDeepCopy_35(dst, src *PrebuildJob) {
-	dst.Name = src.Name
-	dst.RunsOn = src.RunsOn
-	if src.Commands == nil {
-		dst.Commands = nil
-	} else {
-		if dst.Commands != nil {
-			if len(src.Commands) > len(dst.Commands) {
-				if cap(dst.Commands) >= len(src.Commands) {
-					dst.Commands = (dst.Commands)[:len(src.Commands)]
-				} else {
-					dst.Commands = make([]PrebuildCommand, len(src.Commands))
-				}
-			} else if len(src.Commands) < len(dst.Commands) {
-				dst.Commands = (dst.Commands)[:len(src.Commands)]
-			}
-		} else {
-			dst.Commands = make([]PrebuildCommand, len(src.Commands))
-		}
-		deriveDeepCopy_57(dst.Commands, src.Commands)
-	}
-	if src.Extensions != nil {
-		dst.Extensions = make(map[string]any, len(src.Extensions))
-		src.Extensions.DeepCopy(dst.Extensions)
-	} else {
-		dst.Extensions = nil
-	}
-}
-
-// deriveDeepCopy_36 recursively copies the contents of src into dst.
-func deriveDeepCopy_36(dst, src []Trigger) {
+// deriveDeepCopy_45 recursively copies the contents of src into dst.
+func deriveDeepCopy_45(dst, src []Trigger) {
 	for src_i, src_value := range src {
 		func() {
 			field := new(Trigger)
-			deriveDeepCopy_58(field, &src_value)
+			deriveDeepCopy_67(field, &src_value)
 			dst[src_i] = *field
 		}()
 	}
 }
 
-// deriveDeepCopy_37 recursively copies the contents of src into dst.
-func deriveDeepCopy_37(dst, src []WeightDevice) {
+// deriveDeepCopy_46 recursively copies the contents of src into dst.
+func deriveDeepCopy_46(dst, src []WeightDevice) {
 	for src_i, src_value := range src {
 		func() {
 			field := new(WeightDevice)
-			deriveDeepCopy_59(field, &src_value)
+			deriveDeepCopy_68(field, &src_value)
 			dst[src_i] = *field
 		}()
 	}
 }
 
-// deriveDeepCopy_38 recursively copies the contents of src into dst.
-func deriveDeepCopy_38(dst, src []ThrottleDevice) {
+// deriveDeepCopy_47 recursively copies the contents of src into dst.
+func deriveDeepCopy_47(dst, src []ThrottleDevice) {
 	for src_i, src_value := range src {
 		func() {
 			field := new(ThrottleDevice)
-			deriveDeepCopy_60(field, &src_value)
+			deriveDeepCopy_69(field, &src_value)
 			dst[src_i] = *field
 		}()
 	}
 }
 
-// deriveDeepCopy_39 recursively copies the contents of src into dst.
-func deriveDeepCopy_39(dst, src *ServiceConfigObjConfig) {
+// deriveDeepCopy_48 recursively copies the contents of src into dst.
+func deriveDeepCopy_48(dst, src *ServiceConfigObjConfig) {
 	dst.Source = src.Source
 	dst.Target = src.Target
 	dst.UID = src.UID
@@ -1822,9 +2320,22 @@ func deriveDeepCopy_39(dst, src *ServiceConfigObjConfig) {
 	}
 }
 
-// deriveDeepCopy_40 recursively copies the contents of src into dst.
-func deriveDeepCopy_40(dst, src *LocalConfigConfig) {
+// deriveDeepCopy_49 recursively copies the contents of src into dst.
+func deriveDeepCopy_49(dst, src *LocalConfigConfig) {
 	dst.Source = src.Source
+	dst.ResolvedSource = src.ResolvedSource
+	dst.Content = src.Content
+	if src.MaxSize == nil {
+		dst.MaxSize = nil
+	} else {
+		dst.MaxSize = new(UnitBytes)
+		*dst.MaxSize = *src.MaxSize
+	}
+	dst.Interpolate = src.Interpolate
+	dst.ResolvedUID = src.ResolvedUID
+	dst.ResolvedGID = src.ResolvedGID
+	dst.SELinux = src.SELinux
+	dst.TemplateEngine = src.TemplateEngine
 	dst.Target = src.Target
 	dst.UID = src.UID
 	dst.GID = src.GID
@@ -1842,8 +2353,8 @@ func deriveDeepCopy_40(dst, src *LocalConfigConfig) {
 	}
 }
 
-// deriveDeepCopy_41 recursively copies the contents of src into dst.
-func deriveDeepCopy_41(dst, src *ServiceDependency) {
+// deriveDeepCopy_50 recursively copies the contents of src into dst.
+func deriveDeepCopy_50(dst, src *ServiceDependency) {
 	dst.Condition = src.Condition
 	dst.Restart = src.Restart
 	if src.Extensions != nil {
@@ -1855,8 +2366,8 @@ func deriveDeepCopy_41(dst, src *ServiceDependency) {
 	dst.Required = src.Required
 }
 
-// deriveDeepCopy_42 recursively copies the contents of src into dst.
-func deriveDeepCopy_42(dst, src *UpdateConfig) {
+// deriveDeepCopy_51 recursively copies the contents of src into dst.
+func deriveDeepCopy_51(dst, src *UpdateConfig) {
 	if src.Parallelism == nil {
 		dst.Parallelism = nil
 	} else {
@@ -1876,19 +2387,19 @@ func deriveDeepCopy_42(dst, src *UpdateConfig) {
 	}
 }
 
-// deriveDeepCopy_43 recursively copies the contents of src into dst.
-func deriveDeepCopy_43(dst, src *Resources) {
+// deriveDeepCopy_52 recursively copies the contents of src into dst.
+func deriveDeepCopy_52(dst, src *Resources) {
 	if src.Limits == nil {
 		dst.Limits = nil
 	} else {
 		dst.Limits = new(Resource)
-		deriveDeepCopy_61(dst.Limits, src.Limits)
+		deriveDeepCopy_70(dst.Limits, src.Limits)
 	}
 	if src.Reservations == nil {
 		dst.Reservations = nil
 	} else {
 		dst.Reservations = new(Resource)
-		deriveDeepCopy_61(dst.Reservations, src.Reservations)
+		deriveDeepCopy_70(dst.Reservations, src.Reservations)
 	}
 	if src.Extensions != nil {
 		dst.Extensions = make(map[string]any, len(src.Extensions))
@@ -1898,8 +2409,8 @@ func deriveDeepCopy_43(dst, src *Resources) {
 	}
 }
 
-// deriveDeepCopy_44 recursively copies the contents of src into dst.
-func deriveDeepCopy_44(dst, src *RestartPolicy) {
+// deriveDeepCopy_53 recursively copies the contents of src into dst.
+func deriveDeepCopy_53(dst, src *RestartPolicy) {
 	dst.Condition = src.Condition
 	if src.Delay == nil {
 		dst.Delay = nil
@@ -1927,8 +2438,8 @@ func deriveDeepCopy_44(dst, src *RestartPolicy) {
 	}
 }
 
-// deriveDeepCopy_45 recursively copies the contents of src into dst.
-func deriveDeepCopy_45(dst, src *Placement) {
+// deriveDeepCopy_54 recursively copies the contents of src into dst.
+func deriveDeepCopy_54(dst, src *Placement) {
 	if src.Constraints == nil {
 		dst.Constraints = nil
 	} else {
@@ -1963,7 +2474,7 @@ func deriveDeepCopy_45(dst, src *Placement) {
 		} else {
 			dst.Preferences = make([]PlacementPreferences, len(src.Preferences))
 		}
-		deriveDeepCopy_62(dst.Preferences, src.Preferences)
+		deriveDeepCopy_71(dst.Preferences, src.Preferences)
 	}
 	dst.MaxReplicas = src.MaxReplicas
 	if src.Extensions != nil {
@@ -1974,8 +2485,8 @@ func deriveDeepCopy_45(dst, src *Placement) {
 	}
 }
 
-// deriveDeepCopy_46 recursively copies the contents of src into dst.
-func deriveDeepCopy_46(dst, src *DeviceMapping) {
+// deriveDeepCopy_55 recursively copies the contents of src into dst.
+func deriveDeepCopy_55(dst, src *DeviceMapping) {
 	dst.Source = src.Source
 	dst.Target = src.Target
 	dst.Permissions = src.Permissions
@@ -1987,8 +2498,8 @@ func deriveDeepCopy_46(dst, src *DeviceMapping) {
 	}
 }
 
-// deriveDeepCopy_47 recursively copies the contents of src into dst.
-func deriveDeepCopy_47(dst, src *DeviceRequest) {
+// deriveDeepCopy_56 recursively copies the contents of src into dst.
+func deriveDeepCopy_56(dst, src *DeviceRequest) {
 	if src.Capabilities == nil {
 		dst.Capabilities = nil
 	} else {
@@ -2029,14 +2540,14 @@ func deriveDeepCopy_47(dst, src *DeviceRequest) {
 	}
 	if src.Options != nil {
 		dst.Options = make(map[string]string, len(src.Options))
-		deriveDeepCopy_5(dst.Options, src.Options)
+		deriveDeepCopy_13(dst.Options, src.Options)
 	} else {
 		dst.Options = nil
 	}
 }
 
-// deriveDeepCopy_48 recursively copies the contents of src into dst.
-func deriveDeepCopy_48(dst, src *ServiceModelConfig) {
+// deriveDeepCopy_57 recursively copies the contents of src into dst.
+func deriveDeepCopy_57(dst, src *ServiceModelConfig) {
 	dst.EndpointVariable = src.EndpointVariable
 	dst.ModelVariable = src.ModelVariable
 	if src.Extensions != nil {
@@ -2047,8 +2558,8 @@ func deriveDeepCopy_48(dst, src *ServiceModelConfig) {
 	}
 }
 
-// deriveDeepCopy_49 recursively copies the contents of src into dst.
-func deriveDeepCopy_49(dst, src *ServiceNetworkConfig) {
+// deriveDeepCopy_58 recursively copies the contents of src into dst.
+func deriveDeepCopy_58(dst, src *ServiceNetworkConfig) {
 	if src.Aliases == nil {
 		dst.Aliases = nil
 	} else {
@@ -2069,7 +2580,7 @@ func deriveDeepCopy_49(dst, src *ServiceNetworkConfig) {
 	}
 	if src.DriverOpts != nil {
 		dst.DriverOpts = make(map[string]string, len(src.DriverOpts))
-		deriveDeepCopy_5(dst.DriverOpts, src.DriverOpts)
+		deriveDeepCopy_13(dst.DriverOpts, src.DriverOpts)
 	} else {
 		dst.DriverOpts = nil
 	}
@@ -2105,8 +2616,8 @@ func deriveDeepCopy_49(dst, src *ServiceNetworkConfig) {
 	}
 }
 
-// deriveDeepCopy_50 recursively copies the contents of src into dst.
-func deriveDeepCopy_50(dst, src *ServicePortConfig) {
+// deriveDeepCopy_59 recursively copies the contents of src into dst.
+func deriveDeepCopy_59(dst, src *ServicePortConfig) {
 	dst.Name = src.Name
 	dst.Mode = src.Mode
 	dst.HostIP = src.HostIP
@@ -2122,8 +2633,8 @@ func deriveDeepCopy_50(dst, src *ServicePortConfig) {
 	}
 }
 
-// deriveDeepCopy_51 recursively copies the contents of src into dst.
-func deriveDeepCopy_51(dst, src *ServiceSecretConfig) {
+// deriveDeepCopy_60 recursively copies the contents of src into dst.
+func deriveDeepCopy_60(dst, src *ServiceSecretConfig) {
 	dst.Source = src.Source
 	dst.Target = src.Target
 	dst.UID = src.UID
@@ -2142,8 +2653,8 @@ func deriveDeepCopy_51(dst, src *ServiceSecretConfig) {
 	}
 }
 
-// deriveDeepCopy_52 recursively copies the contents of src into dst.
-func deriveDeepCopy_52(dst, src *SensitiveConfig) {
+// deriveDeepCopy_61 recursively copies the contents of src into dst.
+func deriveDeepCopy_61(dst, src *SensitiveConfig) {
 	dst.Target = src.Target
 	dst.Format = src.Format
 	if src.Secrets == nil {
@@ -2162,9 +2673,17 @@ func deriveDeepCopy_52(dst, src *SensitiveConfig) {
 		} else {
 			dst.Secrets = make([]SensitiveSecret, len(src.Secrets))
 		}
-		deriveDeepCopy_63(dst.Secrets, src.Secrets)
+		deriveDeepCopy_72(dst.Secrets, src.Secrets)
+	}
+	dst.FromLabel = src.FromLabel
+	if src.Alias != nil {
+		dst.Alias = make(map[string]string, len(src.Alias))
+		deriveDeepCopy_13(dst.Alias, src.Alias)
+	} else {
+		dst.Alias = nil
 	}
 	dst.Template = src.Template
+	dst.TemplateContent = src.TemplateContent
 	dst.UID = src.UID
 	dst.GID = src.GID
 	if src.Mode == nil {
@@ -2173,6 +2692,28 @@ func deriveDeepCopy_52(dst, src *SensitiveConfig) {
 		dst.Mode = new(FileMode)
 		*dst.Mode = *src.Mode
 	}
+	dst.ResolvedUID = src.ResolvedUID
+	dst.ResolvedGID = src.ResolvedGID
+	dst.SELinux = src.SELinux
+	dst.Sort = src.Sort
+	if src.TTL == nil {
+		dst.TTL = nil
+	} else {
+		dst.TTL = new(Duration)
+		*dst.TTL = *src.TTL
+	}
+	if src.TrailingNewline == nil {
+		dst.TrailingNewline = nil
+	} else {
+		dst.TrailingNewline = new(bool)
+		*dst.TrailingNewline = *src.TrailingNewline
+	}
+	if src.OnChange == nil {
+		dst.OnChange = nil
+	} else {
+		dst.OnChange = new(SensitiveOnChange)
+		deriveDeepCopy_73(dst.OnChange, src.OnChange)
+	}
 	if src.Extensions != nil {
 		dst.Extensions = make(map[string]any, len(src.Extensions))
 		src.Extensions.DeepCopy(dst.Extensions)
@@ -2181,8 +2722,8 @@ func deriveDeepCopy_52(dst, src *SensitiveConfig) {
 	}
 }
 
-// deriveDeepCopy_53 recursively copies the contents of src into dst.
-func deriveDeepCopy_53(dst, src *UlimitsConfig) {
+// deriveDeepCopy_62 recursively copies the contents of src into dst.
+func deriveDeepCopy_62(dst, src *UlimitsConfig) {
 	dst.Single = src.Single
 	dst.Soft = src.Soft
 	dst.Hard = src.Hard
@@ -2194,8 +2735,8 @@ func deriveDeepCopy_53(dst, src *UlimitsConfig) {
 	}
 }
 
-// deriveDeepCopy_54 recursively copies the contents of src into dst.
-func deriveDeepCopy_54(dst, src *ServiceVolumeConfig) {
+// deriveDeepCopy_63 recursively copies the contents of src into dst.
+func deriveDeepCopy_63(dst, src *ServiceVolumeConfig) {
 	dst.Type = src.Type
 	dst.Source = src.Source
 	dst.Target = src.Target
@@ -2205,25 +2746,25 @@ func deriveDeepCopy_54(dst, src *ServiceVolumeConfig) {
 		dst.Bind = nil
 	} else {
 		dst.Bind = new(ServiceVolumeBind)
-		deriveDeepCopy_64(dst.Bind, src.Bind)
+		deriveDeepCopy_74(dst.Bind, src.Bind)
 	}
 	if src.Volume == nil {
 		dst.Volume = nil
 	} else {
 		dst.Volume = new(ServiceVolumeVolume)
-		deriveDeepCopy_65(dst.Volume, src.Volume)
+		deriveDeepCopy_75(dst.Volume, src.Volume)
 	}
 	if src.Tmpfs == nil {
 		dst.Tmpfs = nil
 	} else {
 		dst.Tmpfs = new(ServiceVolumeTmpfs)
-		deriveDeepCopy_66(dst.Tmpfs, src.Tmpfs)
+		deriveDeepCopy_76(dst.Tmpfs, src.Tmpfs)
 	}
 	if src.Image == nil {
 		dst.Image = nil
 	} else {
 		dst.Image = new(ServiceVolumeImage)
-		deriveDeepCopy_67(dst.Image, src.Image)
+		deriveDeepCopy_77(dst.Image, src.Image)
 	}
 	if src.Extensions != nil {
 		dst.Extensions = make(map[string]any, len(src.Extensions))
@@ -2233,8 +2774,8 @@ func deriveDeepCopy_54(dst, src *ServiceVolumeConfig) {
 	}
 }
 
-// deriveDeepCopy_55 recursively copies the contents of src into dst.
-func deriveDeepCopy_55(dst, src *ServiceHook) {
+// deriveDeepCopy_64 recursively copies the contents of src into dst.
+func deriveDeepCopy_64(dst, src *ServiceHook) {
 	if src.Command == nil {
 		dst.Command = nil
 	} else {
@@ -2258,7 +2799,7 @@ func deriveDeepCopy_55(dst, src *ServiceHook) {
 	dst.WorkingDir = src.WorkingDir
 	if src.Environment != nil {
 		dst.Environment = make(map[string]*string, len(src.Environment))
-		deriveDeepCopy_17(dst.Environment, src.Environment)
+		deriveDeepCopy_1(dst.Environment, src.Environment)
 	} else {
 		dst.Environment = nil
 	}
@@ -2270,8 +2811,26 @@ func deriveDeepCopy_55(dst, src *ServiceHook) {
 	}
 }
 
-// deriveDeepCopy_56 recursively copies the contents of src into dst.
-func deriveDeepCopy_56(dst, src *IPAMConfig) {
+// deriveDeepCopy_65 recursively copies the contents of src into dst.
+func deriveDeepCopy_65(dst, src *PrebuildRetryBackoff) {
+	dst.Initial = src.Initial
+	dst.Factor = src.Factor
+	if src.Max == nil {
+		dst.Max = nil
+	} else {
+		dst.Max = new(Duration)
+		*dst.Max = *src.Max
+	}
+	if src.Extensions != nil {
+		dst.Extensions = make(map[string]any, len(src.Extensions))
+		src.Extensions.DeepCopy(dst.Extensions)
+	} else {
+		dst.Extensions = nil
+	}
+}
+
+// deriveDeepCopy_66 recursively copies the contents of src into dst.
+func deriveDeepCopy_66(dst, src *IPAMConfig) {
 	dst.Driver = src.Driver
 	if src.Config == nil {
 		dst.Config = nil
@@ -2289,7 +2848,7 @@ func deriveDeepCopy_56(dst, src *IPAMConfig) {
 		} else {
 			dst.Config = make([]*IPAMPool, len(src.Config))
 		}
-		deriveDeepCopy_68(dst.Config, src.Config)
+		deriveDeepCopy_78(dst.Config, src.Config)
 	}
 	if src.Extensions != nil {
 		dst.Extensions = make(map[string]any, len(src.Extensions))
@@ -2299,25 +2858,14 @@ func deriveDeepCopy_56(dst, src *IPAMConfig) {
 	}
 }
 
-// deriveDeepCopy_57 recursively copies the contents of src into dst.
-func deriveDeepCopy_57(dst, src []PrebuildCommand) {
-	for src_i, src_value := range src {
-		func() {
-			field := new(PrebuildCommand)
-			deriveDeepCopy_69(field, &src_value)
-			dst[src_i] = *field
-		}()
-	}
-}
-
-// deriveDeepCopy_58 recursively copies the contents of src into dst.
-func deriveDeepCopy_58(dst, src *Trigger) {
+// deriveDeepCopy_67 recursively copies the contents of src into dst.
+func deriveDeepCopy_67(dst, src *Trigger) {
 	dst.Path = src.Path
 	dst.Action = src.Action
 	dst.Target = src.Target
 	func() {
 		field := new(ServiceHook)
-		deriveDeepCopy_55(field, &src.Exec)
+		deriveDeepCopy_64(field, &src.Exec)
 		dst.Exec = *field
 	}()
 	if src.Include == nil {
@@ -2365,8 +2913,8 @@ func deriveDeepCopy_58(dst, src *Trigger) {
 	}
 }
 
-// deriveDeepCopy_59 recursively copies the contents of src into dst.
-func deriveDeepCopy_59(dst, src *WeightDevice) {
+// deriveDeepCopy_68 recursively copies the contents of src into dst.
+func deriveDeepCopy_68(dst, src *WeightDevice) {
 	dst.Path = src.Path
 	dst.Weight = src.Weight
 	if src.Extensions != nil {
@@ -2377,8 +2925,8 @@ func deriveDeepCopy_59(dst, src *WeightDevice) {
 	}
 }
 
-// deriveDeepCopy_60 recursively copies the contents of src into dst.
-func deriveDeepCopy_60(dst, src *ThrottleDevice) {
+// deriveDeepCopy_69 recursively copies the contents of src into dst.
+func deriveDeepCopy_69(dst, src *ThrottleDevice) {
 	dst.Path = src.Path
 	dst.Rate = src.Rate
 	if src.Extensions != nil {
@@ -2389,8 +2937,8 @@ func deriveDeepCopy_60(dst, src *ThrottleDevice) {
 	}
 }
 
-// deriveDeepCopy_61 recursively copies the contents of src into dst.
-func deriveDeepCopy_61(dst, src *Resource) {
+// deriveDeepCopy_70 recursively copies the contents of src into dst.
+func deriveDeepCopy_70(dst, src *Resource) {
 	dst.NanoCPUs = src.NanoCPUs
 	dst.MemoryBytes = src.MemoryBytes
 	dst.Pids = src.Pids
@@ -2410,7 +2958,7 @@ func deriveDeepCopy_61(dst, src *Resource) {
 		} else {
 			dst.Devices = make([]DeviceRequest, len(src.Devices))
 		}
-		deriveDeepCopy_19(dst.Devices, src.Devices)
+		deriveDeepCopy_27(dst.Devices, src.Devices)
 	}
 	if src.GenericResources == nil {
 		dst.GenericResources = nil
@@ -2428,7 +2976,7 @@ func deriveDeepCopy_61(dst, src *Resource) {
 		} else {
 			dst.GenericResources = make([]GenericResource, len(src.GenericResources))
 		}
-		deriveDeepCopy_70(dst.GenericResources, src.GenericResources)
+		deriveDeepCopy_79(dst.GenericResources, src.GenericResources)
 	}
 	if src.Extensions != nil {
 		dst.Extensions = make(map[string]any, len(src.Extensions))
@@ -2438,30 +2986,42 @@ func deriveDeepCopy_61(dst, src *Resource) {
 	}
 }
 
-// deriveDeepCopy_62 recursively copies the contents of src into dst.
-func deriveDeepCopy_62(dst, src []PlacementPreferences) {
+// deriveDeepCopy_71 recursively copies the contents of src into dst.
+func deriveDeepCopy_71(dst, src []PlacementPreferences) {
 	for src_i, src_value := range src {
 		func() {
 			field := new(PlacementPreferences)
-			deriveDeepCopy_71(field, &src_value)
+			deriveDeepCopy_80(field, &src_value)
 			dst[src_i] = *field
 		}()
 	}
 }
 
-// deriveDeepCopy_63 recursively copies the contents of src into dst.
-func deriveDeepCopy_63(dst, src []SensitiveSecret) {
+// deriveDeepCopy_72 recursively copies the contents of src into dst.
+func deriveDeepCopy_72(dst, src []SensitiveSecret) {
 	for src_i, src_value := range src {
 		func() {
 			field := new(SensitiveSecret)
-			deriveDeepCopy_72(field, &src_value)
+			deriveDeepCopy_81(field, &src_value)
 			dst[src_i] = *field
 		}()
 	}
 }
 
-// deriveDeepCopy_64 recursively copies the contents of src into dst.
-func deriveDeepCopy_64(dst, src *ServiceVolumeBind) {
+// deriveDeepCopy_73 recursively copies the contents of src into dst.
+func deriveDeepCopy_73(dst, src *SensitiveOnChange) {
+	dst.Action = src.Action
+	dst.Signal = src.Signal
+	if src.Extensions != nil {
+		dst.Extensions = make(map[string]any, len(src.Extensions))
+		src.Extensions.DeepCopy(dst.Extensions)
+	} else {
+		dst.Extensions = nil
+	}
+}
+
+// deriveDeepCopy_74 recursively copies the contents of src into dst.
+func deriveDeepCopy_74(dst, src *ServiceVolumeBind) {
 	dst.SELinux = src.SELinux
 	dst.Propagation = src.Propagation
 	dst.CreateHostPath = src.CreateHostPath
@@ -2474,11 +3034,11 @@ func deriveDeepCopy_64(dst, src *ServiceVolumeBind) {
 	}
 }
 
-// deriveDeepCopy_65 recursively copies the contents of src into dst.
-func deriveDeepCopy_65(dst, src *ServiceVolumeVolume) {
+// deriveDeepCopy_75 recursively copies the contents of src into dst.
+func deriveDeepCopy_75(dst, src *ServiceVolumeVolume) {
 	if src.Labels != nil {
 		dst.Labels = make(map[string]string, len(src.Labels))
-		deriveDeepCopy_5(dst.Labels, src.Labels)
+		deriveDeepCopy_13(dst.Labels, src.Labels)
 	} else {
 		dst.Labels = nil
 	}
@@ -2492,8 +3052,8 @@ func deriveDeepCopy_65(dst, src *ServiceVolumeVolume) {
 	}
 }
 
-// deriveDeepCopy_66 recursively copies the contents of src into dst.
-func deriveDeepCopy_66(dst, src *ServiceVolumeTmpfs) {
+// deriveDeepCopy_76 recursively copies the contents of src into dst.
+func deriveDeepCopy_76(dst, src *ServiceVolumeTmpfs) {
 	dst.Size = src.Size
 	dst.Mode = src.Mode
 	if src.Extensions != nil {
@@ -2504,8 +3064,8 @@ func deriveDeepCopy_66(dst, src *ServiceVolumeTmpfs) {
 	}
 }
 
-// deriveDeepCopy_67 recursively copies the contents of src into dst.
-func deriveDeepCopy_67(dst, src *ServiceVolumeImage) {
+// deriveDeepCopy_77 recursively copies the contents of src into dst.
+func deriveDeepCopy_77(dst, src *ServiceVolumeImage) {
 	dst.SubPath = src.SubPath
 	if src.Extensions != nil {
 		dst.Extensions = make(map[string]any, len(src.Extensions))
@@ -2515,43 +3075,31 @@ func deriveDeepCopy_67(dst, src *ServiceVolumeImage) {
 	}
 }
 
-// deriveDeepCopy_68 recursively copies the contents of src into dst.
-func deriveDeepCopy_68(dst, src []*IPAMPool) {
+// deriveDeepCopy_78 recursively copies the contents of src into dst.
+func deriveDeepCopy_78(dst, src []*IPAMPool) {
 	for src_i, src_value := range src {
 		if src_value == nil {
 			dst[src_i] = nil
 		} else {
 			dst[src_i] = new(IPAMPool)
-			deriveDeepCopy_73(dst[src_i], src_value)
+			deriveDeepCopy_82(dst[src_i], src_value)
 		}
 	}
 }
 
-// deriveDeepCopy_69 recursively copies the contents of src into dst.
-func deriveDeepCopy_69(dst, src *PrebuildCommand) {
-	dst.Name = src.Name
-	dst.Command = src.Command
-	if src.Extensions != nil {
-		dst.Extensions = make(map[string]any, len(src.Extensions))
-		src.Extensions.DeepCopy(dst.Extensions)
-	} else {
-		dst.Extensions = nil
-	}
-}
-
-// deriveDeepCopy_70 recursively copies the contents of src into dst.
-func deriveDeepCopy_70(dst, src []GenericResource) {
+// deriveDeepCopy_79 recursively copies the contents of src into dst.
+func deriveDeepCopy_79(dst, src []GenericResource) {
 	for src_i, src_value := range src {
 		func() {
 			field := new(GenericResource)
-			deriveDeepCopy_74(field, &src_value)
+			deriveDeepCopy_83(field, &src_value)
 			dst[src_i] = *field
 		}()
 	}
 }
 
-// deriveDeepCopy_71 recursively copies the contents of src into dst.
-func deriveDeepCopy_71(dst, src *PlacementPreferences) {
+// deriveDeepCopy_80 recursively copies the contents of src into dst.
+func deriveDeepCopy_80(dst, src *PlacementPreferences) {
 	dst.Spread = src.Spread
 	if src.Extensions != nil {
 		dst.Extensions = make(map[string]any, len(src.Extensions))
@@ -2561,10 +3109,26 @@ func deriveDeepCopy_71(dst, src *PlacementPreferences) {
 	}
 }
 
-// deriveDeepCopy_72 recursively copies the contents of src into dst.
-func deriveDeepCopy_72(dst, src *SensitiveSecret) {
+// deriveDeepCopy_81 recursively copies the contents of src into dst.
+func deriveDeepCopy_81(dst, src *SensitiveSecret) {
 	dst.Source = src.Source
 	dst.Name = src.Name
+	if src.Validate == nil {
+		dst.Validate = nil
+	} else {
+		dst.Validate = new(SensitiveSecretValidation)
+		deriveDeepCopy_84(dst.Validate, src.Validate)
+	}
+	dst.Provider = src.Provider
+	dst.ProviderPath = src.ProviderPath
+	dst.Environment = src.Environment
+	dst.File = src.File
+	if src.Default == nil {
+		dst.Default = nil
+	} else {
+		dst.Default = new(string)
+		*dst.Default = *src.Default
+	}
 	if src.Extensions != nil {
 		dst.Extensions = make(map[string]any, len(src.Extensions))
 		src.Extensions.DeepCopy(dst.Extensions)
@@ -2573,14 +3137,14 @@ func deriveDeepCopy_72(dst, src *SensitiveSecret) {
 	}
 }
 
-// deriveDeepCopy_73 recursively copies the contents of src into dst.
-func deriveDeepCopy_73(dst, src *IPAMPool) {
+// deriveDeepCopy_82 recursively copies the contents of src into dst.
+func deriveDeepCopy_82(dst, src *IPAMPool) {
 	dst.Subnet = src.Subnet
 	dst.Gateway = src.Gateway
 	dst.IPRange = src.IPRange
 	if src.AuxiliaryAddresses != nil {
 		dst.AuxiliaryAddresses = make(map[string]string, len(src.AuxiliaryAddresses))
-		deriveDeepCopy_5(dst.AuxiliaryAddresses, src.AuxiliaryAddresses)
+		deriveDeepCopy_13(dst.AuxiliaryAddresses, src.AuxiliaryAddresses)
 	} else {
 		dst.AuxiliaryAddresses = nil
 	}
@@ -2592,13 +3156,13 @@ func deriveDeepCopy_73(dst, src *IPAMPool) {
 	}
 }
 
-// deriveDeepCopy_74 recursively copies the contents of src into dst.
-func deriveDeepCopy_74(dst, src *GenericResource) {
+// deriveDeepCopy_83 recursively copies the contents of src into dst.
+func deriveDeepCopy_83(dst, src *GenericResource) {
 	if src.DiscreteResourceSpec == nil {
 		dst.DiscreteResourceSpec = nil
 	} else {
 		dst.DiscreteResourceSpec = new(DiscreteGenericResource)
-		deriveDeepCopy_75(dst.DiscreteResourceSpec, src.DiscreteResourceSpec)
+		deriveDeepCopy_85(dst.DiscreteResourceSpec, src.DiscreteResourceSpec)
 	}
 	if src.Extensions != nil {
 		dst.Extensions = make(map[string]any, len(src.Extensions))
@@ -2608,8 +3172,20 @@ func deriveDeepCopy_74(dst, src *GenericResource) {
 	}
 }
 
-// deriveDeepCopy_75 recursively copies the contents of src into dst.
-func deriveDeepCopy_75(dst, src *DiscreteGenericResource) {
+// deriveDeepCopy_84 recursively copies the contents of src into dst.
+func deriveDeepCopy_84(dst, src *SensitiveSecretValidation) {
+	dst.MinLength = src.MinLength
+	dst.Pattern = src.Pattern
+	if src.Extensions != nil {
+		dst.Extensions = make(map[string]any, len(src.Extensions))
+		src.Extensions.DeepCopy(dst.Extensions)
+	} else {
+		dst.Extensions = nil
+	}
+}
+
+// deriveDeepCopy_85 recursively copies the contents of src into dst.
+func deriveDeepCopy_85(dst, src *DiscreteGenericResource) {
 	dst.Kind = src.Kind
 	dst.Value = src.Value
 	if src.Extensions != nil {
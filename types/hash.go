@@ -0,0 +1,58 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+)
+
+// ServiceHash returns a sha256 digest of service configuration, prebuild jobs, local_configs and sensitive entries
+// included, so orchestrators can tell if containers or prebuild pipelines must be re-run after an edit. The service is
+// serialized as JSON, which sorts mappings, and local configs read from a file also contribute the file content so
+// the digest changes with it. Scale and replicas are ignored, as changing those doesn't re-create containers
+func ServiceHash(s ServiceConfig) (string, error) {
+	s = s.Clone()
+	s.Scale = nil
+	if s.Deploy != nil {
+		s.Deploy.Replicas = nil
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	h.Write(data)
+	for _, name := range slices.Sorted(maps.Keys(s.LocalConfigs)) {
+		c := s.LocalConfigs[name]
+		if c.Source == "" {
+			continue
+		}
+		content, err := os.ReadFile(c.SourcePath())
+		if err != nil {
+			return "", fmt.Errorf("services.%s.local_configs.%s: %w", s.Name, name, err)
+		}
+		sum := sha256.Sum256(content)
+		fmt.Fprintf(h, "\x00%s\x00%x", name, sum)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,81 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestServiceHash(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "nginx.conf")
+	assert.NilError(t, os.WriteFile(source, []byte("worker_processes 1;"), 0o600))
+	replicas := 2
+	service := func() ServiceConfig {
+		return ServiceConfig{
+			Name:     "web",
+			Image:    "nginx",
+			Deploy:   &DeployConfig{},
+			Prebuild: []PrebuildJob{{Name: "Build", Commands: []PrebuildCommand{{Name: "Make", Command: "make"}}}},
+			LocalConfigs: map[string]LocalConfigConfig{
+				"nginx": {Source: "nginx.conf", ResolvedSource: source, Target: "/etc/nginx/nginx.conf"},
+				"motd":  {Content: "welcome", Target: "/etc/motd"},
+			},
+			Sensitive: map[string]SensitiveConfig{
+				"env": {Target: "/run/secrets/app.env", Secrets: []SensitiveSecret{{Source: "api_key"}}},
+			},
+		}
+	}
+	hash, err := ServiceHash(service())
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(hash, 64))
+
+	scaled := service()
+	scaled.Scale = &replicas
+	scaled.Deploy.Replicas = &replicas
+	same, err := ServiceHash(scaled)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(hash, same))
+	assert.Check(t, is.Equal(2, *scaled.Deploy.Replicas))
+
+	for name, edit := range map[string]func(s *ServiceConfig){
+		"prebuild": func(s *ServiceConfig) { s.Prebuild[0].Commands[0].Command = "make all" },
+		"content": func(s *ServiceConfig) {
+			s.LocalConfigs["motd"] = LocalConfigConfig{Content: "hello", Target: "/etc/motd"}
+		},
+		"sensitive": func(s *ServiceConfig) { s.Sensitive["env"] = SensitiveConfig{Target: "/run/secrets/.env"} },
+	} {
+		edited := service()
+		edit(&edited)
+		other, err := ServiceHash(edited)
+		assert.NilError(t, err)
+		assert.Check(t, hash != other, name)
+	}
+
+	assert.NilError(t, os.WriteFile(source, []byte("worker_processes 2;"), 0o600))
+	changed, err := ServiceHash(service())
+	assert.NilError(t, err)
+	assert.Check(t, hash != changed)
+
+	assert.NilError(t, os.Remove(source))
+	_, err = ServiceHash(service())
+	assert.Check(t, is.ErrorContains(err, "services.web.local_configs.nginx"))
+}
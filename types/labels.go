@@ -93,3 +93,13 @@ func (l *Labels) DecodeMapstructure(value interface{}) error {
 	}
 	return nil
 }
+
+// MatchesLabel returns true if labels satisfy selector, either `key=value` or `key` for label presence
+func (l Labels) MatchesLabel(selector string) bool {
+	key, value, hasValue := strings.Cut(selector, "=")
+	v, ok := l[key]
+	if !ok {
+		return false
+	}
+	return !hasValue || v == value
+}
@@ -0,0 +1,129 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/compose-spec/compose-go/v2/errdefs"
+)
+
+const (
+	// SELinuxRelabelShared relabels a local config or sensitive file as shared among containers, as `z`
+	SELinuxRelabelShared = "shared"
+	// SELinuxRelabelPrivate relabels a local config or sensitive file as private to the container, as `Z`
+	SELinuxRelabelPrivate = "private"
+)
+
+// selinuxOptions maps selinux relabeling to bind mount options
+var selinuxOptions = map[string]string{
+	SELinuxRelabelShared:  SELinuxShared,
+	SELinuxRelabelPrivate: SELinuxPrivate,
+}
+
+func checkSELinux(selinux string) error {
+	if _, ok := selinuxOptions[selinux]; selinux != "" && !ok {
+		return fmt.Errorf("unsupported selinux %q, must be one of %s, %s: %w", selinux, SELinuxRelabelShared, SELinuxRelabelPrivate, errdefs.ErrInvalid)
+	}
+	return nil
+}
+
+// SELinuxOption returns the `z` or `Z` bind mount option runners relabel the file with, empty when not relabeled
+func (c LocalConfigConfig) SELinuxOption() string {
+	return selinuxOptions[c.SELinux]
+}
+
+// Validate checks a local config is valid on its own: exactly one of source or content, an absolute target, a
+// valid file mode, numeric uid and gid and a supported selinux relabeling. User and group names are accepted once
+// resolved to a non-root id, see ResolvedUID, root being declared as `0`. When resolvePaths is set, source file must
+// exist
+func (c LocalConfigConfig) Validate(resolvePaths bool) error {
+	if (c.Source == "") == (c.Content == "") {
+		return fmt.Errorf("exactly one of source or content must be set: %w", errdefs.ErrInvalid)
+	}
+	if !path.IsAbs(c.Target) {
+		return fmt.Errorf("target %q must be an absolute path: %w", c.Target, errdefs.ErrInvalid)
+	}
+	if c.Mode != nil && !c.Mode.IsPermission() {
+		return fmt.Errorf("mode %s is not a valid file permission: %w", c.Mode, errdefs.ErrInvalid)
+	}
+	if err := checkNumericID("uid", c.UID, c.ResolvedUID); err != nil {
+		return err
+	}
+	if err := checkNumericID("gid", c.GID, c.ResolvedGID); err != nil {
+		return err
+	}
+	if err := checkSELinux(c.SELinux); err != nil {
+		return err
+	}
+	if resolvePaths && c.Source != "" {
+		if _, err := os.Stat(c.SourcePath()); err != nil {
+			return fmt.Errorf("source %q: %w", c.Source, err)
+		}
+	}
+	return nil
+}
+
+// SourcePath returns the path source file is read from: ResolvedSource when paths got resolved, Source otherwise
+func (c LocalConfigConfig) SourcePath() string {
+	if c.ResolvedSource != "" {
+		return c.ResolvedSource
+	}
+	return c.Source
+}
+
+// ContentTemplate parses Content according to TemplateEngine. It returns nil when no template engine is set
+func (c LocalConfigConfig) ContentTemplate() (*template.Template, error) {
+	switch c.TemplateEngine {
+	case "":
+		return nil, nil
+	case LocalConfigTemplateEngineGoTemplate:
+		return template.New("content").Option("missingkey=zero").Parse(c.Content)
+	default:
+		return nil, fmt.Errorf("unsupported template engine %q", c.TemplateEngine)
+	}
+}
+
+// RenderContent returns Content rendered by TemplateEngine with environment as data, or as-is when not set
+func (c LocalConfigConfig) RenderContent(environment Mapping) (string, error) {
+	tmpl, err := c.ContentTemplate()
+	if err != nil || tmpl == nil {
+		return c.Content, err
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, map[string]string(environment)); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func checkNumericID(attr string, id string, resolved int) error {
+	n, err := strconv.Atoi(id)
+	if err != nil && resolved > 0 {
+		// a name resolved by loader
+		return nil
+	}
+	if id != "" && (err != nil || n < 0) {
+		return fmt.Errorf("%s %q must be a non-negative number: %w", attr, id, errdefs.ErrInvalid)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestLocalConfigRenderContent(t *testing.T) {
+	c := LocalConfigConfig{
+		Content:        `level={{ if eq .DEBUG "true" }}debug{{ else }}info{{ end }}`,
+		TemplateEngine: LocalConfigTemplateEngineGoTemplate,
+	}
+	content, err := c.RenderContent(Mapping{"DEBUG": "true"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("level=debug", content))
+
+	content, err = c.RenderContent(Mapping{})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal("level=info", content))
+
+	c.TemplateEngine = ""
+	content, err = c.RenderContent(Mapping{"DEBUG": "true"})
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(c.Content, content))
+}
+
+func TestLocalConfigValidate(t *testing.T) {
+	mode := func(m FileMode) *FileMode { return &m }
+	source := filepath.Join(t.TempDir(), "app.conf")
+	assert.NilError(t, os.WriteFile(source, []byte("debug=true"), 0o600))
+	valid := LocalConfigConfig{Source: source, Target: "/etc/app.conf", UID: "1000", GID: "1000", Mode: mode(0o440)}
+	assert.NilError(t, valid.Validate(true))
+
+	// names resolved by loader Options.ResolveUserNames
+	named := valid
+	named.UID, named.GID, named.ResolvedUID, named.ResolvedGID = "appuser", "appgroup", 1000, 1000
+	assert.NilError(t, named.Validate(true))
+
+	tests := []struct {
+		name         string
+		config       func(c LocalConfigConfig) LocalConfigConfig
+		resolvePaths bool
+		err          string
+	}{
+		{
+			name:   "source and content",
+			config: func(c LocalConfigConfig) LocalConfigConfig { c.Content = "debug=true"; return c },
+			err:    "exactly one of source or content must be set",
+		},
+		{
+			name:   "neither source nor content",
+			config: func(c LocalConfigConfig) LocalConfigConfig { c.Source = ""; return c },
+			err:    "exactly one of source or content must be set",
+		},
+		{
+			name:   "relative target",
+			config: func(c LocalConfigConfig) LocalConfigConfig { c.Target = "etc/app.conf"; return c },
+			err:    `target "etc/app.conf" must be an absolute path`,
+		},
+		{
+			name:   "unsupported selinux",
+			config: func(c LocalConfigConfig) LocalConfigConfig { c.SELinux = "z"; return c },
+			err:    `unsupported selinux "z", must be one of shared, private`,
+		},
+		{
+			name:   "invalid mode",
+			config: func(c LocalConfigConfig) LocalConfigConfig { c.Mode = mode(0o1000); return c },
+			err:    "mode 01000 is not a valid file permission",
+		},
+		{
+			name:   "invalid uid",
+			config: func(c LocalConfigConfig) LocalConfigConfig { c.UID = "root"; return c },
+			err:    `uid "root" must be a non-negative number`,
+		},
+		{
+			name:   "unresolved uid name",
+			config: func(c LocalConfigConfig) LocalConfigConfig { c.UID, c.ResolvedUID = "appuser", 0; return c },
+			err:    `uid "appuser" must be a non-negative number`,
+		},
+		{
+			name:   "invalid gid",
+			config: func(c LocalConfigConfig) LocalConfigConfig { c.GID = "-1"; return c },
+			err:    `gid "-1" must be a non-negative number`,
+		},
+		{
+			name:         "missing source",
+			config:       func(c LocalConfigConfig) LocalConfigConfig { c.Source += ".missing"; return c },
+			resolvePaths: true,
+			err:          "no such file or directory",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.ErrorContains(t, tt.config(valid).Validate(tt.resolvePaths), tt.err)
+		})
+	}
+
+	missing := valid
+	missing.Source += ".missing"
+	assert.NilError(t, missing.Validate(false))
+}
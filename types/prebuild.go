@@ -0,0 +1,755 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"math"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/errdefs"
+	"github.com/compose-spec/compose-go/v2/utils"
+)
+
+const (
+	// ResultFieldRC is the exit status of a registered command
+	ResultFieldRC = "rc"
+	// ResultFieldStdout is the standard output of a registered command
+	ResultFieldStdout = "stdout"
+)
+
+var resultReferencePattern = regexp.MustCompile(`\$\{result\.([^.}]*)\.([^}]*)\}`)
+
+var sensitiveReferencePattern = regexp.MustCompile(`\$\{sensitive\.([^}]*)\}`)
+
+// ResultReference is a reference to a registered command outcome, written as `$${result.<register>.<field>}` in
+// compose files so interpolation leaves it as `${result.<register>.<field>}`
+type ResultReference struct {
+	Register string
+	Field    string
+}
+
+// ResultReferences returns the registered command outcomes this command refers to, in order of appearance
+func (c PrebuildCommand) ResultReferences() []ResultReference {
+	var refs []ResultReference
+	for _, m := range resultReferencePattern.FindAllStringSubmatch(c.Command, -1) {
+		refs = append(refs, ResultReference{Register: m[1], Field: m[2]})
+	}
+	return refs
+}
+
+// SensitiveReference is a reference from a command environment variable to a secret delivered by a sensitive
+// entry, written as `$${sensitive.<source>}` in compose files and resolved by runner
+type SensitiveReference struct {
+	Variable string
+	Source   string
+}
+
+// SensitiveReferences returns the sensitive secrets this command environment refers to, sorted by variable
+func (c PrebuildCommand) SensitiveReferences() []SensitiveReference {
+	var refs []SensitiveReference
+	for _, variable := range slices.Sorted(maps.Keys(c.Environment)) {
+		value := c.Environment[variable]
+		if value == nil {
+			continue
+		}
+		for _, m := range sensitiveReferencePattern.FindAllStringSubmatch(*value, -1) {
+			refs = append(refs, SensitiveReference{Variable: variable, Source: m[1]})
+		}
+	}
+	return refs
+}
+
+// PrebuildPlatformPattern is the `os/arch[/variant]` syntax of prebuild command platforms
+var PrebuildPlatformPattern = regexp.MustCompile(`^[a-z0-9]+/[a-z0-9_]+(/[a-z0-9]+)?$`)
+
+// RunsOnPlatform tells if command runs on a runner of platform, as `os/arch[/variant]`. A command without Platforms
+// runs on all platforms, and a platform declared without variant matches all variants, as for buildx
+func (c PrebuildCommand) RunsOnPlatform(platform string) bool {
+	if len(c.Platforms) == 0 {
+		return true
+	}
+	platform = strings.ToLower(platform)
+	for _, p := range c.Platforms {
+		p = strings.ToLower(p)
+		if p == platform || strings.HasPrefix(platform, p+"/") && strings.Count(p, "/") == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// resolvePrebuildEnvFiles loads prebuild commands env files into their environment, below variables commands set
+// inline. Variables are interpolated from project environment, then job and command environment
+func (s *ServiceConfig) resolvePrebuildEnvFiles(projectEnvironment Mapping, discardEnvFiles bool) error {
+	for i, job := range s.Prebuild {
+		for j, cmd := range job.Commands {
+			if len(cmd.EnvFiles) == 0 {
+				continue
+			}
+			inline := job.CommandEnvironment(cmd)
+			environment := Mapping{}
+			for _, envFile := range cmd.EnvFiles {
+				err := loadEnvFile(envFile, environment, func(k string) (string, bool) {
+					if v, ok := projectEnvironment.Resolve(k); ok {
+						return v, true
+					}
+					if v, ok := inline[k]; ok && v != nil {
+						return *v, true
+					}
+					return "", false
+				})
+				if err != nil {
+					return fmt.Errorf("services.%s.prebuild.%s.commands.%s: %w", s.Name, job.Name, cmd.Name, err)
+				}
+			}
+			cmd.Environment = environment.ToMappingWithEquals().OverrideBy(cmd.Environment)
+			if discardEnvFiles {
+				cmd.EnvFiles = nil
+			}
+			s.Prebuild[i].Commands[j] = cmd
+		}
+	}
+	return nil
+}
+
+// DefaultPrebuildShell is the shell prebuild commands run with when not setting one
+var DefaultPrebuildShell = ShellCommand{"/bin/sh", "-c"}
+
+// ShellArgs returns the arguments command runs as, command being passed to shell, DefaultPrebuildShell when not set
+func (c PrebuildCommand) ShellArgs() []string {
+	shell := c.Shell
+	if len(shell) == 0 {
+		shell = DefaultPrebuildShell
+	}
+	return append(slices.Clone(shell), c.Command)
+}
+
+// PrebuildCommandGroup is a run of consecutive commands sharing a group, for runners to output in a collapsible
+// log section
+type PrebuildCommandGroup struct {
+	// Group is the commands group, empty for a command without group
+	Group    string
+	Commands []PrebuildCommand
+}
+
+// CommandsByGroup returns job commands in order, consecutive commands of the same group being grouped together. A
+// command without group forms a group of its own
+func (j PrebuildJob) CommandsByGroup() []PrebuildCommandGroup {
+	var groups []PrebuildCommandGroup
+	for _, cmd := range j.Commands {
+		if n := len(groups); n > 0 && cmd.Group != "" && groups[n-1].Group == cmd.Group {
+			groups[n-1].Commands = append(groups[n-1].Commands, cmd)
+			continue
+		}
+		groups = append(groups, PrebuildCommandGroup{Group: cmd.Group, Commands: []PrebuildCommand{cmd}})
+	}
+	return groups
+}
+
+// DeepCopy returns a copy of the job sharing no state with it
+func (j PrebuildJob) DeepCopy() PrebuildJob {
+	n := PrebuildJob{}
+	deriveDeepCopyPrebuildJob(&n, &j)
+	return n
+}
+
+// MatrixRunsOn is the matrix key setting runs-on of expanded jobs
+const MatrixRunsOn = "runs-on"
+
+// ExpandMatrix returns a job per combination of Matrix values, in declaration order of values with keys sorted, or
+// the job itself when it has no matrix. Expanded jobs are named after the job suffixed with values, like
+// `Test (node:18)`, `runs-on` key setting their runner and other keys their environment. As runners are not run by a
+// shell, `${key}` references to other keys in runs-on, container image or cache key are replaced by their value,
+// those being escaped as `$${key}` in compose files. Each expanded job is a deep copy, so they share no commands
+func (j PrebuildJob) ExpandMatrix() ([]PrebuildJob, error) {
+	if len(j.Matrix) == 0 {
+		return []PrebuildJob{j}, nil
+	}
+	keys := slices.Sorted(maps.Keys(j.Matrix))
+	if _, ok := j.Matrix[MatrixRunsOn]; ok && j.Runner() != "" {
+		return nil, fmt.Errorf("runs-on and matrix.runs-on are mutually exclusive: %w", errdefs.ErrInvalid)
+	}
+	combinations := [][]string{{}}
+	for _, key := range keys {
+		if len(j.Matrix[key]) == 0 {
+			return nil, fmt.Errorf("matrix.%s must declare at least one value: %w", key, errdefs.ErrInvalid)
+		}
+		var next [][]string
+		for _, c := range combinations {
+			for _, v := range j.Matrix[key] {
+				next = append(next, append(slices.Clone(c), v))
+			}
+		}
+		combinations = next
+	}
+	jobs := make([]PrebuildJob, 0, len(combinations))
+	for _, values := range combinations {
+		job := j.DeepCopy()
+		job.Matrix = nil
+		job.Name = fmt.Sprintf("%s (%s)", j.Name, strings.Join(values, ", "))
+		for i, key := range keys {
+			if key == MatrixRunsOn {
+				job.RunsOn = values[i]
+				continue
+			}
+			if job.Environment == nil {
+				job.Environment = MappingWithEquals{}
+			}
+			job.Environment[key] = &values[i]
+			job.RunsOn = strings.ReplaceAll(job.RunsOn, "${"+key+"}", values[i])
+			if job.Container != nil {
+				job.Container.Image = strings.ReplaceAll(job.Container.Image, "${"+key+"}", values[i])
+			}
+			if job.Cache != nil {
+				job.Cache.Key = strings.ReplaceAll(job.Cache.Key, "${"+key+"}", values[i])
+			}
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// Runner returns the image job runs on, as set by runs-on or container image, empty for jobs running on the host
+func (j PrebuildJob) Runner() string {
+	if j.RunsOn == "" && j.Container != nil {
+		return j.Container.Image
+	}
+	return j.RunsOn
+}
+
+// PrebuildPullPolicies are the pull policies supported for prebuild runner images
+var PrebuildPullPolicies = []string{PullPolicyAlways, PullPolicyNever, PullPolicyIfNotPresent}
+
+// PullPolicy returns the policy runner image is pulled with, as set by ImagePullPolicy. Defaults to PullPolicyIfNotPresent
+func (j PrebuildJob) PullPolicy() string {
+	if j.ImagePullPolicy == "" {
+		return PullPolicyIfNotPresent
+	}
+	return j.ImagePullPolicy
+}
+
+// CommandEnvironment returns the environment cmd runs with: job environment overridden by command environment
+func (j PrebuildJob) CommandEnvironment(cmd PrebuildCommand) MappingWithEquals {
+	return MappingWithEquals{}.OverrideBy(j.Environment).OverrideBy(cmd.Environment)
+}
+
+const (
+	// PrebuildNeedSuccess runs the dependent job only when needed job succeeded, which is the default
+	PrebuildNeedSuccess = "success"
+	// PrebuildNeedFailure runs the dependent job only when needed job failed
+	PrebuildNeedFailure = "failure"
+	// PrebuildNeedCompleted runs the dependent job once needed job completed, regardless of its outcome
+	PrebuildNeedCompleted = "completed"
+)
+
+// PrebuildNeedStatuses are the supported statuses a prebuild job can need
+var PrebuildNeedStatuses = []string{PrebuildNeedSuccess, PrebuildNeedCompleted, PrebuildNeedFailure}
+
+// RequiredStatus returns the outcome of needed job for the dependent job to run, PrebuildNeedSuccess when not set
+func (n PrebuildNeed) RequiredStatus() string {
+	if n.Status == "" {
+		return PrebuildNeedSuccess
+	}
+	return n.Status
+}
+
+// Satisfied tells if the dependent job can run once needed job completed with succeeded outcome. Whatever the
+// status, dependent job is always ordered after needed job
+func (n PrebuildNeed) Satisfied(succeeded bool) bool {
+	switch n.RequiredStatus() {
+	case PrebuildNeedCompleted:
+		return true
+	case PrebuildNeedFailure:
+		return !succeeded
+	default:
+		return succeeded
+	}
+}
+
+// NeededJobs returns the names of the jobs this one needs
+func (j PrebuildJob) NeededJobs() []string {
+	names := make([]string, len(j.Needs))
+	for i, need := range j.Needs {
+		names[i] = need.Job
+	}
+	return names
+}
+
+// HasProfile returns true if the job is enabled by the given profiles, jobs without profiles always being enabled
+func (j PrebuildJob) HasProfile(profiles []string) bool {
+	return ServiceConfig{Profiles: j.Profiles}.HasProfile(profiles)
+}
+
+// prebuildRunnerImage resolves the image a prebuild job runs on, following `service:<name>` references.
+// An empty string means job runs on the host
+func (p *Project) prebuildRunnerImage(runsOn string) (string, error) {
+	target, ok := strings.CutPrefix(runsOn, ServicePrefix)
+	if !ok {
+		return runsOn, nil
+	}
+	service, err := p.GetService(target)
+	if err != nil {
+		return "", err
+	}
+	if service.Image != "" {
+		return service.Image, nil
+	}
+	if service.Build == nil {
+		return "", fmt.Errorf("service %q has neither an image nor a build section to run prebuild jobs on", target)
+	}
+	return fmt.Sprintf("%s-%s", p.Name, target), nil
+}
+
+// PrebuildImagesForService returns the distinct images prebuild jobs of a service run on, with `service:<name>`
+// references resolved. Jobs running on the host are ignored
+func (p *Project) PrebuildImagesForService(name string) ([]string, error) {
+	service, err := p.GetService(name)
+	if err != nil {
+		return nil, err
+	}
+	images := utils.Set[string]{}
+	for _, job := range service.Prebuild {
+		image, err := p.prebuildRunnerImage(job.Runner())
+		if err != nil {
+			return nil, fmt.Errorf("services.%s.prebuild.%s: %w", name, job.Name, err)
+		}
+		if image != "" {
+			images.Add(image)
+		}
+	}
+	result := images.Elements()
+	sort.Strings(result)
+	return result, nil
+}
+
+// ImageExistenceChecker tells if an image reference exists in its registry, typically backed by a registry client
+type ImageExistenceChecker interface {
+	Exists(ctx context.Context, ref string) (bool, error)
+}
+
+// PrebuildValidateImages checks images prebuild jobs run on exist using checker, and returns the sorted missing ones.
+// `service:<name>` references are resolved first, and skipped for services with a build section, as those images
+// are built locally. Jobs running on the host are ignored. This is an explicit opt-in, as checker typically
+// queries registries
+func (p *Project) PrebuildValidateImages(ctx context.Context, checker ImageExistenceChecker) ([]string, error) {
+	images := utils.Set[string]{}
+	for _, name := range p.ServiceNames() {
+		for _, job := range p.Services[name].Prebuild {
+			runsOn := job.Runner()
+			if target, ok := strings.CutPrefix(runsOn, ServicePrefix); ok {
+				if s, err := p.GetService(target); err == nil && s.Build != nil {
+					continue
+				}
+			}
+			image, err := p.prebuildRunnerImage(runsOn)
+			if err != nil {
+				return nil, fmt.Errorf("services.%s.prebuild.%s: %w", name, job.Name, err)
+			}
+			if image != "" {
+				images.Add(image)
+			}
+		}
+	}
+	var missing []string
+	for _, image := range slices.Sorted(slices.Values(images.Elements())) {
+		exists, err := checker.Exists(ctx, image)
+		if err != nil {
+			return nil, fmt.Errorf("checking image %s: %w", image, err)
+		}
+		if !exists {
+			missing = append(missing, image)
+		}
+	}
+	return missing, nil
+}
+
+// PrebuildJobNames returns sorted unique prebuild job names across services, and for names declared by more than
+// one service, the sorted services declaring those, so a flat listing can tell when names must be qualified
+func (p *Project) PrebuildJobNames() (names []string, ambiguous map[string][]string) {
+	services := map[string][]string{}
+	for _, name := range p.ServiceNames() {
+		for _, job := range p.Services[name].Prebuild {
+			if !slices.Contains(services[job.Name], name) {
+				services[job.Name] = append(services[job.Name], name)
+			}
+		}
+	}
+	ambiguous = map[string][]string{}
+	for job, declaring := range services {
+		names = append(names, job)
+		if len(declaring) > 1 {
+			ambiguous[job] = declaring
+		}
+	}
+	sort.Strings(names)
+	return names, ambiguous
+}
+
+// PrebuildUsesNetwork returns the distinct networks prebuild runners must join, as declared by jobs container
+// networks, and with serviceNetworks set, the networks of services declaring prebuild jobs, which jobs then share.
+// Networks must be declared as top-level networks
+func (p *Project) PrebuildUsesNetwork(serviceNetworks bool) ([]string, error) {
+	networks := utils.Set[string]{}
+	for _, name := range p.ServiceNames() {
+		service := p.Services[name]
+		for _, job := range service.Prebuild {
+			if job.Container == nil {
+				continue
+			}
+			for _, network := range job.Container.Networks {
+				if _, ok := p.Networks[network]; !ok {
+					return nil, fmt.Errorf("services.%s.prebuild.%s.container.networks: undefined network %q: %w", name, job.Name, network, errdefs.ErrInvalid)
+				}
+				networks.Add(network)
+			}
+		}
+		if serviceNetworks && len(service.Prebuild) > 0 {
+			for network := range service.Networks {
+				if _, ok := p.Networks[network]; !ok {
+					return nil, fmt.Errorf("services.%s.networks: undefined network %q: %w", name, network, errdefs.ErrInvalid)
+				}
+				networks.Add(network)
+			}
+		}
+	}
+	result := networks.Elements()
+	sort.Strings(result)
+	return result, nil
+}
+
+// PrebuildTopoSort returns service prebuild jobs in an order satisfying `needs`, jobs without dependencies between
+// them keeping declaration order. It fails when a job needs an undefined job, or on a dependency cycle
+func (s ServiceConfig) PrebuildTopoSort() ([]PrebuildJob, error) {
+	layers, err := prebuildJobLayers(s)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]PrebuildJob, 0, len(s.Prebuild))
+	for _, layer := range layers {
+		for _, i := range layer {
+			jobs = append(jobs, s.Prebuild[i])
+		}
+	}
+	return jobs, nil
+}
+
+// PrebuildGraph returns a service prebuild jobs as successive batches, jobs of a batch only needing jobs from
+// previous batches so they can run in parallel. Jobs keep declaration order within a batch
+func (p *Project) PrebuildGraph(service string) ([][]PrebuildJob, error) {
+	s, err := p.GetService(service)
+	if err != nil {
+		return nil, err
+	}
+	layers, err := prebuildJobLayers(s)
+	if err != nil {
+		return nil, err
+	}
+	batches := make([][]PrebuildJob, len(layers))
+	for i, layer := range layers {
+		for _, j := range layer {
+			batches[i] = append(batches[i], s.Prebuild[j])
+		}
+	}
+	return batches, nil
+}
+
+// prebuildJobLayers sorts a service prebuild jobs by `needs`, as successive layers of jobs indexes which can run
+// in parallel. Jobs keep declaration order within a layer
+func prebuildJobLayers(service ServiceConfig) ([][]int, error) {
+	index := map[string]int{}
+	for i, job := range service.Prebuild {
+		index[job.Name] = i
+	}
+	pending := map[int]int{}
+	dependents := map[int][]int{}
+	for i, job := range service.Prebuild {
+		for _, need := range job.NeededJobs() {
+			j, ok := index[need]
+			if !ok {
+				return nil, fmt.Errorf("services.%s.prebuild.%s: needs undefined job %q: %w", service.Name, job.Name, need, errdefs.ErrInvalid)
+			}
+			pending[i]++
+			dependents[j] = append(dependents[j], i)
+		}
+	}
+	var layers [][]int
+	var current []int
+	for i := range service.Prebuild {
+		if pending[i] == 0 {
+			current = append(current, i)
+		}
+	}
+	sorted := 0
+	for len(current) > 0 {
+		layers = append(layers, current)
+		sorted += len(current)
+		var next []int
+		for _, i := range current {
+			for _, d := range dependents[i] {
+				pending[d]--
+				if pending[d] == 0 {
+					next = append(next, d)
+				}
+			}
+		}
+		sort.Ints(next)
+		current = next
+	}
+	if sorted < len(service.Prebuild) {
+		var cycle []string
+		for i, job := range service.Prebuild {
+			if pending[i] > 0 {
+				cycle = append(cycle, job.Name)
+			}
+		}
+		return nil, fmt.Errorf("services.%s.prebuild: dependency cycle detected between jobs %s: %w", service.Name, strings.Join(cycle, ", "), errdefs.ErrInvalid)
+	}
+	return layers, nil
+}
+
+// PrebuildCommandID identifies a prebuild command within a project
+type PrebuildCommandID struct {
+	Service string `json:"service"`
+	Job     string `json:"job"`
+	Command string `json:"command"`
+}
+
+// PrebuildCommandsReferencingFile heuristically selects prebuild commands which arguments are file, either literally
+// or through a glob pattern, typically to only run jobs affected by a change. Commands running in a working_dir
+// containing file, and commands of a job which cache paths contain file, are selected as well. Commands are sorted
+// by service, then in declaration order
+func (p *Project) PrebuildCommandsReferencingFile(file string) []PrebuildCommandID {
+	file = path.Clean(filepath.ToSlash(file))
+	var matches []PrebuildCommandID
+	for _, name := range p.ServiceNames() {
+		for _, job := range p.Services[name].Prebuild {
+			cached := false
+			if job.Cache != nil {
+				cached = slices.ContainsFunc(job.Cache.Paths, func(p string) bool {
+					return pathReferencesFile(p, file)
+				})
+			}
+			for _, cmd := range job.Commands {
+				if cached || commandReferencesFile(cmd.Command, file) ||
+					cmd.WorkingDir != "" && pathReferencesFile(cmd.WorkingDir, file) {
+					matches = append(matches, PrebuildCommandID{Service: name, Job: job.Name, Command: cmd.Name})
+				}
+			}
+		}
+	}
+	return matches
+}
+
+// commandReferencesFile tells if an argument of command, or the value of a `--flag=value` argument, is file, a glob
+// pattern matching it, or an absolute path, as in a container, ending with file path components
+func commandReferencesFile(command string, file string) bool {
+	for _, arg := range strings.Fields(command) {
+		arg = strings.Trim(arg, `"'`)
+		if i := strings.LastIndex(arg, "="); i >= 0 {
+			arg = strings.Trim(arg[i+1:], `"'`)
+		}
+		if arg == "" {
+			continue
+		}
+		arg = path.Clean(arg)
+		if arg == file || path.IsAbs(arg) && strings.HasSuffix(arg, "/"+file) {
+			return true
+		}
+		if ok, _ := path.Match(arg, file); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// pathReferencesFile tells if p, a directory or a glob pattern, is or contains file. Project directory, as `.`,
+// contains all files and doesn't reference any in particular
+func pathReferencesFile(p string, file string) bool {
+	p = path.Clean(filepath.ToSlash(p))
+	if p == "." {
+		return false
+	}
+	if p == file || strings.HasPrefix(file, p+"/") {
+		return true
+	}
+	ok, _ := path.Match(p, file)
+	return ok
+}
+
+// PrebuildCommandLocation locates a command as the Index-th command of Service prebuild Job
+type PrebuildCommandLocation struct {
+	Service string
+	Job     string
+	Index   int
+}
+
+// ID returns a stable identifier for this command, derived from its Location, so runners can correlate results across
+// runs. Identifier is derived from command name, and from index for an unnamed command, so it doesn't change when
+// named commands get reordered. Location being set by loader normalization, commands declared programmatically must
+// set it for identifiers to be unique within project
+func (c PrebuildCommand) ID() string {
+	key := "index:" + strconv.Itoa(c.Location.Index)
+	if c.Name != "" {
+		key = "name:" + c.Name
+	}
+	sum := sha256.Sum256([]byte(strings.Join([]string{c.Location.Service, c.Location.Job, key}, "\x00")))
+	return hex.EncodeToString(sum[:8])
+}
+
+// CollapseCommandWhitespace normalizes a command by trimming and collapsing consecutive whitespaces
+func CollapseCommandWhitespace(command string) string {
+	return strings.Join(strings.Fields(command), " ")
+}
+
+// DuplicatePrebuildCommands groups prebuild commands declared in more than one place by their normalized command,
+// typically to detect copy-pasted commands. normalize defaults to CollapseCommandWhitespace. Commands within a group
+// are sorted by service, then in declaration order
+func (p *Project) DuplicatePrebuildCommands(normalize func(command string) string) map[string][]PrebuildCommandID {
+	if normalize == nil {
+		normalize = CollapseCommandWhitespace
+	}
+	commands := map[string][]PrebuildCommandID{}
+	for _, name := range p.ServiceNames() {
+		for _, job := range p.Services[name].Prebuild {
+			for _, cmd := range job.Commands {
+				key := normalize(cmd.Command)
+				commands[key] = append(commands[key], PrebuildCommandID{Service: name, Job: job.Name, Command: cmd.Name})
+			}
+		}
+	}
+	for key, ids := range commands {
+		if len(ids) < 2 {
+			delete(commands, key)
+		}
+	}
+	return commands
+}
+
+// PrebuildRequirements are the secrets and environment variables prebuild phase requires
+type PrebuildRequirements struct {
+	// Secrets are top-level secrets, either required by name or sourced from a required environment variable
+	Secrets []string `json:"secrets,omitempty"`
+	// Environment are required environment variables which are not sourcing a top-level secret
+	Environment []string `json:"environment,omitempty"`
+}
+
+// PrebuildRequiredSecrets collects secrets and environment variables prebuild jobs require across the project, so
+// access can be provisioned before running those. Requirements are declared by job `requires_env`, by job, command
+// or container environment variables without a value, which get passed through from runner environment, by command
+// env_file set to the file of a top-level secret, and by `${sensitive.<source>}` references in command environment.
+// Variables sourcing a top-level secret, by name or as its `environment`, are reported as that secret
+func (p *Project) PrebuildRequiredSecrets() PrebuildRequirements {
+	required := utils.Set[string]{}
+	secrets := utils.Set[string]{}
+	for _, service := range p.Services {
+		for _, job := range service.Prebuild {
+			required.AddAll(job.RequiresEnv...)
+			required.AddAll(passThroughVariables(job.Environment)...)
+			for _, cmd := range job.Commands {
+				required.AddAll(passThroughVariables(cmd.Environment)...)
+				for _, envFile := range cmd.EnvFiles {
+					if secret, ok := p.secretForFile(envFile.Path); ok {
+						secrets.Add(secret)
+					}
+				}
+				for _, ref := range cmd.SensitiveReferences() {
+					secrets.Add(ref.Source)
+				}
+			}
+			if job.Container != nil {
+				required.AddAll(passThroughVariables(job.Container.Environment)...)
+			}
+		}
+	}
+	environment := utils.Set[string]{}
+	for _, name := range required.Elements() {
+		secret, ok := p.secretForVariable(name)
+		if ok {
+			secrets.Add(secret)
+		} else {
+			environment.Add(name)
+		}
+	}
+	result := PrebuildRequirements{
+		Secrets:     secrets.Elements(),
+		Environment: environment.Elements(),
+	}
+	sort.Strings(result.Secrets)
+	sort.Strings(result.Environment)
+	return result
+}
+
+// secretForFile resolves the top-level secret defined by file path
+func (p *Project) secretForFile(path string) (string, bool) {
+	for _, secret := range p.SecretNames() {
+		if file := p.Secrets[secret].File; file != "" && filepath.Clean(file) == filepath.Clean(path) {
+			return secret, true
+		}
+	}
+	return "", false
+}
+
+// secretForVariable resolves the top-level secret named name or sourced from environment variable name
+func (p *Project) secretForVariable(name string) (string, bool) {
+	if _, ok := p.Secrets[name]; ok {
+		return name, true
+	}
+	for _, secret := range p.SecretNames() {
+		if p.Secrets[secret].Environment == name {
+			return secret, true
+		}
+	}
+	return "", false
+}
+
+func passThroughVariables(environment MappingWithEquals) []string {
+	var names []string
+	for name, value := range environment {
+		if value == nil {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Delay returns the delay before the nth retry, starting at 1. Without Max, delay is capped to the longest
+// time.Duration rather than overflowing
+func (b PrebuildRetryBackoff) Delay(retry int) time.Duration {
+	delay := float64(b.Initial)
+	if b.Factor > 1 && delay > 0 {
+		delay *= math.Pow(b.Factor, float64(retry-1))
+	}
+	if b.Max != nil && delay > float64(*b.Max) {
+		return time.Duration(*b.Max)
+	}
+	if delay >= math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(delay)
+}
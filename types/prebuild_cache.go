@@ -0,0 +1,47 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/compose-spec/compose-go/v2/errdefs"
+)
+
+// CacheChecksum returns the job cache key suffixed with a sha256 checksum of files content, like
+// `npm-3f2a...`, so that caches are invalidated when files such as lockfiles change. Files are hashed along with their
+// path, sorted, so the checksum doesn't depend on the order they are listed in
+func (j PrebuildJob) CacheChecksum(files ...string) (string, error) {
+	if j.Cache == nil {
+		return "", fmt.Errorf("prebuild job %s declares no cache: %w", j.Name, errdefs.ErrNotFound)
+	}
+	h := sha256.New()
+	h.Write([]byte(j.Cache.Key))
+	for _, file := range slices.Sorted(slices.Values(files)) {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "\x00%s\x00%d\x00", file, len(content))
+		h.Write(content)
+	}
+	return j.Cache.Key + "-" + hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,56 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/errdefs"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestPrebuildJobCacheChecksum(t *testing.T) {
+	dir := t.TempDir()
+	lock, sum := filepath.Join(dir, "package-lock.json"), filepath.Join(dir, "go.sum")
+	assert.NilError(t, os.WriteFile(lock, []byte(`{"lockfileVersion": 3}`), 0o600))
+	assert.NilError(t, os.WriteFile(sum, []byte("golang.org/x/sync v0.1.0 h1:abc"), 0o600))
+	job := PrebuildJob{Name: "Build", Cache: &PrebuildCache{Key: "deps", Paths: []string{"node_modules"}}}
+
+	checksum, err := job.CacheChecksum(lock, sum)
+	assert.NilError(t, err)
+	assert.Check(t, strings.HasPrefix(checksum, "deps-"))
+	assert.Check(t, is.Len(checksum, len("deps-")+64))
+
+	reordered, err := job.CacheChecksum(sum, lock)
+	assert.NilError(t, err)
+	assert.Check(t, is.Equal(checksum, reordered))
+
+	assert.NilError(t, os.WriteFile(lock, []byte(`{"lockfileVersion": 2}`), 0o600))
+	changed, err := job.CacheChecksum(lock, sum)
+	assert.NilError(t, err)
+	assert.Check(t, checksum != changed)
+
+	_, err = job.CacheChecksum(filepath.Join(dir, "missing.lock"))
+	assert.Check(t, os.IsNotExist(err))
+
+	_, err = PrebuildJob{Name: "Lint"}.CacheChecksum(lock)
+	assert.Check(t, is.ErrorIs(err, errdefs.ErrNotFound))
+}
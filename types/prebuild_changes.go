@@ -0,0 +1,67 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// MatchesChanges tells if any of the changed files matches job `when_changed` patterns. A job without
+// `when_changed` patterns always matches
+func (j PrebuildJob) MatchesChanges(files []string) bool {
+	if len(j.WhenChanged) == 0 {
+		return true
+	}
+	for _, file := range files {
+		file = path.Clean(filepath.ToSlash(file))
+		for _, pattern := range j.WhenChanged {
+			if matchGlob(pattern, file) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchGlob reports whether name matches the slash separated glob pattern, using path.Match syntax
+// for each path segment, while a `**` segment matches any number of segments
+func matchGlob(pattern string, name string) bool {
+	return matchSegments(strings.Split(path.Clean(pattern), "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern []string, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], name[0]); !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
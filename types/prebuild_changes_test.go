@@ -0,0 +1,39 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestPrebuildJobMatchesChanges(t *testing.T) {
+	job := PrebuildJob{Name: "Test", WhenChanged: []string{"src/**", "go.mod", "docs/*.md"}}
+
+	assert.Check(t, job.MatchesChanges([]string{"README.md", "src/pkg/parser/parser.go"}))
+	assert.Check(t, job.MatchesChanges([]string{"./go.mod"}))
+	assert.Check(t, job.MatchesChanges([]string{"docs/index.md"}))
+	assert.Check(t, job.MatchesChanges([]string{"src"}))
+
+	assert.Check(t, !job.MatchesChanges([]string{"README.md", "go.sum"}))
+	assert.Check(t, !job.MatchesChanges([]string{"docs/api/index.md"}))
+	assert.Check(t, !job.MatchesChanges([]string{"vendor/src/main.go"}))
+	assert.Check(t, !job.MatchesChanges(nil))
+
+	assert.Check(t, PrebuildJob{Name: "Always"}.MatchesChanges(nil))
+}
@@ -0,0 +1,111 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"go.yaml.in/yaml/v4"
+)
+
+// compactPrebuildCommands rewrites, within a marshalled project, the single command of prebuild jobs as a plain
+// string when it only sets a command, or a name equal to the command, and concurrency only setting a group as the
+// group name
+func compactPrebuildCommands(node *yaml.Node) {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	for _, service := range nodeValues(nodeValue(node, "services")) {
+		for _, key := range []string{"prebuild", CICDExtensionPrebuild} {
+			prebuild := nodeValue(service, key)
+			if prebuild != nil && prebuild.Kind == yaml.SequenceNode {
+				compactJobs(prebuild.Content)
+			}
+		}
+	}
+}
+
+func compactJobs(jobs []*yaml.Node) {
+	for _, job := range jobs {
+		if concurrency := nodeValue(job, "concurrency"); concurrency != nil && concurrency.Kind == yaml.MappingNode &&
+			len(concurrency.Content) == 2 && concurrency.Content[0].Value == "group" {
+			*concurrency = *concurrency.Content[1]
+		}
+		if needs := nodeValue(job, "needs"); needs != nil && needs.Kind == yaml.SequenceNode {
+			for i, need := range needs.Content {
+				if need.Kind == yaml.MappingNode && len(need.Content) == 2 && need.Content[0].Value == "job" {
+					needs.Content[i] = need.Content[1]
+				}
+			}
+		}
+		commands := nodeValue(job, "commands")
+		if commands == nil || commands.Kind != yaml.SequenceNode || len(commands.Content) != 1 {
+			continue
+		}
+		if command, ok := compactCommand(commands.Content[0]); ok {
+			commands.Content[0] = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: command}
+		}
+	}
+}
+
+func compactCommand(node *yaml.Node) (string, bool) {
+	if node.Kind != yaml.MappingNode {
+		return "", false
+	}
+	attrs := map[string]string{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i+1].Kind != yaml.ScalarNode {
+			return "", false
+		}
+		attrs[node.Content[i].Value] = node.Content[i+1].Value
+	}
+	command, ok := attrs["command"]
+	if !ok {
+		return "", false
+	}
+	name, named := attrs["name"]
+	if named && name != command {
+		return "", false
+	}
+	if named && len(attrs) != 2 || !named && len(attrs) != 1 {
+		return "", false
+	}
+	return command, true
+}
+
+// nodeValue returns the value node for key in a mapping node, nil if not found
+func nodeValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// nodeValues returns all value nodes of a mapping node
+func nodeValues(node *yaml.Node) []*yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	var values []*yaml.Node
+	for i := 1; i < len(node.Content); i += 2 {
+		values = append(values, node.Content[i])
+	}
+	return values
+}
@@ -0,0 +1,130 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	conditionResultPattern = regexp.MustCompile(`^\$\{result\.([a-zA-Z0-9_-]+)\.([^}]*)\}$`)
+	conditionEnvPattern    = regexp.MustCompile(`^\$\{env\.([a-zA-Z_][a-zA-Z0-9_]*)\}$`)
+)
+
+// PrebuildCondition is a parsed job or command `if` expression, comparing two operands with `==` or `!=`
+type PrebuildCondition struct {
+	Left     PrebuildOperand
+	Right    PrebuildOperand
+	NotEqual bool
+}
+
+// PrebuildOperand is a condition operand, either a registered command outcome written as
+// `$${result.<register>.<field>}`, an environment variable written as `$${env.<name>}`, or a literal value. Compose
+// files escape `$` so operands are left for runners to resolve, rather than interpolated by the loader
+type PrebuildOperand struct {
+	Result  *ResultReference
+	Env     string
+	Literal string
+}
+
+// PrebuildResult is the outcome of a registered command
+type PrebuildResult struct {
+	RC     int
+	Stdout string
+}
+
+// ParsePrebuildCondition parses a job or command `if` expression, like `${result.build.rc} == 0` once loaded from a
+// compose file declaring it as `$${result.build.rc} == 0`
+func ParsePrebuildCondition(expr string) (PrebuildCondition, error) {
+	var condition PrebuildCondition
+	left, right, ok := strings.Cut(expr, "==")
+	if !ok {
+		left, right, ok = strings.Cut(expr, "!=")
+		condition.NotEqual = true
+	}
+	if !ok || strings.Contains(right, "==") || strings.Contains(right, "!=") {
+		return condition, fmt.Errorf("condition %q must compare two operands with == or !=", expr)
+	}
+	var err error
+	if condition.Left, err = parsePrebuildOperand(left); err != nil {
+		return condition, fmt.Errorf("condition %q: %w", expr, err)
+	}
+	if condition.Right, err = parsePrebuildOperand(right); err != nil {
+		return condition, fmt.Errorf("condition %q: %w", expr, err)
+	}
+	return condition, nil
+}
+
+func parsePrebuildOperand(s string) (PrebuildOperand, error) {
+	s = strings.TrimSpace(s)
+	if m := conditionResultPattern.FindStringSubmatch(s); m != nil {
+		if m[2] != ResultFieldRC && m[2] != ResultFieldStdout {
+			return PrebuildOperand{}, fmt.Errorf("unsupported result field %q for register %q", m[2], m[1])
+		}
+		return PrebuildOperand{Result: &ResultReference{Register: m[1], Field: m[2]}}, nil
+	}
+	if m := conditionEnvPattern.FindStringSubmatch(s); m != nil {
+		return PrebuildOperand{Env: m[1]}, nil
+	}
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return PrebuildOperand{Literal: s[1 : len(s)-1]}, nil
+	}
+	if s == "" || strings.ContainsAny(s, " \t\"'") || strings.Contains(s, "${") {
+		return PrebuildOperand{}, fmt.Errorf("invalid operand %q", s)
+	}
+	return PrebuildOperand{Literal: s}, nil
+}
+
+// Literal tells if condition only compares literals, as when `if` used interpolated variables, so it can be evaluated
+// at load time
+func (c PrebuildCondition) Literal() bool {
+	return c.Left.Result == nil && c.Left.Env == "" && c.Right.Result == nil && c.Right.Env == ""
+}
+
+// Evaluate resolves condition operands against registered command outcomes and runner environment
+func (c PrebuildCondition) Evaluate(results map[string]PrebuildResult, env Mapping) (bool, error) {
+	left, err := c.Left.resolve(results, env)
+	if err != nil {
+		return false, err
+	}
+	right, err := c.Right.resolve(results, env)
+	if err != nil {
+		return false, err
+	}
+	return (left == right) != c.NotEqual, nil
+}
+
+func (o PrebuildOperand) resolve(results map[string]PrebuildResult, env Mapping) (string, error) {
+	switch {
+	case o.Result != nil:
+		result, ok := results[o.Result.Register]
+		if !ok {
+			return "", fmt.Errorf("no result registered as %q", o.Result.Register)
+		}
+		if o.Result.Field == ResultFieldRC {
+			return strconv.Itoa(result.RC), nil
+		}
+		return strings.TrimRight(result.Stdout, "\n"), nil
+	case o.Env != "":
+		return env[o.Env], nil
+	default:
+		return o.Literal, nil
+	}
+}
@@ -0,0 +1,77 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestPrebuildConditionEvaluate(t *testing.T) {
+	results := map[string]PrebuildResult{
+		"build": {RC: 0, Stdout: "v1.2.0\n"},
+	}
+	env := Mapping{"TARGET": "prod"}
+	tests := []struct {
+		expr     string
+		expected bool
+	}{
+		{expr: "${result.build.rc} == 0", expected: true},
+		{expr: "${result.build.rc} != 0", expected: false},
+		{expr: "${result.build.stdout} == 'v1.2.0'", expected: true},
+		{expr: `${env.TARGET} == "prod"`, expected: true},
+		{expr: "${env.TARGET}!=staging", expected: true},
+		{expr: "${env.UNSET} == ''", expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			condition, err := ParsePrebuildCondition(tt.expr)
+			assert.NilError(t, err)
+			actual, err := condition.Evaluate(results, env)
+			assert.NilError(t, err)
+			assert.Check(t, is.Equal(tt.expected, actual))
+		})
+	}
+}
+
+func TestParsePrebuildConditionInvalid(t *testing.T) {
+	for _, expr := range []string{
+		"${result.build.rc}",
+		"${result.build.rc} > 0",
+		"${result.build.rc} == 0 == 0",
+		"${result.build.stderr} == ''",
+		"${build} == 0",
+		"== 0",
+	} {
+		_, err := ParsePrebuildCondition(expr)
+		assert.Check(t, err != nil, expr)
+	}
+}
+
+func TestPrebuildConditionLiteral(t *testing.T) {
+	for expr, expected := range map[string]bool{
+		"main == 'main'":          true,
+		"${env.TARGET} == prod":   false,
+		"${result.build.rc} != 0": false,
+	} {
+		condition, err := ParsePrebuildCondition(expr)
+		assert.NilError(t, err)
+		assert.Check(t, is.Equal(expected, condition.Literal()), expr)
+	}
+}
@@ -0,0 +1,147 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// PrebuildGraphDOT exports prebuild jobs dependency graph as a Graphviz DOT document, with one node per job labeled
+// `service/job`. Edges are set by `needs`, and from all prebuild jobs of a service to jobs running on its image with
+// `runs-on: service:<name>`. Jobs and edges involved in a dependency cycle are rendered in red
+func (p *Project) PrebuildGraphDOT() (string, error) {
+	type edge struct {
+		from, to string
+	}
+	var nodes []string
+	var edges []edge
+	for _, name := range p.ServiceNames() {
+		service := p.Services[name]
+		jobs := map[string]bool{}
+		for _, job := range service.Prebuild {
+			jobs[job.Name] = true
+		}
+		for _, job := range service.Prebuild {
+			node := name + "/" + job.Name
+			nodes = append(nodes, node)
+			for _, need := range job.NeededJobs() {
+				if !jobs[need] {
+					return "", fmt.Errorf("services.%s.prebuild.%s: needs undefined job %q", name, job.Name, need)
+				}
+				edges = append(edges, edge{from: name + "/" + need, to: node})
+			}
+			target, ok := strings.CutPrefix(job.RunsOn, ServicePrefix)
+			if !ok || target == name {
+				continue
+			}
+			other, err := p.GetService(target)
+			if err != nil {
+				return "", fmt.Errorf("services.%s.prebuild.%s: %w", name, job.Name, err)
+			}
+			for _, dependency := range other.Prebuild {
+				edges = append(edges, edge{from: target + "/" + dependency.Name, to: node})
+			}
+		}
+	}
+
+	graph := map[string][]string{}
+	for _, e := range edges {
+		graph[e.from] = append(graph[e.from], e.to)
+	}
+	cycles := stronglyConnectedComponents(nodes, graph)
+
+	var buf bytes.Buffer
+	buf.WriteString("digraph prebuild {\n")
+	for _, node := range nodes {
+		attrs := "label=" + dotQuote(node)
+		if cycles[node] != 0 {
+			attrs += ", color=red"
+		}
+		fmt.Fprintf(&buf, "  %s [%s];\n", dotQuote(node), attrs)
+	}
+	for _, e := range edges {
+		fmt.Fprintf(&buf, "  %s -> %s", dotQuote(e.from), dotQuote(e.to))
+		if cycles[e.from] != 0 && cycles[e.from] == cycles[e.to] {
+			buf.WriteString(" [color=red]")
+		}
+		buf.WriteString(";\n")
+	}
+	buf.WriteString("}\n")
+	return buf.String(), nil
+}
+
+// stronglyConnectedComponents identifies nodes involved in a cycle, by assigning those an identifier shared with
+// other nodes of the same cycle. Nodes not involved in a cycle are not set
+func stronglyConnectedComponents(nodes []string, graph map[string][]string) map[string]int {
+	index := map[string]int{}
+	lowLink := map[string]int{}
+	onStack := map[string]bool{}
+	var stack []string
+	components := map[string]int{}
+	component := 0
+
+	var visit func(node string)
+	visit = func(node string) {
+		index[node] = len(index) + 1
+		lowLink[node] = index[node]
+		stack = append(stack, node)
+		onStack[node] = true
+		selfLoop := false
+		for _, next := range graph[node] {
+			if next == node {
+				selfLoop = true
+			}
+			if index[next] == 0 {
+				visit(next)
+				lowLink[node] = min(lowLink[node], lowLink[next])
+			} else if onStack[next] {
+				lowLink[node] = min(lowLink[node], index[next])
+			}
+		}
+		if lowLink[node] != index[node] {
+			return
+		}
+		var members []string
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			members = append(members, last)
+			if last == node {
+				break
+			}
+		}
+		if len(members) > 1 || selfLoop {
+			component++
+			for _, member := range members {
+				components[member] = component
+			}
+		}
+	}
+	for _, node := range nodes {
+		if index[node] == 0 {
+			visit(node)
+		}
+	}
+	return components
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
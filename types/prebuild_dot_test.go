@@ -0,0 +1,67 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+)
+
+func TestPrebuildGraphDOT(t *testing.T) {
+	p := &Project{
+		Name: "demo",
+		Services: Services{
+			"web": {
+				Name:  "web",
+				Build: &BuildConfig{Context: "."},
+				Prebuild: []PrebuildJob{
+					{Name: "Lint"},
+					{Name: "Test", Needs: []PrebuildNeed{{Job: "Lint"}}},
+					{Name: "Bundle", RunsOn: "service:api"},
+				},
+			},
+			"api": {
+				Name:  "api",
+				Image: "golang:1.21",
+				Prebuild: []PrebuildJob{
+					{Name: "Generate", Needs: []PrebuildNeed{{Job: "Vet"}}},
+					{Name: "Vet", Needs: []PrebuildNeed{{Job: "Generate"}}},
+				},
+			},
+		},
+	}
+	actual, err := p.PrebuildGraphDOT()
+	assert.NilError(t, err)
+	golden.Assert(t, actual, "prebuild.dot.golden")
+}
+
+func TestPrebuildGraphDOTUndefinedNeeds(t *testing.T) {
+	p := &Project{
+		Services: Services{
+			"web": {
+				Name: "web",
+				Prebuild: []PrebuildJob{
+					{Name: "Test", Needs: []PrebuildNeed{{Job: "Build"}}},
+				},
+			},
+		},
+	}
+	_, err := p.PrebuildGraphDOT()
+	assert.ErrorContains(t, err, `services.web.prebuild.Test: needs undefined job "Build"`)
+}
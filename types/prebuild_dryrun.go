@@ -0,0 +1,119 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"fmt"
+)
+
+// DryRunStep is a prebuild job, or one of its commands, as planned by Project.PrebuildDryRun
+type DryRunStep struct {
+	Job string `json:"job"`
+	// Command is the command name, empty for a job step
+	Command string `json:"command,omitempty"`
+	// Reason explains why a skipped step would not run
+	Reason string `json:"reason,omitempty"`
+}
+
+// DryRunPlan is the simulated execution of a service prebuild jobs. Both lists are in execution order
+type DryRunPlan struct {
+	Service  string       `json:"service"`
+	Executed []DryRunStep `json:"executed"`
+	Skipped  []DryRunStep `json:"skipped,omitempty"`
+}
+
+// PrebuildDryRun simulates execution of a service prebuild jobs, without running any command. Jobs are considered
+// in an order satisfying `needs`, including those disabled by `skip` or profiles the loader set apart. Commands `if`
+// conditions are evaluated against env, every command being assumed to succeed with an empty output, so a job needing
+// another with status failure is skipped. A job needing a skipped job is skipped as well
+func (p *Project) PrebuildDryRun(service string, env map[string]string) (DryRunPlan, error) {
+	s, err := p.GetService(service)
+	if err != nil {
+		return DryRunPlan{}, err
+	}
+	s.Prebuild = append(append([]PrebuildJob{}, s.Prebuild...), p.DisabledPrebuildJobs[service]...)
+	jobs, err := s.PrebuildTopoSort()
+	if err != nil {
+		return DryRunPlan{}, err
+	}
+
+	plan := DryRunPlan{Service: service, Executed: []DryRunStep{}}
+	skipped := map[string]bool{}
+	for _, job := range jobs {
+		if reason := p.dryRunJobSkipReason(job, skipped); reason != "" {
+			skipped[job.Name] = true
+			plan.Skipped = append(plan.Skipped, DryRunStep{Job: job.Name, Reason: reason})
+			continue
+		}
+		plan.Executed = append(plan.Executed, DryRunStep{Job: job.Name})
+		results := map[string]PrebuildResult{}
+		for i, cmd := range job.Commands {
+			step := DryRunStep{Job: job.Name, Command: cmd.Name, Reason: cmd.SkipReason}
+			if cmd.If != "" && step.Reason == "" {
+				condition, err := ParsePrebuildCondition(cmd.If)
+				if err != nil {
+					return DryRunPlan{}, fmt.Errorf("services.%s.prebuild.%s.commands[%d].if: %w", service, job.Name, i, err)
+				}
+				run, err := condition.Evaluate(results, env)
+				switch {
+				case err != nil:
+					step.Reason = fmt.Sprintf("if %q: %s", cmd.If, err)
+				case !run:
+					step.Reason = fmt.Sprintf("if %q is false", cmd.If)
+				}
+			}
+			if step.Reason != "" {
+				plan.Skipped = append(plan.Skipped, step)
+				continue
+			}
+			plan.Executed = append(plan.Executed, step)
+			if cmd.Register != "" {
+				results[cmd.Register] = PrebuildResult{}
+			}
+		}
+	}
+	return plan, nil
+}
+
+// prebuildProfiles are the profiles prebuild jobs are matched with
+func (p *Project) prebuildProfiles() []string {
+	if p.PrebuildProfiles != nil {
+		return p.PrebuildProfiles
+	}
+	return p.Profiles
+}
+
+// dryRunJobSkipReason tells why job would not run, given the jobs skipped so far, or an empty string if it would
+func (p *Project) dryRunJobSkipReason(job PrebuildJob, skipped map[string]bool) string {
+	switch {
+	case job.Skip:
+		return "skip is set"
+	case job.SkipReason != "":
+		return job.SkipReason
+	case !job.HasProfile(p.prebuildProfiles()):
+		return fmt.Sprintf("profiles %v are not enabled", job.Profiles)
+	}
+	for _, need := range job.Needs {
+		if skipped[need.Job] {
+			return fmt.Sprintf("needs skipped job %q", need.Job)
+		}
+		if !need.Satisfied(true) {
+			return fmt.Sprintf("needs job %q with status %s, while it is assumed to succeed", need.Job, need.RequiredStatus())
+		}
+	}
+	return ""
+}
@@ -0,0 +1,87 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestPrebuildDryRun(t *testing.T) {
+	p := &Project{
+		Services: Services{
+			"web": {
+				Name: "web",
+				Prebuild: []PrebuildJob{
+					{
+						Name:  "Deploy",
+						Needs: []PrebuildNeed{{Job: "Build"}},
+						Commands: []PrebuildCommand{
+							{Name: "Push", If: "${env.BRANCH} == main"},
+						},
+					},
+					{
+						Name: "Build",
+						Commands: []PrebuildCommand{
+							{Name: "Compile", Register: "compile"},
+							{Name: "Report", If: "${result.compile.rc} != 0"},
+						},
+					},
+					{
+						Name:  "Notify",
+						Needs: []PrebuildNeed{{Job: "Build", Status: PrebuildNeedFailure}},
+						Commands: []PrebuildCommand{
+							{Name: "Mail"},
+						},
+					},
+				},
+			},
+		},
+		DisabledPrebuildJobs: map[string][]PrebuildJob{
+			"web": {
+				{Name: "Bench", Profiles: []string{"perf"}},
+				{Name: "Publish", Needs: []PrebuildNeed{{Job: "Bench"}}},
+			},
+		},
+	}
+
+	plan, err := p.PrebuildDryRun("web", map[string]string{"BRANCH": "feature"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, plan, DryRunPlan{
+		Service: "web",
+		Executed: []DryRunStep{
+			{Job: "Build"},
+			{Job: "Build", Command: "Compile"},
+			{Job: "Deploy"},
+		},
+		Skipped: []DryRunStep{
+			{Job: "Build", Command: "Report", Reason: `if "${result.compile.rc} != 0" is false`},
+			{Job: "Bench", Reason: "profiles [perf] are not enabled"},
+			{Job: "Deploy", Command: "Push", Reason: `if "${env.BRANCH} == main" is false`},
+			{Job: "Notify", Reason: `needs job "Build" with status failure, while it is assumed to succeed`},
+			{Job: "Publish", Reason: `needs skipped job "Bench"`},
+		},
+	})
+
+	plan, err = p.PrebuildDryRun("web", map[string]string{"BRANCH": "main"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, plan.Executed[len(plan.Executed)-1], DryRunStep{Job: "Deploy", Command: "Push"})
+
+	_, err = p.PrebuildDryRun("db", nil)
+	assert.ErrorContains(t, err, `no such service: db`)
+}
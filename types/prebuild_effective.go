@@ -0,0 +1,79 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/compose-spec/compose-go/v2/errdefs"
+)
+
+// EffectiveJob is a prebuild job as it runs, once loaded, with runner and command environments resolved
+type EffectiveJob struct {
+	// Service is the name of the service declaring the job
+	Service string `json:"service"`
+	PrebuildJob
+	// Image is the image job runs on, `service:<name>` references being resolved. Empty when job runs on the host
+	Image string `json:"image,omitempty"`
+	// PullPolicy is the policy runner image is pulled with, see PrebuildJob.PullPolicy
+	PullPolicy string `json:"pull_policy"`
+	// Commands shadow job commands, with effective environment
+	Commands []EffectiveCommand `json:"commands,omitempty"`
+}
+
+// EffectiveCommand is a prebuild command with the environment it runs with
+type EffectiveCommand struct {
+	PrebuildCommand
+	// Environment shadows command environment, merged with job environment, see PrebuildJob.CommandEnvironment
+	Environment MappingWithEquals `json:"environment,omitempty"`
+}
+
+// PrebuildEffectiveConfig returns a prebuild job of a service as it runs, for introspection. Project is expected to
+// be loaded with normalization so inheritance, defaults and profiles are applied. It returns ErrDisabled when the
+// job, or service, got disabled by profiles or skip, and ErrNotFound when it isn't declared
+func (p *Project) PrebuildEffectiveConfig(service, job string) (EffectiveJob, error) {
+	s, err := p.GetService(service)
+	if err != nil {
+		return EffectiveJob{}, err
+	}
+	i := slices.IndexFunc(s.Prebuild, func(j PrebuildJob) bool { return j.Name == job })
+	if i < 0 {
+		if slices.ContainsFunc(p.DisabledPrebuildJobs[service], func(j PrebuildJob) bool { return j.Name == job }) {
+			return EffectiveJob{}, fmt.Errorf("services.%s.prebuild.%s: %w", service, job, errdefs.ErrDisabled)
+		}
+		return EffectiveJob{}, fmt.Errorf("services.%s.prebuild.%s: %w", service, job, errdefs.ErrNotFound)
+	}
+	j := s.Prebuild[i].DeepCopy()
+	image, err := p.prebuildRunnerImage(j.Runner())
+	if err != nil {
+		return EffectiveJob{}, fmt.Errorf("services.%s.prebuild.%s: %w", service, job, err)
+	}
+	effective := EffectiveJob{
+		Service:     service,
+		PrebuildJob: j,
+		Image:       image,
+		PullPolicy:  j.PullPolicy(),
+	}
+	for _, cmd := range j.Commands {
+		effective.Commands = append(effective.Commands, EffectiveCommand{
+			PrebuildCommand: cmd,
+			Environment:     j.CommandEnvironment(cmd),
+		})
+	}
+	return effective, nil
+}
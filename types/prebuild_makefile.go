@@ -0,0 +1,114 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var makeTargetInvalidChars = regexp.MustCompile(`[^a-z0-9_.-]+`)
+
+// makeTargetName computes a Make target name for a prebuild job, as the index-th job exported, `job-<n>`, when service
+// and job names have no valid character
+func makeTargetName(service, job string, index int) string {
+	name := strings.ToLower(service + "-" + job)
+	name = makeTargetInvalidChars.ReplaceAllString(name, "-")
+	if name = strings.Trim(name, "-"); name == "" {
+		return fmt.Sprintf("job-%d", index)
+	}
+	return name
+}
+
+// PrebuildToMakefile exports prebuild jobs as a Makefile, with one target per job and prerequisites set by `needs`.
+// Jobs declaring `runs-on` have their commands executed by `docker run` with project working directory mounted.
+// Recipes run with `.ONESHELL` so multi-line commands are supported, and abort on first failure.
+func (p *Project) PrebuildToMakefile() ([]byte, error) {
+	type target struct {
+		name    string
+		needs   []string
+		recipes []string
+	}
+	var targets []target
+	owners := map[string]string{}
+	for _, name := range p.ServiceNames() {
+		service := p.Services[name]
+		jobs := map[string]string{}
+		for _, job := range service.Prebuild {
+			t := makeTargetName(name, job.Name, len(owners))
+			if owner, ok := owners[t]; ok {
+				return nil, fmt.Errorf("services.%s.prebuild.%s: Makefile target %q conflicts with %s", name, job.Name, t, owner)
+			}
+			owners[t] = fmt.Sprintf("services.%s.prebuild.%s", name, job.Name)
+			jobs[job.Name] = t
+		}
+		for _, job := range service.Prebuild {
+			image, err := p.prebuildRunnerImage(job.Runner())
+			if err != nil {
+				return nil, fmt.Errorf("services.%s.prebuild.%s: %w", name, job.Name, err)
+			}
+			t := target{name: jobs[job.Name]}
+			for _, need := range job.NeededJobs() {
+				n, ok := jobs[need]
+				if !ok {
+					return nil, fmt.Errorf("services.%s.prebuild.%s: needs undefined job %q", name, job.Name, need)
+				}
+				t.needs = append(t.needs, n)
+			}
+			for _, cmd := range job.Commands {
+				t.recipes = append(t.recipes, makeRecipe(image, cmd.Command))
+			}
+			targets = append(targets, t)
+		}
+	}
+
+	names := make([]string, len(targets))
+	for i, t := range targets {
+		names[i] = t.name
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# Prebuild jobs exported from compose project %q\n\n", p.Name)
+	buf.WriteString("SHELL := /bin/sh\n")
+	buf.WriteString(".SHELLFLAGS := -ec\n")
+	buf.WriteString(".ONESHELL:\n\n")
+	fmt.Fprintf(&buf, ".PHONY: %s\n\n", strings.Join(append([]string{"all"}, names...), " "))
+	fmt.Fprintf(&buf, "all: %s\n", strings.Join(names, " "))
+	for _, t := range targets {
+		buf.WriteString("\n")
+		buf.WriteString(strings.TrimRight(fmt.Sprintf("%s: %s", t.name, strings.Join(t.needs, " ")), " "))
+		buf.WriteString("\n")
+		for _, recipe := range t.recipes {
+			for _, line := range strings.Split(recipe, "\n") {
+				fmt.Fprintf(&buf, "\t%s\n", line)
+			}
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// makeRecipe renders a command as a Make recipe, running inside image when set
+func makeRecipe(image string, command string) string {
+	command = strings.ReplaceAll(strings.TrimRight(command, "\n"), "$", "$$")
+	if image == "" {
+		return command
+	}
+	quoted := "'" + strings.ReplaceAll(command, "'", `'\''`) + "'"
+	return fmt.Sprintf(`docker run --rm -v "$(CURDIR)":/workspace -w /workspace %s sh -c %s`, image, quoted)
+}
@@ -0,0 +1,122 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+	"gotest.tools/v3/golden"
+)
+
+func TestPrebuildToMakefile(t *testing.T) {
+	p := &Project{
+		Name: "demo",
+		Services: Services{
+			"web": {
+				Name:  "web",
+				Build: &BuildConfig{Context: "."},
+				Prebuild: []PrebuildJob{
+					{
+						Name:   "Test Suite",
+						RunsOn: "node:18",
+						Needs:  []PrebuildNeed{{Job: "Lint"}},
+						Commands: []PrebuildCommand{
+							{Name: "Install", Command: "npm ci"},
+							{Name: "Test", Command: "echo 'running' && npm test -- --reporter=$REPORTER"},
+						},
+					},
+					{
+						Name: "Lint",
+						Commands: []PrebuildCommand{
+							{Name: "Lint", Command: "npm run lint\nnpm run format:check"},
+						},
+					},
+				},
+			},
+			"api": {
+				Name:  "api",
+				Image: "golang:1.21",
+				Prebuild: []PrebuildJob{
+					{
+						Name:     "go/vet",
+						RunsOn:   "service:api",
+						Commands: []PrebuildCommand{{Name: "Vet", Command: "go vet ./..."}},
+					},
+				},
+			},
+		},
+	}
+	actual, err := p.PrebuildToMakefile()
+	assert.NilError(t, err)
+	golden.Assert(t, string(actual), "prebuild.Makefile.golden")
+}
+
+func TestPrebuildToMakefileUndefinedNeeds(t *testing.T) {
+	p := &Project{
+		Services: Services{
+			"web": {
+				Name: "web",
+				Prebuild: []PrebuildJob{
+					{Name: "Test", Needs: []PrebuildNeed{{Job: "Build"}}},
+				},
+			},
+		},
+	}
+	_, err := p.PrebuildToMakefile()
+	assert.ErrorContains(t, err, `services.web.prebuild.Test: needs undefined job "Build"`)
+}
+
+func TestPrebuildToMakefileTargetConflict(t *testing.T) {
+	p := &Project{
+		Services: Services{
+			"web": {
+				Name: "web",
+				Prebuild: []PrebuildJob{
+					{Name: "Unit Tests"},
+					{Name: "unit-tests"},
+				},
+			},
+		},
+	}
+	_, err := p.PrebuildToMakefile()
+	assert.ErrorContains(t, err, `Makefile target "web-unit-tests" conflicts with services.web.prebuild.Unit Tests`)
+}
+
+func TestPrebuildToMakefileInvalidTargetName(t *testing.T) {
+	p := &Project{
+		Services: Services{
+			"ci": {
+				Name: "ci",
+				Prebuild: []PrebuildJob{
+					{Name: "Build", Commands: []PrebuildCommand{{Name: "Compile", Command: "make"}}},
+				},
+			},
+			"€": {
+				Name: "€",
+				Prebuild: []PrebuildJob{
+					{Name: "!!", Commands: []PrebuildCommand{{Name: "Check", Command: "make check"}}},
+				},
+			},
+		},
+	}
+	out, err := p.PrebuildToMakefile()
+	assert.NilError(t, err)
+	assert.Check(t, strings.Contains(string(out), "\nall: ci-build job-1\n"), string(out))
+	assert.Check(t, strings.Contains(string(out), "\njob-1:\n\tmake check\n"), string(out))
+}
@@ -0,0 +1,62 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"maps"
+	"slices"
+)
+
+// PrebuildMergeFrom returns a copy of project with cicdez attributes of other merged into services declared by
+// both projects. Prebuild jobs are merged by name, local_configs and sensitive entries by key, other's definition
+// replacing the original one, as when loading an override file. Services only declared by other are ignored,
+// unless addServices is set.
+func (p *Project) PrebuildMergeFrom(other *Project, addServices bool) *Project {
+	merged := p.deepCopy()
+	if merged.Services == nil {
+		merged.Services = Services{}
+	}
+	for _, name := range other.ServiceNames() {
+		service := other.Services[name]
+		o := service.deepCopy()
+		s, ok := merged.Services[name]
+		if !ok {
+			if addServices {
+				merged.Services[name] = *o
+			}
+			continue
+		}
+		for _, job := range o.Prebuild {
+			i := slices.IndexFunc(s.Prebuild, func(j PrebuildJob) bool { return j.Name == job.Name })
+			if i < 0 {
+				s.Prebuild = append(s.Prebuild, job)
+			} else {
+				s.Prebuild[i] = job
+			}
+		}
+		if len(o.LocalConfigs) > 0 && s.LocalConfigs == nil {
+			s.LocalConfigs = map[string]LocalConfigConfig{}
+		}
+		maps.Copy(s.LocalConfigs, o.LocalConfigs)
+		if len(o.Sensitive) > 0 && s.Sensitive == nil {
+			s.Sensitive = map[string]SensitiveConfig{}
+		}
+		maps.Copy(s.Sensitive, o.Sensitive)
+		merged.Services[name] = s
+	}
+	return merged
+}
@@ -0,0 +1,86 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func TestPrebuildMergeFrom(t *testing.T) {
+	base := &Project{
+		Services: Services{
+			"web": {
+				Name:  "web",
+				Image: "nginx",
+				Prebuild: []PrebuildJob{
+					{Name: "Lint", Commands: []PrebuildCommand{{Name: "Vet", Command: "go vet ./..."}}},
+					{Name: "Test", Commands: []PrebuildCommand{{Name: "Unit", Command: "go test ./..."}}},
+				},
+				LocalConfigs: map[string]LocalConfigConfig{
+					"app": {Source: "./app.conf", Target: "/etc/app.conf"},
+				},
+			},
+		},
+	}
+	overlay := &Project{
+		Services: Services{
+			"web": {
+				Name:  "web",
+				Image: "ignored",
+				Prebuild: []PrebuildJob{
+					{Name: "Test", Commands: []PrebuildCommand{{Name: "Race", Command: "go test -race ./..."}}},
+					{Name: "Build", Commands: []PrebuildCommand{{Name: "Compile", Command: "go build ./..."}}},
+				},
+				LocalConfigs: map[string]LocalConfigConfig{
+					"app": {Source: "./team.conf", Target: "/etc/app.conf"},
+				},
+				Sensitive: map[string]SensitiveConfig{
+					"env": {Target: "/run/secrets/env", Secrets: []SensitiveSecret{{Source: "token"}}},
+				},
+			},
+			"api": {
+				Name:     "api",
+				Image:    "golang",
+				Prebuild: []PrebuildJob{{Name: "Test"}},
+			},
+		},
+	}
+
+	merged := base.PrebuildMergeFrom(overlay, false)
+	web := merged.Services["web"]
+	assert.Check(t, is.Equal("nginx", web.Image))
+	assert.DeepEqual(t, web.Prebuild, []PrebuildJob{
+		{Name: "Lint", Commands: []PrebuildCommand{{Name: "Vet", Command: "go vet ./..."}}},
+		{Name: "Test", Commands: []PrebuildCommand{{Name: "Race", Command: "go test -race ./..."}}},
+		{Name: "Build", Commands: []PrebuildCommand{{Name: "Compile", Command: "go build ./..."}}},
+	})
+	assert.Check(t, is.Equal("./team.conf", web.LocalConfigs["app"].Source))
+	assert.Check(t, is.Len(web.Sensitive, 1))
+	_, ok := merged.Services["api"]
+	assert.Check(t, !ok)
+
+	// receiver is left unchanged
+	assert.Check(t, is.Len(base.Services["web"].Prebuild, 2))
+	assert.Check(t, is.Equal("./app.conf", base.Services["web"].LocalConfigs["app"].Source))
+
+	merged = base.PrebuildMergeFrom(overlay, true)
+	assert.Check(t, is.Equal("golang", merged.Services["api"].Image))
+	assert.Check(t, is.Len(merged.Services["api"].Prebuild, 1))
+}
@@ -0,0 +1,78 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"fmt"
+
+	"github.com/compose-spec/compose-go/v2/errdefs"
+)
+
+// WithPrebuildReordered returns a new project with prebuild jobs of service reordered according to order, which must
+// list each job name exactly once, and place jobs after the ones they need. The original project is left unchanged
+func (p *Project) WithPrebuildReordered(service string, order []string) (*Project, error) {
+	newProject := p.deepCopy()
+	if err := newProject.reorderPrebuild(service, order); err != nil {
+		return nil, err
+	}
+	return newProject, nil
+}
+
+// PrebuildReorder reorders prebuild jobs of a service in place, as WithPrebuildReordered does. Project is left
+// unchanged when order is invalid
+//
+// Deprecated: use WithPrebuildReordered, which doesn't mutate a project other goroutines may be using
+func (p *Project) PrebuildReorder(service string, order []string) error {
+	return p.reorderPrebuild(service, order)
+}
+
+func (p *Project) reorderPrebuild(service string, order []string) error {
+	s, err := p.GetService(service)
+	if err != nil {
+		return err
+	}
+	jobs := map[string]PrebuildJob{}
+	for _, job := range s.Prebuild {
+		jobs[job.Name] = job
+	}
+	if len(order) != len(s.Prebuild) {
+		return fmt.Errorf("services.%s.prebuild: order lists %d jobs, service declares %d: %w", service, len(order), len(s.Prebuild), errdefs.ErrInvalid)
+	}
+	position := map[string]int{}
+	for i, name := range order {
+		if _, ok := jobs[name]; !ok {
+			return fmt.Errorf("services.%s.prebuild: order refers to undefined job %q: %w", service, name, errdefs.ErrInvalid)
+		}
+		if _, ok := position[name]; ok {
+			return fmt.Errorf("services.%s.prebuild: order lists job %q more than once: %w", service, name, errdefs.ErrInvalid)
+		}
+		position[name] = i
+	}
+	reordered := make([]PrebuildJob, len(order))
+	for i, name := range order {
+		job := jobs[name]
+		for _, need := range job.NeededJobs() {
+			if j, ok := position[need]; ok && j > i {
+				return fmt.Errorf("services.%s.prebuild.%s: order places job before %q it needs: %w", service, name, need, errdefs.ErrInvalid)
+			}
+		}
+		reordered[i] = job
+	}
+	s.Prebuild = reordered
+	p.Services[service] = s
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func reorderProject() *Project {
+	return &Project{
+		Services: Services{
+			"web": {
+				Name: "web",
+				Prebuild: []PrebuildJob{
+					{Name: "Lint"},
+					{Name: "Build"},
+					{Name: "Test", Needs: []PrebuildNeed{{Job: "Build"}}},
+				},
+			},
+		},
+	}
+}
+
+func jobNames(jobs []PrebuildJob) []string {
+	var names []string
+	for _, job := range jobs {
+		names = append(names, job.Name)
+	}
+	return names
+}
+
+func TestPrebuildReorder(t *testing.T) {
+	p := reorderProject()
+	err := p.PrebuildReorder("web", []string{"Build", "Test", "Lint"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"Build", "Test", "Lint"}, jobNames(p.Services["web"].Prebuild))
+	assert.DeepEqual(t, []string{"Build"}, p.Services["web"].Prebuild[1].NeededJobs())
+}
+
+func TestPrebuildReorderInvalid(t *testing.T) {
+	tests := []struct {
+		order    []string
+		expected string
+	}{
+		{order: []string{"Test", "Build", "Lint"}, expected: `services.web.prebuild.Test: order places job before "Build" it needs`},
+		{order: []string{"Build", "Test"}, expected: "order lists 2 jobs, service declares 3"},
+		{order: []string{"Build", "Test", "Deploy"}, expected: `order refers to undefined job "Deploy"`},
+		{order: []string{"Build", "Build", "Test"}, expected: `order lists job "Build" more than once`},
+	}
+	for _, tt := range tests {
+		p := reorderProject()
+		err := p.PrebuildReorder("web", tt.order)
+		assert.Check(t, is.ErrorContains(err, tt.expected))
+		assert.DeepEqual(t, []string{"Lint", "Build", "Test"}, jobNames(p.Services["web"].Prebuild))
+	}
+}
+
+func TestWithPrebuildReordered(t *testing.T) {
+	p := reorderProject()
+	reordered, err := p.WithPrebuildReordered("web", []string{"Build", "Test", "Lint"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"Build", "Test", "Lint"}, jobNames(reordered.Services["web"].Prebuild))
+	assert.DeepEqual(t, []string{"Lint", "Build", "Test"}, jobNames(p.Services["web"].Prebuild))
+
+	_, err = p.WithPrebuildReordered("web", []string{"Test", "Build", "Lint"})
+	assert.Check(t, is.ErrorContains(err, `order places job before "Build" it needs`))
+}
@@ -0,0 +1,79 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/compose-spec/compose-go/v2/errdefs"
+	"github.com/compose-spec/compose-go/v2/utils"
+)
+
+// PrebuildRequiredServices returns the services job of service requires to be running, with their dependencies,
+// sorted. As prebuild jobs run before service is built, job can't require service itself or a service depending on it
+func (p *Project) PrebuildRequiredServices(service string, job PrebuildJob) ([]string, error) {
+	required := utils.NewSet[string]()
+	for _, name := range job.Services {
+		if name == service {
+			return nil, fmt.Errorf("services.%s.prebuild.%s.services: job can't require service %q it runs before: %w",
+				service, job.Name, name, errdefs.ErrInvalid)
+		}
+		if _, ok := p.DisabledServices[name]; ok {
+			return nil, fmt.Errorf("services.%s.prebuild.%s.services: required service %q is disabled: %w", service, job.Name, name, errdefs.ErrInvalid)
+		}
+		if _, ok := p.Services[name]; !ok {
+			return nil, fmt.Errorf("services.%s.prebuild.%s.services: undefined service %q: %w", service, job.Name, name, errdefs.ErrNotFound)
+		}
+		err := p.ForEachService([]string{name}, func(dependency string, _ *ServiceConfig) error {
+			if dependency == service {
+				return fmt.Errorf("services.%s.prebuild.%s.services: service %q depends on %s, which job runs before: %w",
+					service, job.Name, name, service, errdefs.ErrInvalid)
+			}
+			required.Add(dependency)
+			return nil
+		}, IncludeDependencies)
+		if err != nil {
+			return nil, err
+		}
+	}
+	names := required.Elements()
+	slices.Sort(names)
+	return names, nil
+}
+
+// PrebuildJobProject returns the minimal project needed to run prebuild job of service: the services it requires,
+// with their dependencies, see PrebuildRequiredServices, other services being disabled. Resources are kept, as job
+// container may use those. The original project is left unchanged
+func (p *Project) PrebuildJobProject(service string, job string) (*Project, error) {
+	s, err := p.GetService(service)
+	if err != nil {
+		return nil, err
+	}
+	i := slices.IndexFunc(s.Prebuild, func(j PrebuildJob) bool { return j.Name == job })
+	if i < 0 {
+		return nil, fmt.Errorf("services.%s.prebuild: undefined job %q: %w", service, job, errdefs.ErrNotFound)
+	}
+	names, err := p.PrebuildRequiredServices(service, s.Prebuild[i])
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return p.WithServicesDisabled(p.ServiceNames()...), nil
+	}
+	return p.WithSelectedServices(names, IncludeDependencies)
+}
@@ -0,0 +1,86 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/errdefs"
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+)
+
+func servicesProject() *Project {
+	return &Project{
+		Services: Services{
+			"web": {
+				Name:      "web",
+				DependsOn: DependsOnConfig{"api": {Condition: ServiceConditionStarted, Required: true}},
+				Prebuild: []PrebuildJob{
+					{Name: "Lint"},
+					{Name: "Integration", Services: []string{"api"}},
+				},
+			},
+			"api": {
+				Name:      "api",
+				DependsOn: DependsOnConfig{"db": {Condition: ServiceConditionHealthy, Required: true}},
+				Prebuild:  []PrebuildJob{{Name: "Test", Services: []string{"db", "redis"}}},
+			},
+			"db":    {Name: "db"},
+			"redis": {Name: "redis"},
+		},
+		Networks: Networks{"ci": {}},
+	}
+}
+
+func TestPrebuildRequiredServices(t *testing.T) {
+	p := servicesProject()
+	names, err := p.PrebuildRequiredServices("api", p.Services["api"].Prebuild[0])
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"db", "redis"}, names)
+
+	names, err = p.PrebuildRequiredServices("web", p.Services["web"].Prebuild[1])
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"api", "db"}, names)
+
+	_, err = p.PrebuildRequiredServices("db", PrebuildJob{Name: "Seed", Services: []string{"api"}})
+	assert.Check(t, is.ErrorContains(err, `services.db.prebuild.Seed.services: service "api" depends on db, which job runs before`))
+	assert.Check(t, is.ErrorIs(err, errdefs.ErrInvalid))
+
+	_, err = p.PrebuildRequiredServices("db", PrebuildJob{Name: "Seed", Services: []string{"db"}})
+	assert.Check(t, is.ErrorContains(err, `job can't require service "db" it runs before`))
+
+	_, err = p.PrebuildRequiredServices("db", PrebuildJob{Name: "Seed", Services: []string{"cache"}})
+	assert.Check(t, is.ErrorContains(err, `services.db.prebuild.Seed.services: undefined service "cache"`))
+	assert.Check(t, is.ErrorIs(err, errdefs.ErrNotFound))
+}
+
+func TestPrebuildJobProject(t *testing.T) {
+	p := servicesProject()
+	sub, err := p.PrebuildJobProject("api", "Test")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"db", "redis"}, sub.ServiceNames())
+	assert.Check(t, is.Len(sub.Networks, 1))
+	assert.Check(t, is.Len(p.Services, 4))
+
+	sub, err = p.PrebuildJobProject("web", "Lint")
+	assert.NilError(t, err)
+	assert.Check(t, is.Len(sub.Services, 0))
+
+	_, err = p.PrebuildJobProject("web", "Deploy")
+	assert.Check(t, is.ErrorContains(err, `services.web.prebuild: undefined job "Deploy"`))
+}
@@ -0,0 +1,54 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import "slices"
+
+// PrebuildStage is a named group of prebuild jobs
+type PrebuildStage struct {
+	Name string          `json:"name"`
+	Jobs []PrebuildJobID `json:"jobs"`
+}
+
+// PrebuildJobID identifies a prebuild job within a project
+type PrebuildJobID struct {
+	Service string `json:"service"`
+	Job     string `json:"job"`
+}
+
+// PrebuildStages groups prebuild jobs by stage. Stages are ordered as declared by Project.Stages, or by first
+// appearance when not set, services being considered by name and jobs in declaration order. Stages without jobs
+// are omitted, and jobs without a stage are grouped in an unnamed stage.
+func (p *Project) PrebuildStages() []PrebuildStage {
+	order := slices.Clone(p.Stages)
+	jobs := map[string][]PrebuildJobID{}
+	for _, name := range p.ServiceNames() {
+		for _, job := range p.Services[name].Prebuild {
+			if !slices.Contains(order, job.Stage) {
+				order = append(order, job.Stage)
+			}
+			jobs[job.Stage] = append(jobs[job.Stage], PrebuildJobID{Service: name, Job: job.Name})
+		}
+	}
+	var stages []PrebuildStage
+	for _, stage := range order {
+		if len(jobs[stage]) > 0 {
+			stages = append(stages, PrebuildStage{Name: stage, Jobs: jobs[stage]})
+		}
+	}
+	return stages
+}
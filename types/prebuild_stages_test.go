@@ -0,0 +1,65 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func stagesProject() *Project {
+	return &Project{
+		Services: Services{
+			"web": {
+				Name: "web",
+				Prebuild: []PrebuildJob{
+					{Name: "Test", Stage: "test"},
+					{Name: "Build", Stage: "build"},
+				},
+			},
+			"api": {
+				Name: "api",
+				Prebuild: []PrebuildJob{
+					{Name: "Deploy", Stage: "deploy"},
+					{Name: "Test", Stage: "test"},
+					{Name: "Notify"},
+				},
+			},
+		},
+	}
+}
+
+func TestPrebuildStagesImplicitOrder(t *testing.T) {
+	assert.DeepEqual(t, stagesProject().PrebuildStages(), []PrebuildStage{
+		{Name: "deploy", Jobs: []PrebuildJobID{{Service: "api", Job: "Deploy"}}},
+		{Name: "test", Jobs: []PrebuildJobID{{Service: "api", Job: "Test"}, {Service: "web", Job: "Test"}}},
+		{Name: "", Jobs: []PrebuildJobID{{Service: "api", Job: "Notify"}}},
+		{Name: "build", Jobs: []PrebuildJobID{{Service: "web", Job: "Build"}}},
+	})
+}
+
+func TestPrebuildStagesExplicitOrder(t *testing.T) {
+	p := stagesProject()
+	p.Stages = []string{"lint", "build", "test", "deploy"}
+	assert.DeepEqual(t, p.PrebuildStages(), []PrebuildStage{
+		{Name: "build", Jobs: []PrebuildJobID{{Service: "web", Job: "Build"}}},
+		{Name: "test", Jobs: []PrebuildJobID{{Service: "api", Job: "Test"}, {Service: "web", Job: "Test"}}},
+		{Name: "deploy", Jobs: []PrebuildJobID{{Service: "api", Job: "Deploy"}}},
+		{Name: "", Jobs: []PrebuildJobID{{Service: "api", Job: "Notify"}}},
+	})
+}
@@ -0,0 +1,220 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"go.yaml.in/yaml/v4"
+)
+
+// TektonAPIVersion is the Tekton API version of resources produced by Project.PrebuildToTektonPipeline
+const TektonAPIVersion = "tekton.dev/v1"
+
+type tektonResource struct {
+	APIVersion string         `yaml:"apiVersion"`
+	Kind       string         `yaml:"kind"`
+	Metadata   tektonMetadata `yaml:"metadata"`
+	Spec       any            `yaml:"spec"`
+}
+
+type tektonMetadata struct {
+	Name string `yaml:"name"`
+}
+
+type tektonTaskSpec struct {
+	Steps []tektonStep `yaml:"steps"`
+}
+
+type tektonStep struct {
+	Name            string      `yaml:"name"`
+	Image           string      `yaml:"image"`
+	ImagePullPolicy string      `yaml:"imagePullPolicy,omitempty"`
+	WorkingDir      string      `yaml:"workingDir,omitempty"`
+	Env             []tektonEnv `yaml:"env,omitempty"`
+	Script          string      `yaml:"script"`
+	Timeout         string      `yaml:"timeout,omitempty"`
+	OnError         string      `yaml:"onError,omitempty"`
+}
+
+type tektonEnv struct {
+	Name  string `yaml:"name"`
+	Value string `yaml:"value"`
+}
+
+type tektonPipelineSpec struct {
+	Tasks []tektonPipelineTask `yaml:"tasks"`
+}
+
+type tektonPipelineTask struct {
+	Name     string        `yaml:"name"`
+	TaskRef  tektonTaskRef `yaml:"taskRef"`
+	RunAfter []string      `yaml:"runAfter,omitempty"`
+	Timeout  string        `yaml:"timeout,omitempty"`
+}
+
+type tektonTaskRef struct {
+	Name string `yaml:"name"`
+}
+
+var tektonPullPolicies = map[string]string{
+	PullPolicyAlways:       "Always",
+	PullPolicyNever:        "Never",
+	PullPolicyIfNotPresent: "IfNotPresent",
+}
+
+var tektonNameInvalidChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// tektonName computes a Kubernetes resource name out of parts
+func tektonName(parts ...string) string {
+	name := strings.ToLower(strings.Join(parts, "-"))
+	name = tektonNameInvalidChars.ReplaceAllString(name, "-")
+	return strings.Trim(name, "-")
+}
+
+// PrebuildToTektonPipeline exports prebuild jobs as Tekton resources: one Task per job, with a step per command, and
+// a Pipeline named after the project running those tasks, ordered by `runAfter` as set by `needs`. Tekton has no host
+// runner, so all jobs must declare `runs-on`, and dependent tasks only run once needed tasks succeeded. Container
+// volumes, user and networks aren't exported
+func (p *Project) PrebuildToTektonPipeline() ([]byte, error) {
+	var tasks []tektonResource
+	pipeline := tektonPipelineSpec{}
+	owners := map[string]string{}
+	for _, name := range p.ServiceNames() {
+		service := p.Services[name]
+		jobs := map[string]string{}
+		for _, job := range service.Prebuild {
+			t := tektonName(name, job.Name)
+			if owner, ok := owners[t]; ok {
+				return nil, fmt.Errorf("services.%s.prebuild.%s: Tekton task %q conflicts with %s", name, job.Name, t, owner)
+			}
+			owners[t] = fmt.Sprintf("services.%s.prebuild.%s", name, job.Name)
+			jobs[job.Name] = t
+		}
+		for _, job := range service.Prebuild {
+			id := fmt.Sprintf("services.%s.prebuild.%s", name, job.Name)
+			if job.Runner() == "" {
+				return nil, fmt.Errorf("%s: Tekton tasks require runs-on to be set", id)
+			}
+			image, err := p.prebuildRunnerImage(job.Runner())
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", id, err)
+			}
+			spec, err := tektonTask(id, job, image)
+			if err != nil {
+				return nil, err
+			}
+			tasks = append(tasks, tektonResource{
+				APIVersion: TektonAPIVersion,
+				Kind:       "Task",
+				Metadata:   tektonMetadata{Name: tektonName(p.Name, jobs[job.Name])},
+				Spec:       spec,
+			})
+
+			task := tektonPipelineTask{
+				Name:    jobs[job.Name],
+				TaskRef: tektonTaskRef{Name: tektonName(p.Name, jobs[job.Name])},
+			}
+			for _, need := range job.Needs {
+				n, ok := jobs[need.Job]
+				if !ok {
+					return nil, fmt.Errorf("%s: needs undefined job %q", id, need.Job)
+				}
+				if need.RequiredStatus() != PrebuildNeedSuccess {
+					return nil, fmt.Errorf("%s: needs %s with status %s, which Tekton runAfter can't express", id, need.Job, need.RequiredStatus())
+				}
+				task.RunAfter = append(task.RunAfter, n)
+			}
+			if job.Timeout != nil {
+				task.Timeout = job.Timeout.String()
+			}
+			pipeline.Tasks = append(pipeline.Tasks, task)
+		}
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	for _, task := range tasks {
+		if err := encoder.Encode(task); err != nil {
+			return nil, err
+		}
+	}
+	err := encoder.Encode(tektonResource{
+		APIVersion: TektonAPIVersion,
+		Kind:       "Pipeline",
+		Metadata:   tektonMetadata{Name: tektonName(p.Name)},
+		Spec:       pipeline,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// tektonTask converts a prebuild job into a Tekton Task spec, running commands as steps. Unnamed commands get named
+// after their index, as `step-<n>`
+func tektonTask(id string, job PrebuildJob, image string) (tektonTaskSpec, error) {
+	spec := tektonTaskSpec{}
+	steps := map[string]string{}
+	for i, cmd := range job.Commands {
+		command := cmd.Name
+		if command == "" {
+			command = fmt.Sprintf("[%d]", i)
+		}
+		name := tektonName(cmd.Name)
+		if name == "" {
+			name = fmt.Sprintf("step-%d", i)
+		}
+		if other, ok := steps[name]; ok {
+			return spec, fmt.Errorf("%s.commands.%s: Tekton step %q conflicts with %s", id, command, name, other)
+		}
+		steps[name] = command
+		step := tektonStep{
+			Name:            name,
+			Image:           image,
+			ImagePullPolicy: tektonPullPolicies[job.PullPolicy()],
+			WorkingDir:      cmd.WorkingDir,
+			Script:          cmd.Command,
+		}
+		if step.WorkingDir == "" && job.Container != nil {
+			step.WorkingDir = job.Container.WorkingDir
+		}
+		environment := job.CommandEnvironment(cmd)
+		for _, key := range slices.Sorted(maps.Keys(environment)) {
+			if v := environment[key]; v != nil {
+				step.Env = append(step.Env, tektonEnv{Name: key, Value: *v})
+			}
+		}
+		if cmd.Timeout != nil {
+			step.Timeout = cmd.Timeout.String()
+		}
+		if cmd.ContinueOnError {
+			step.OnError = "continue"
+		}
+		spec.Steps = append(spec.Steps, step)
+	}
+	return spec, nil
+}
@@ -0,0 +1,141 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
+	"gotest.tools/v3/golden"
+)
+
+func TestPrebuildToTektonPipeline(t *testing.T) {
+	timeout := Duration(10 * time.Minute)
+	commandTimeout := Duration(2 * time.Minute)
+	reporter := "junit"
+	p := &Project{
+		Name: "demo",
+		Services: Services{
+			"web": {
+				Name:  "web",
+				Build: &BuildConfig{Context: "."},
+				Prebuild: []PrebuildJob{
+					{
+						Name:        "Test Suite",
+						RunsOn:      "node:18",
+						Needs:       []PrebuildNeed{{Job: "Lint"}},
+						Timeout:     &timeout,
+						Environment: MappingWithEquals{"CI": &reporter, "UNSET": nil},
+						Container:   &PrebuildContainer{WorkingDir: "/workspace"},
+						Commands: []PrebuildCommand{
+							{Name: "Install", Command: "npm ci"},
+							{
+								Name:        "Test",
+								Command:     "npm test -- --reporter=$REPORTER",
+								Environment: MappingWithEquals{"REPORTER": &reporter},
+								WorkingDir:  "/workspace/app",
+								Timeout:     &commandTimeout,
+							},
+						},
+					},
+					{
+						Name:            "Lint",
+						RunsOn:          "node:18",
+						ImagePullPolicy: PullPolicyAlways,
+						Commands: []PrebuildCommand{
+							{Name: "Lint", Command: "npm run lint\nnpm run format:check", ContinueOnError: true},
+						},
+					},
+				},
+			},
+			"api": {
+				Name:  "api",
+				Image: "golang:1.21",
+				Prebuild: []PrebuildJob{
+					{
+						Name:     "go/vet",
+						RunsOn:   "service:api",
+						Commands: []PrebuildCommand{{Name: "Vet", Command: "go vet ./..."}},
+					},
+				},
+			},
+		},
+	}
+	actual, err := p.PrebuildToTektonPipeline()
+	assert.NilError(t, err)
+	golden.Assert(t, string(actual), "prebuild.tekton.yaml.golden")
+}
+
+func TestPrebuildToTektonPipelineHostJob(t *testing.T) {
+	p := &Project{
+		Services: Services{
+			"web": {
+				Name:     "web",
+				Prebuild: []PrebuildJob{{Name: "Test"}},
+			},
+		},
+	}
+	_, err := p.PrebuildToTektonPipeline()
+	assert.ErrorContains(t, err, "services.web.prebuild.Test: Tekton tasks require runs-on to be set")
+}
+
+func TestPrebuildToTektonPipelineNeedsStatus(t *testing.T) {
+	p := &Project{
+		Services: Services{
+			"web": {
+				Name: "web",
+				Prebuild: []PrebuildJob{
+					{Name: "Test", RunsOn: "node"},
+					{Name: "Report", RunsOn: "node", Needs: []PrebuildNeed{{Job: "Test", Status: PrebuildNeedFailure}}},
+				},
+			},
+		},
+	}
+	_, err := p.PrebuildToTektonPipeline()
+	assert.ErrorContains(t, err, "services.web.prebuild.Report: needs Test with status failure, which Tekton runAfter can't express")
+}
+
+func TestPrebuildToTektonPipelineUnnamedSteps(t *testing.T) {
+	p := &Project{
+		Name: "demo",
+		Services: Services{
+			"web": {
+				Name: "web",
+				Prebuild: []PrebuildJob{{
+					Name:   "Test",
+					RunsOn: "node",
+					Commands: []PrebuildCommand{
+						{Command: "npm ci"},
+						{Name: "Unit", Command: "npm test"},
+						{Name: "!!", Command: "npm run lint"},
+					},
+				}},
+			},
+		},
+	}
+	out, err := p.PrebuildToTektonPipeline()
+	assert.NilError(t, err)
+	assert.Check(t, is.Contains(string(out), "- name: step-0\n"))
+	assert.Check(t, is.Contains(string(out), "- name: unit\n"))
+	assert.Check(t, is.Contains(string(out), "- name: step-2\n"))
+
+	p.Services["web"].Prebuild[0].Commands[1].Name = "Step 0"
+	_, err = p.PrebuildToTektonPipeline()
+	assert.ErrorContains(t, err, `services.web.prebuild.Test.commands.Step 0: Tekton step "step-0" conflicts with [0]`)
+}
@@ -0,0 +1,546 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/errdefs"
+	"github.com/google/go-cmp/cmp"
+	"gotest.tools/v3/assert"
+)
+
+func TestPrebuildImagesForService(t *testing.T) {
+	p := &Project{
+		Name: "demo",
+		Services: Services{
+			"web": {
+				Name: "web",
+				Prebuild: []PrebuildJob{
+					{Name: "Test", RunsOn: "node:18"},
+					{Name: "Lint", RunsOn: "node:18"},
+					{Name: "Host"},
+					{Name: "Migrate", RunsOn: "service:db"},
+					{Name: "Integration", RunsOn: "service:api"},
+				},
+			},
+			"db": {
+				Name:  "db",
+				Image: "postgres:15",
+			},
+			"api": {
+				Name:  "api",
+				Build: &BuildConfig{Context: "."},
+			},
+		},
+	}
+	images, err := p.PrebuildImagesForService("web")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"demo-api", "node:18", "postgres:15"}, images)
+
+	_, err = p.PrebuildImagesForService("unknown")
+	assert.ErrorIs(t, err, errdefs.ErrNotFound)
+}
+
+func TestPrebuildCommandsReferencingFile(t *testing.T) {
+	p := &Project{
+		Services: Services{
+			"web": {
+				Name: "web",
+				Prebuild: []PrebuildJob{
+					{
+						Name: "Test",
+						Commands: []PrebuildCommand{
+							{Name: "Unit", Command: "go test ./pkg/parser/parser_test.go"},
+							{Name: "Lint", Command: "golangci-lint run"},
+						},
+					},
+					{
+						Name: "Docs",
+						Commands: []PrebuildCommand{
+							{Name: "Check", Command: `markdownlint "docs/*.md"`},
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.DeepEqual(t, p.PrebuildCommandsReferencingFile("pkg/parser/parser_test.go"), []PrebuildCommandID{
+		{Service: "web", Job: "Test", Command: "Unit"},
+	})
+	assert.DeepEqual(t, p.PrebuildCommandsReferencingFile("./docs/index.md"), []PrebuildCommandID{
+		{Service: "web", Job: "Docs", Command: "Check"},
+	})
+	assert.Check(t, len(p.PrebuildCommandsReferencingFile("README.md")) == 0)
+	// arguments only partially matching file don't reference it
+	assert.Check(t, len(p.PrebuildCommandsReferencingFile("parser_test.go")) == 0)
+	assert.Check(t, len(p.PrebuildCommandsReferencingFile("parser/parser_test.go")) == 0)
+}
+
+func TestPrebuildCommandsReferencingFilePaths(t *testing.T) {
+	p := &Project{
+		Services: Services{
+			"web": {
+				Name: "web",
+				Prebuild: []PrebuildJob{
+					{
+						Name: "Frontend",
+						Commands: []PrebuildCommand{
+							{Name: "Build", Command: "npm run build", WorkingDir: "./frontend"},
+							{Name: "Lint", Command: "npm run lint", WorkingDir: "/src"},
+							{Name: "Format", Command: "prettier --check data.go", WorkingDir: "."},
+							{Name: "Config", Command: "tsc --project=frontend/tsconfig.json"},
+							{Name: "Serve", Command: "node /src/server/main.js"},
+						},
+					},
+					{
+						Name:  "Deps",
+						Cache: &PrebuildCache{Key: "deps", Paths: []string{"vendor/*"}},
+						Commands: []PrebuildCommand{
+							{Name: "Vendor", Command: "go mod vendor"},
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.DeepEqual(t, p.PrebuildCommandsReferencingFile("frontend/src/app.ts"), []PrebuildCommandID{
+		{Service: "web", Job: "Frontend", Command: "Build"},
+	})
+	assert.DeepEqual(t, p.PrebuildCommandsReferencingFile("vendor/modules.txt"), []PrebuildCommandID{
+		{Service: "web", Job: "Deps", Command: "Vendor"},
+	})
+	assert.DeepEqual(t, p.PrebuildCommandsReferencingFile("frontend/tsconfig.json"), []PrebuildCommandID{
+		{Service: "web", Job: "Frontend", Command: "Build"},
+		{Service: "web", Job: "Frontend", Command: "Config"},
+	})
+	assert.DeepEqual(t, p.PrebuildCommandsReferencingFile("server/main.js"), []PrebuildCommandID{
+		{Service: "web", Job: "Frontend", Command: "Serve"},
+	})
+	// working_dir `.` and a mere substring of an argument don't reference file
+	assert.Check(t, len(p.PrebuildCommandsReferencingFile("a.go")) == 0)
+	assert.Check(t, len(p.PrebuildCommandsReferencingFile("frontend2/app.ts")) == 0)
+}
+
+func TestPrebuildCommandID(t *testing.T) {
+	at := func(c PrebuildCommand, service string, job string, index int) PrebuildCommand {
+		c.Location = PrebuildCommandLocation{Service: service, Job: job, Index: index}
+		return c
+	}
+	build := PrebuildCommand{Name: "Compile", Command: "make"}
+	test := PrebuildCommand{Name: "Test", Command: "make test"}
+	unnamed := PrebuildCommand{Command: "make lint"}
+
+	// stable across loads, and when named commands get reordered
+	assert.Equal(t, at(build, "web", "Build", 0).ID(), at(PrebuildCommand{Name: "Compile", Command: "make all"}, "web", "Build", 1).ID())
+	assert.Equal(t, at(unnamed, "web", "Build", 2).ID(), at(unnamed, "web", "Build", 2).ID())
+	assert.Assert(t, at(unnamed, "web", "Build", 2).ID() != at(unnamed, "web", "Build", 3).ID())
+
+	ids := map[string]bool{}
+	for _, c := range []PrebuildCommand{
+		at(build, "web", "Build", 0),
+		at(test, "web", "Build", 1),
+		at(unnamed, "web", "Build", 2),
+		at(build, "web", "Release", 0),
+		at(build, "api", "Build", 0),
+		at(PrebuildCommand{Name: "index:2"}, "web", "Build", 0),
+	} {
+		assert.Assert(t, !ids[c.ID()], "duplicate id %s", c.ID())
+		ids[c.ID()] = true
+	}
+}
+
+func TestDuplicatePrebuildCommands(t *testing.T) {
+	p := &Project{
+		Services: Services{
+			"web": {
+				Name: "web",
+				Prebuild: []PrebuildJob{
+					{Name: "Test", Commands: []PrebuildCommand{
+						{Name: "Unit", Command: "go test ./..."},
+						{Name: "Vet", Command: "go vet ./..."},
+					}},
+				},
+			},
+			"api": {
+				Name: "api",
+				Prebuild: []PrebuildJob{
+					{Name: "Check", Commands: []PrebuildCommand{
+						{Name: "Tests", Command: "  go  test ./... "},
+						{Name: "Lint", Command: "golangci-lint run"},
+					}},
+				},
+			},
+		},
+	}
+	assert.DeepEqual(t, p.DuplicatePrebuildCommands(nil), map[string][]PrebuildCommandID{
+		"go test ./...": {
+			{Service: "api", Job: "Check", Command: "Tests"},
+			{Service: "web", Job: "Test", Command: "Unit"},
+		},
+	})
+
+	exact := func(command string) string { return command }
+	assert.Check(t, len(p.DuplicatePrebuildCommands(exact)) == 0)
+}
+
+func TestPrebuildRequiredSecrets(t *testing.T) {
+	token := "static"
+	p := &Project{
+		Services: Services{
+			"web": {
+				Name: "web",
+				Prebuild: []PrebuildJob{
+					{
+						Name:        "Publish",
+						RequiresEnv: []string{"NPM_TOKEN", "CI"},
+						Commands: []PrebuildCommand{
+							{Name: "Login", Environment: MappingWithEquals{"REGISTRY_PASSWORD": nil, "TOKEN": &token}},
+						},
+					},
+				},
+			},
+			"api": {
+				Name: "api",
+				Prebuild: []PrebuildJob{
+					{
+						Name:        "Test",
+						RequiresEnv: []string{"db_password"},
+						Container:   &PrebuildContainer{Environment: MappingWithEquals{"GOPROXY": nil}},
+					},
+				},
+			},
+		},
+		Secrets: Secrets{
+			"db_password":       {Environment: "DB_PASSWORD"},
+			"npm_token":         {Environment: "NPM_TOKEN"},
+			"registry_password": {File: "./registry.txt"},
+		},
+	}
+	assert.DeepEqual(t, p.PrebuildRequiredSecrets(), PrebuildRequirements{
+		Secrets:     []string{"db_password", "npm_token"},
+		Environment: []string{"CI", "GOPROXY", "REGISTRY_PASSWORD"},
+	})
+}
+
+func TestPrebuildRequiredSecretsSources(t *testing.T) {
+	apiKey := "${sensitive.api_key}"
+	tests := []struct {
+		name     string
+		job      PrebuildJob
+		expected PrebuildRequirements
+	}{
+		{
+			name:     "job environment",
+			job:      PrebuildJob{Name: "Build", Environment: MappingWithEquals{"GITHUB_TOKEN": nil, "DEPLOY_KEY": nil}},
+			expected: PrebuildRequirements{Secrets: []string{"deploy_key"}, Environment: []string{"GITHUB_TOKEN"}},
+		},
+		{
+			name: "command env_file",
+			job: PrebuildJob{Name: "Build", Commands: []PrebuildCommand{
+				{Name: "Publish", EnvFiles: []EnvFile{{Path: "/project/registry.env"}, {Path: "/project/build.env"}}},
+			}},
+			expected: PrebuildRequirements{Secrets: []string{"registry"}, Environment: []string{}},
+		},
+		{
+			name: "sensitive reference",
+			job: PrebuildJob{Name: "Build", Commands: []PrebuildCommand{
+				{Name: "Publish", Environment: MappingWithEquals{"API_KEY": &apiKey}},
+			}},
+			expected: PrebuildRequirements{Secrets: []string{"api_key"}, Environment: []string{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &Project{
+				Services: Services{"web": {Name: "web", Prebuild: []PrebuildJob{tt.job}}},
+				Secrets: Secrets{
+					"api_key":    {Environment: "API_KEY"},
+					"deploy_key": {Environment: "DEPLOY_KEY"},
+					"registry":   {File: "/project/registry.env"},
+				},
+			}
+			assert.DeepEqual(t, p.PrebuildRequiredSecrets(), tt.expected)
+		})
+	}
+}
+
+func TestPrebuildRetryBackoffDelay(t *testing.T) {
+	maxDelay := Duration(30 * time.Second)
+	b := PrebuildRetryBackoff{Initial: Duration(2 * time.Second), Factor: 2, Max: &maxDelay}
+	assert.Equal(t, b.Delay(1), 2*time.Second)
+	assert.Equal(t, b.Delay(2), 4*time.Second)
+	assert.Equal(t, b.Delay(4), 16*time.Second)
+	assert.Equal(t, b.Delay(5), 30*time.Second)
+
+	constant := PrebuildRetryBackoff{Initial: Duration(time.Second)}
+	assert.Equal(t, constant.Delay(3), time.Second)
+
+	unbounded := PrebuildRetryBackoff{Initial: Duration(time.Second), Factor: 2}
+	assert.Equal(t, unbounded.Delay(64), time.Duration(math.MaxInt64))
+	assert.Equal(t, unbounded.Delay(10000), time.Duration(math.MaxInt64))
+	assert.Equal(t, PrebuildRetryBackoff{Factor: 2}.Delay(10000), time.Duration(0))
+}
+
+func TestPrebuildJobNames(t *testing.T) {
+	p := &Project{
+		Services: Services{
+			"web": {
+				Name:     "web",
+				Prebuild: []PrebuildJob{{Name: "Test"}, {Name: "Lint"}},
+			},
+			"api": {
+				Name:     "api",
+				Prebuild: []PrebuildJob{{Name: "Test"}, {Name: "Generate"}},
+			},
+			"db": {Name: "db"},
+		},
+	}
+	names, ambiguous := p.PrebuildJobNames()
+	assert.DeepEqual(t, []string{"Generate", "Lint", "Test"}, names)
+	assert.DeepEqual(t, map[string][]string{"Test": {"api", "web"}}, ambiguous)
+}
+
+func TestPrebuildUsesNetwork(t *testing.T) {
+	p := &Project{
+		Networks: Networks{"backend": {}, "frontend": {}, "ci": {}},
+		Services: Services{
+			"web": {
+				Name:     "web",
+				Networks: map[string]*ServiceNetworkConfig{"frontend": nil},
+				Prebuild: []PrebuildJob{
+					{Name: "Integration", Container: &PrebuildContainer{Image: "node:18", Networks: []string{"ci", "backend"}}},
+					{Name: "Lint"},
+				},
+			},
+			"db": {
+				Name:     "db",
+				Networks: map[string]*ServiceNetworkConfig{"backend": nil},
+			},
+		},
+	}
+	networks, err := p.PrebuildUsesNetwork(false)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"backend", "ci"}, networks)
+
+	networks, err = p.PrebuildUsesNetwork(true)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"backend", "ci", "frontend"}, networks)
+
+	delete(p.Networks, "ci")
+	_, err = p.PrebuildUsesNetwork(false)
+	assert.ErrorContains(t, err, `services.web.prebuild.Integration.container.networks: undefined network "ci"`)
+}
+
+func TestPrebuildTopoSort(t *testing.T) {
+	s := ServiceConfig{
+		Name: "web",
+		Prebuild: []PrebuildJob{
+			{Name: "Test Suite", Needs: []PrebuildNeed{{Job: "Lint"}}},
+			{Name: "Package", Needs: []PrebuildNeed{{Job: "Test Suite"}}},
+			{Name: "Lint"},
+			{Name: "Docs"},
+		},
+	}
+	jobs, err := s.PrebuildTopoSort()
+	assert.NilError(t, err)
+	var names []string
+	for _, job := range jobs {
+		names = append(names, job.Name)
+	}
+	assert.DeepEqual(t, []string{"Lint", "Docs", "Test Suite", "Package"}, names)
+
+	s.Prebuild = []PrebuildJob{{Name: "Test Suite", Needs: []PrebuildNeed{{Job: "Lnt"}}}}
+	_, err = s.PrebuildTopoSort()
+	assert.Error(t, err, `services.web.prebuild.Test Suite: needs undefined job "Lnt": invalid compose project`)
+
+	s.Prebuild = []PrebuildJob{{Name: "A", Needs: []PrebuildNeed{{Job: "B"}}}, {Name: "B", Needs: []PrebuildNeed{{Job: "A"}}}}
+	_, err = s.PrebuildTopoSort()
+	assert.Error(t, err, "services.web.prebuild: dependency cycle detected between jobs A, B: invalid compose project")
+}
+
+func TestPrebuildGraph(t *testing.T) {
+	p := &Project{
+		Services: Services{
+			"web": {
+				Name: "web",
+				Prebuild: []PrebuildJob{
+					{Name: "Test Suite", Needs: []PrebuildNeed{{Job: "Lint"}, {Job: "Build"}}},
+					{Name: "Package", Needs: []PrebuildNeed{{Job: "Test Suite"}}},
+					{Name: "Build"},
+					{Name: "Lint"},
+					{Name: "Docs", Needs: []PrebuildNeed{{Job: "Build"}}},
+				},
+			},
+		},
+	}
+	batches, err := p.PrebuildGraph("web")
+	assert.NilError(t, err)
+	var names [][]string
+	for _, batch := range batches {
+		var batchNames []string
+		for _, job := range batch {
+			batchNames = append(batchNames, job.Name)
+		}
+		names = append(names, batchNames)
+	}
+	assert.DeepEqual(t, [][]string{{"Build", "Lint"}, {"Test Suite", "Docs"}, {"Package"}}, names)
+
+	_, err = p.PrebuildGraph("db")
+	assert.ErrorContains(t, err, "no such service: db")
+}
+
+func TestPrebuildJobExpandMatrix(t *testing.T) {
+	job := PrebuildJob{
+		Name: "Test",
+		Matrix: map[string][]string{
+			"runs-on":  {"node:18", "node:20"},
+			"NODE_ENV": {"test", "production"},
+		},
+		Commands: []PrebuildCommand{{Name: "Test", Command: "npm test"}},
+	}
+	jobs, err := job.ExpandMatrix()
+	assert.NilError(t, err)
+	var names []string
+	for _, j := range jobs {
+		names = append(names, j.Name)
+		assert.Check(t, j.Matrix == nil)
+	}
+	assert.DeepEqual(t, names, []string{
+		"Test (test, node:18)",
+		"Test (test, node:20)",
+		"Test (production, node:18)",
+		"Test (production, node:20)",
+	})
+	assert.Equal(t, jobs[3].RunsOn, "node:20")
+	assert.Equal(t, *jobs[3].Environment["NODE_ENV"], "production")
+
+	jobs[0].Commands[0].Command = "npm run test:ci"
+	jobs[1].Commands = append(jobs[1].Commands, PrebuildCommand{Name: "Lint", Command: "npm run lint"})
+	assert.Equal(t, jobs[2].Commands[0].Command, "npm test")
+	assert.Equal(t, len(jobs[2].Commands), 1)
+	assert.Equal(t, job.Commands[0].Command, "npm test")
+
+	jobs, err = PrebuildJob{Name: "Lint"}.ExpandMatrix()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, jobs, []PrebuildJob{{Name: "Lint"}})
+
+	_, err = PrebuildJob{Name: "Test", RunsOn: "node:18", Matrix: map[string][]string{"runs-on": {"node:20"}}}.ExpandMatrix()
+	assert.ErrorContains(t, err, "runs-on and matrix.runs-on are mutually exclusive")
+	_, err = PrebuildJob{Name: "Test", Matrix: map[string][]string{"NODE_ENV": {}}}.ExpandMatrix()
+	assert.ErrorContains(t, err, "matrix.NODE_ENV must declare at least one value")
+}
+
+func TestPrebuildCommandRunsOnPlatform(t *testing.T) {
+	all := PrebuildCommand{Name: "Test"}
+	assert.Check(t, all.RunsOnPlatform("linux/amd64"))
+	assert.Check(t, all.RunsOnPlatform("windows/arm64"))
+
+	cmd := PrebuildCommand{Name: "Test", Platforms: []string{"linux/amd64", "linux/arm/v7", "Linux/ARM64"}}
+	assert.Check(t, cmd.RunsOnPlatform("linux/amd64"))
+	assert.Check(t, cmd.RunsOnPlatform("linux/amd64/v3"))
+	assert.Check(t, cmd.RunsOnPlatform("linux/arm/v7"))
+	assert.Check(t, cmd.RunsOnPlatform("linux/arm64/v8"))
+	assert.Check(t, !cmd.RunsOnPlatform("linux/arm/v6"))
+	assert.Check(t, !cmd.RunsOnPlatform("linux/arm"))
+	assert.Check(t, !cmd.RunsOnPlatform("windows/amd64"))
+	assert.Check(t, !cmd.RunsOnPlatform("darwin/arm64"))
+}
+
+type stubImageChecker map[string]bool
+
+func (c stubImageChecker) Exists(_ context.Context, ref string) (bool, error) {
+	exists, ok := c[ref]
+	if !ok {
+		return false, errors.New("unexpected image check")
+	}
+	return exists, nil
+}
+
+func TestPrebuildValidateImages(t *testing.T) {
+	p := &Project{
+		Name: "demo",
+		Services: Services{
+			"web": {
+				Name: "web",
+				Prebuild: []PrebuildJob{
+					{Name: "Test", RunsOn: "node:18"},
+					{Name: "Lint", RunsOn: "golangci/golangci-lint:v0"},
+					{Name: "Host"},
+					{Name: "Migrate", RunsOn: "service:db"},
+					{Name: "Integration", RunsOn: "service:api"},
+				},
+			},
+			"db": {
+				Name:  "db",
+				Image: "postgres:15",
+			},
+			"api": {
+				Name:  "api",
+				Build: &BuildConfig{Context: "."},
+			},
+		},
+	}
+	missing, err := p.PrebuildValidateImages(context.TODO(), stubImageChecker{
+		"node:18":                   true,
+		"postgres:15":               true,
+		"golangci/golangci-lint:v0": false,
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, missing, []string{"golangci/golangci-lint:v0"})
+
+	_, err = p.PrebuildValidateImages(context.TODO(), stubImageChecker{"node:18": true})
+	assert.ErrorContains(t, err, "unexpected image check")
+}
+
+func TestPrebuildJobCommandsByGroup(t *testing.T) {
+	job := PrebuildJob{
+		Name: "Build",
+		Commands: []PrebuildCommand{
+			{Name: "Install", Group: "Setup"},
+			{Name: "Configure", Group: "Setup"},
+			{Name: "Compile"},
+			{Name: "Link"},
+			{Name: "Unit", Group: "Test"},
+			{Name: "Lint", Group: "Setup"},
+		},
+	}
+	type group struct {
+		name     string
+		commands []string
+	}
+	var actual []group
+	for _, g := range job.CommandsByGroup() {
+		var names []string
+		for _, cmd := range g.Commands {
+			names = append(names, cmd.Name)
+		}
+		actual = append(actual, group{name: g.Group, commands: names})
+	}
+	assert.DeepEqual(t, actual, []group{
+		{name: "Setup", commands: []string{"Install", "Configure"}},
+		{commands: []string{"Compile"}},
+		{commands: []string{"Link"}},
+		{name: "Test", commands: []string{"Unit"}},
+		{name: "Setup", commands: []string{"Lint"}},
+	}, cmp.AllowUnexported(group{}))
+	assert.Check(t, PrebuildJob{}.CommandsByGroup() == nil)
+}
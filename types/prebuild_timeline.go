@@ -0,0 +1,74 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"time"
+)
+
+// TimelineStep is a prebuild job, or one of its commands, with an estimated start offset
+type TimelineStep struct {
+	Service string `json:"service"`
+	Job     string `json:"job"`
+	// Command is the command name, empty for a job step
+	Command  string        `json:"command,omitempty"`
+	Start    time.Duration `json:"start"`
+	Duration time.Duration `json:"duration"`
+}
+
+// PrebuildStepTimeline estimates start offset of each prebuild job and command, based on commands
+// `estimated_duration`. Commands run sequentially within a job, while a job starts as soon as all jobs it
+// `needs` completed, jobs without needs starting together. Commands without estimated duration count as zero.
+// Steps are sorted by service, then in execution order.
+func (p *Project) PrebuildStepTimeline() ([]TimelineStep, error) {
+	var timeline []TimelineStep
+	for _, name := range p.ServiceNames() {
+		service := p.Services[name]
+		layers, err := prebuildJobLayers(service)
+		if err != nil {
+			return nil, err
+		}
+		index := map[string]int{}
+		for i, job := range service.Prebuild {
+			index[job.Name] = i
+		}
+		ends := make([]time.Duration, len(service.Prebuild))
+		for _, layer := range layers {
+			for _, i := range layer {
+				job := service.Prebuild[i]
+				var start time.Duration
+				for _, need := range job.NeededJobs() {
+					start = max(start, ends[index[need]])
+				}
+				steps := []TimelineStep{{Service: name, Job: job.Name, Start: start}}
+				offset := start
+				for _, cmd := range job.Commands {
+					var d time.Duration
+					if cmd.EstimatedDuration != nil {
+						d = time.Duration(*cmd.EstimatedDuration)
+					}
+					steps = append(steps, TimelineStep{Service: name, Job: job.Name, Command: cmd.Name, Start: offset, Duration: d})
+					offset += d
+				}
+				steps[0].Duration = offset - start
+				ends[i] = offset
+				timeline = append(timeline, steps...)
+			}
+		}
+	}
+	return timeline, nil
+}
@@ -0,0 +1,89 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestPrebuildStepTimeline(t *testing.T) {
+	duration := func(d time.Duration) *Duration {
+		v := Duration(d)
+		return &v
+	}
+	p := &Project{
+		Services: Services{
+			"web": {
+				Name: "web",
+				Prebuild: []PrebuildJob{
+					{
+						Name:  "Test",
+						Needs: []PrebuildNeed{{Job: "Build"}, {Job: "Lint"}},
+						Commands: []PrebuildCommand{
+							{Name: "Unit", EstimatedDuration: duration(time.Minute)},
+						},
+					},
+					{
+						Name: "Build",
+						Commands: []PrebuildCommand{
+							{Name: "Install", EstimatedDuration: duration(30 * time.Second)},
+							{Name: "Compile", EstimatedDuration: duration(2 * time.Minute)},
+						},
+					},
+					{
+						Name: "Lint",
+						Commands: []PrebuildCommand{
+							{Name: "Vet", EstimatedDuration: duration(10 * time.Second)},
+							{Name: "Format"},
+						},
+					},
+				},
+			},
+		},
+	}
+	timeline, err := p.PrebuildStepTimeline()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []TimelineStep{
+		{Service: "web", Job: "Build", Start: 0, Duration: 150 * time.Second},
+		{Service: "web", Job: "Build", Command: "Install", Start: 0, Duration: 30 * time.Second},
+		{Service: "web", Job: "Build", Command: "Compile", Start: 30 * time.Second, Duration: 2 * time.Minute},
+		{Service: "web", Job: "Lint", Start: 0, Duration: 10 * time.Second},
+		{Service: "web", Job: "Lint", Command: "Vet", Start: 0, Duration: 10 * time.Second},
+		{Service: "web", Job: "Lint", Command: "Format", Start: 10 * time.Second, Duration: 0},
+		{Service: "web", Job: "Test", Start: 150 * time.Second, Duration: time.Minute},
+		{Service: "web", Job: "Test", Command: "Unit", Start: 150 * time.Second, Duration: time.Minute},
+	}, timeline)
+}
+
+func TestPrebuildStepTimelineCycle(t *testing.T) {
+	p := &Project{
+		Services: Services{
+			"web": {
+				Name: "web",
+				Prebuild: []PrebuildJob{
+					{Name: "A", Needs: []PrebuildNeed{{Job: "B"}}},
+					{Name: "B", Needs: []PrebuildNeed{{Job: "A"}}},
+				},
+			},
+		},
+	}
+	_, err := p.PrebuildStepTimeline()
+	assert.ErrorContains(t, err, "services.web.prebuild: dependency cycle detected between jobs A, B")
+}
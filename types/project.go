@@ -40,22 +40,33 @@ import (
 // Since v2, Project are managed as immutable objects.
 // Each public functions which mutate Project state now return a copy of the original Project with the expected changes.
 type Project struct {
-	Name       string     `yaml:"name,omitempty" json:"name,omitempty"`
-	WorkingDir string     `yaml:"-" json:"-"`
-	Services   Services   `yaml:"services" json:"services"`
-	Networks   Networks   `yaml:"networks,omitempty" json:"networks,omitempty"`
-	Volumes    Volumes    `yaml:"volumes,omitempty" json:"volumes,omitempty"`
-	Secrets    Secrets    `yaml:"secrets,omitempty" json:"secrets,omitempty"`
-	Configs    Configs    `yaml:"configs,omitempty" json:"configs,omitempty"`
-	Models     Models     `yaml:"models,omitempty" json:"models,omitempty"`
-	Extensions Extensions `yaml:"#extensions,inline,omitempty" json:"-"` // https://github.com/golang/go/issues/6213
+	Name       string   `yaml:"name,omitempty" json:"name,omitempty"`
+	WorkingDir string   `yaml:"-" json:"-"`
+	Services   Services `yaml:"services" json:"services"`
+	Networks   Networks `yaml:"networks,omitempty" json:"networks,omitempty"`
+	Volumes    Volumes  `yaml:"volumes,omitempty" json:"volumes,omitempty"`
+	Secrets    Secrets  `yaml:"secrets,omitempty" json:"secrets,omitempty"`
+	Configs    Configs  `yaml:"configs,omitempty" json:"configs,omitempty"`
+	Models     Models   `yaml:"models,omitempty" json:"models,omitempty"`
+	// Stages declares the order of prebuild stages. When not set, stages are ordered by first appearance
+	Stages []string `yaml:"stages,omitempty" json:"stages,omitempty"`
+	// FileDefaults applies to local_configs and sensitive entries of all services, see ServiceConfig.FileDefaults
+	FileDefaults *FileDefaults `yaml:"file_defaults,omitempty" json:"file_defaults,omitempty"`
+	Extensions   Extensions    `yaml:"#extensions,inline,omitempty" json:"-"` // https://github.com/golang/go/issues/6213
 
 	ComposeFiles []string `yaml:"-" json:"-"`
 	Environment  Mapping  `yaml:"-" json:"-"`
+	// EnvironmentSources are the variables interpolation resolved with their source, see loader
+	// Options.TrackEnvironmentSources
+	EnvironmentSources map[string]EnvironmentSource `yaml:"-" json:"-"`
 
 	// DisabledServices track services which have been disable as profile is not active
 	DisabledServices Services `yaml:"-" json:"-"`
 	Profiles         []string `yaml:"-" json:"-"`
+	// PrebuildProfiles are the profiles prebuild jobs are matched with, instead of Profiles, see WithPrebuildProfiles
+	PrebuildProfiles []string `yaml:"-" json:"-"`
+	// DisabledPrebuildJobs track, by service, prebuild jobs which have been disabled by profiles or skip
+	DisabledPrebuildJobs map[string][]PrebuildJob `yaml:"-" json:"-"`
 }
 
 // ServiceNames return names for all services in this Compose config
@@ -380,13 +391,32 @@ func (s ServiceConfig) HasProfile(profiles []string) bool {
 	return false
 }
 
-// WithProfiles disables services which don't match selected profiles
+// WithProfiles disables services which don't match selected profiles, and prebuild jobs which don't match those, or
+// PrebuildProfiles when set, or are skipped, prebuild jobs enabled again being appended to service jobs
 // It returns a new Project instance with the changes and keep the original Project unchanged
 func (p *Project) WithProfiles(profiles []string) (*Project, error) {
 	newProject := p.deepCopy()
 	enabled := Services{}
 	disabled := Services{}
+	var disabledJobs map[string][]PrebuildJob
+	jobProfiles := profiles
+	if p.PrebuildProfiles != nil {
+		jobProfiles = p.PrebuildProfiles
+	}
 	for name, service := range newProject.AllServices() {
+		if jobs := slices.Concat(service.Prebuild, p.DisabledPrebuildJobs[name]); len(jobs) > 0 {
+			service.Prebuild = nil
+			for _, job := range jobs {
+				if job.Skip || job.SkipReason != "" || !job.HasProfile(jobProfiles) {
+					if disabledJobs == nil {
+						disabledJobs = map[string][]PrebuildJob{}
+					}
+					disabledJobs[name] = append(disabledJobs[name], job)
+				} else {
+					service.Prebuild = append(service.Prebuild, job)
+				}
+			}
+		}
 		if service.HasProfile(profiles) {
 			enabled[name] = service
 		} else {
@@ -395,10 +425,20 @@ func (p *Project) WithProfiles(profiles []string) (*Project, error) {
 	}
 	newProject.Services = enabled
 	newProject.DisabledServices = disabled
+	newProject.DisabledPrebuildJobs = disabledJobs
 	newProject.Profiles = profiles
 	return newProject, nil
 }
 
+// WithPrebuildProfiles selects prebuild jobs by profiles rather than by the profiles services are selected by, so jobs
+// can be enabled independently. A nil profiles matches jobs with Profiles again
+// It returns a new Project instance with the changes and keep the original Project unchanged
+func (p *Project) WithPrebuildProfiles(profiles []string) (*Project, error) {
+	newProject := p.deepCopy()
+	newProject.PrebuildProfiles = profiles
+	return newProject.WithProfiles(newProject.Profiles)
+}
+
 // WithServicesEnabled ensures services are enabled and activate profiles accordingly
 // It returns a new Project instance with the changes and keep the original Project unchanged
 func (p *Project) WithServicesEnabled(names ...string) (*Project, error) {
@@ -419,7 +459,8 @@ func (p *Project) WithServicesEnabled(names ...string) (*Project, error) {
 	return newProject.WithProfiles(profiles)
 }
 
-// WithoutUnnecessaryResources drops networks/volumes/secrets/configs that are not referenced by active services
+// WithoutUnnecessaryResources drops networks/volumes/secrets/configs that are not referenced by active services,
+// secrets rendered by sensitive entries being referenced
 // It returns a new Project instance with the changes and keep the original Project unchanged
 func (p *Project) WithoutUnnecessaryResources() *Project {
 	newProject := p.deepCopy()
@@ -446,6 +487,9 @@ func (p *Project) WithoutUnnecessaryResources() *Project {
 				requiredSecrets[v.Source] = struct{}{}
 			}
 		}
+		for _, source := range s.RequiredSensitiveSecrets() {
+			requiredSecrets[source] = struct{}{}
+		}
 		for _, v := range s.Configs {
 			requiredConfigs[v.Source] = struct{}{}
 		}
@@ -603,14 +647,57 @@ func (p *Project) WithImagesResolved(resolver func(named reference.Named) (godig
 	})
 }
 
+const (
+	// CICDExtensionPrebuild is the service extension holding prebuild jobs, see WithCICDAsExtensions
+	CICDExtensionPrebuild = "x-prebuild"
+	// CICDExtensionLocalConfigs is the service extension holding local configs, see WithCICDAsExtensions
+	CICDExtensionLocalConfigs = "x-local-configs"
+	// CICDExtensionSensitive is the service extension holding sensitive entries, see WithCICDAsExtensions
+	CICDExtensionSensitive = "x-sensitive"
+	// CICDExtensionStages is the project extension holding prebuild stages, see WithCICDAsExtensions
+	CICDExtensionStages = "x-stages"
+	// CICDExtensionFileDefaults is the project and service extension holding file defaults, see WithCICDAsExtensions
+	CICDExtensionFileDefaults = "x-file-defaults"
+)
+
+// CICDIsEmpty tells if project declares no cicdez attribute: no service has prebuild jobs, local configs or
+// sensitive entries, and no prebuild stages are declared, so consumers can skip cicdez processing altogether
+func (p *Project) CICDIsEmpty() bool {
+	if len(p.Stages) > 0 {
+		return false
+	}
+	for _, s := range p.Services {
+		if len(s.Prebuild) > 0 || len(s.LocalConfigs) > 0 || len(s.Sensitive) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
 type marshallOptions struct {
-	secretsContent bool
+	secretsContent   bool
+	cicdAsExtensions bool
+	compactPrebuild  bool
+	durationFormat   DurationFormat
 }
 
 func WithSecretContent(o *marshallOptions) {
 	o.secretsContent = true
 }
 
+// WithCompactPrebuild makes MarshalYAML use the short string form for the command of prebuild jobs with a single
+// trivial command, which only sets a command, or a name equal to the command, for concurrency only setting a group, and for needs only setting a job
+func WithCompactPrebuild(o *marshallOptions) {
+	o.compactPrebuild = true
+}
+
+// WithCICDAsExtensions marshals cicdez attributes as `x-prebuild`, `x-local-configs`, `x-sensitive` and
+// `x-file-defaults` service extensions, and prebuild stages and file defaults as `x-stages` and `x-file-defaults`
+// project extensions, so the resulting document is accepted by compose implementations not supporting those
+func WithCICDAsExtensions(o *marshallOptions) {
+	o.cicdAsExtensions = true
+}
+
 func (opt *marshallOptions) apply(p *Project) *Project {
 	if opt.secretsContent {
 		p = p.deepCopy()
@@ -619,6 +706,40 @@ func (opt *marshallOptions) apply(p *Project) *Project {
 			p.Secrets[name] = config
 		}
 	}
+	if opt.cicdAsExtensions {
+		p = p.deepCopy()
+		for name, s := range p.Services {
+			extensions := Extensions{}
+			s.Extensions.DeepCopy(extensions)
+			if len(s.Prebuild) > 0 {
+				extensions[CICDExtensionPrebuild] = s.Prebuild
+			}
+			if len(s.LocalConfigs) > 0 {
+				extensions[CICDExtensionLocalConfigs] = s.LocalConfigs
+			}
+			if len(s.Sensitive) > 0 {
+				extensions[CICDExtensionSensitive] = s.Sensitive
+			}
+			if s.FileDefaults != nil {
+				extensions[CICDExtensionFileDefaults] = s.FileDefaults
+			}
+			s.Extensions = extensions
+			s.Prebuild, s.LocalConfigs, s.Sensitive, s.FileDefaults = nil, nil, nil, nil
+			p.Services[name] = s
+		}
+		if len(p.Stages) > 0 || p.FileDefaults != nil {
+			extensions := Extensions{}
+			p.Extensions.DeepCopy(extensions)
+			if len(p.Stages) > 0 {
+				extensions[CICDExtensionStages] = p.Stages
+			}
+			if p.FileDefaults != nil {
+				extensions[CICDExtensionFileDefaults] = p.FileDefaults
+			}
+			p.Extensions = extensions
+			p.Stages, p.FileDefaults = nil, nil
+		}
+	}
 	return p
 }
 
@@ -637,8 +758,27 @@ func (p *Project) MarshalYAML(options ...func(*marshallOptions)) ([]byte, error)
 	encoder := yaml.NewEncoder(buf)
 	encoder.SetIndent(2)
 	// encoder.CompactSeqIndent() FIXME https://github.com/go-yaml/yaml/pull/753
-	src := applyMarshallOptions(p, options...)
-	err := encoder.Encode(src)
+	opts := &marshallOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+	src := opts.apply(p)
+	var err error
+	if opts.compactPrebuild || opts.durationFormat != "" {
+		var node yaml.Node
+		if err = node.Encode(src); err != nil {
+			return nil, err
+		}
+		if opts.compactPrebuild {
+			compactPrebuildCommands(&node)
+		}
+		if opts.durationFormat != "" {
+			canonicalCICDDurations(&node, opts.durationFormat)
+		}
+		err = encoder.Encode(&node)
+	} else {
+		err = encoder.Encode(src)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -665,6 +805,12 @@ func (p *Project) MarshalJSON(options ...func(*marshallOptions)) ([]byte, error)
 	if len(src.Configs) > 0 {
 		m["configs"] = src.Configs
 	}
+	if len(src.Stages) > 0 {
+		m["stages"] = src.Stages
+	}
+	if src.FileDefaults != nil {
+		m["file_defaults"] = src.FileDefaults
+	}
 	for k, v := range src.Extensions {
 		m[k] = v
 	}
@@ -701,6 +847,9 @@ func (p Project) WithServicesEnvironmentResolved(discardEnvFiles bool) (*Project
 		if discardEnvFiles {
 			service.EnvFiles = nil
 		}
+		if err := service.resolvePrebuildEnvFiles(p.Environment, discardEnvFiles); err != nil {
+			return nil, err
+		}
 		newProject.Services[i] = service
 	}
 	return newProject, nil
@@ -776,6 +925,12 @@ func loadMappingFile(path string, format string, vars Mapping, resolve dotenv.Lo
 	return dotenv.ParseWithFormat(file, path, vars, resolve, format)
 }
 
+// Clone returns a deep copy of project, cicdez attributes and extensions included, so the copy can be modified while
+// p is concurrently used
+func (p *Project) Clone() *Project {
+	return p.deepCopy()
+}
+
 func (p *Project) deepCopy() *Project {
 	if p == nil {
 		return nil
@@ -29,6 +29,7 @@ import (
 	"github.com/distribution/reference"
 	"github.com/opencontainers/go-digest"
 	"gotest.tools/v3/assert"
+	is "gotest.tools/v3/assert/cmp"
 )
 
 func Test_ApplyProfiles(t *testing.T) {
@@ -45,6 +46,47 @@ func Test_ApplyProfiles(t *testing.T) {
 	assert.DeepEqual(t, p.DisabledServiceNames(), []string{"service_3"})
 }
 
+func Test_ApplyProfilesPrebuildJobs(t *testing.T) {
+	p := &Project{
+		Services: Services{
+			"web": {
+				Name: "web",
+				Prebuild: []PrebuildJob{
+					{Name: "Build"},
+					{Name: "Integration Tests", Profiles: []string{"integration"}},
+					{Name: "Flaky", Skip: true},
+				},
+			},
+		},
+	}
+	jobNames := func(jobs []PrebuildJob) []string {
+		var names []string
+		for _, job := range jobs {
+			names = append(names, job.Name)
+		}
+		return names
+	}
+
+	p, err := p.WithProfiles(nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, jobNames(p.Services["web"].Prebuild), []string{"Build"})
+	assert.DeepEqual(t, jobNames(p.DisabledPrebuildJobs["web"]), []string{"Integration Tests", "Flaky"})
+
+	p, err = p.WithProfiles([]string{"integration"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, jobNames(p.Services["web"].Prebuild), []string{"Build", "Integration Tests"})
+	assert.DeepEqual(t, jobNames(p.DisabledPrebuildJobs["web"]), []string{"Flaky"})
+
+	p, err = p.WithPrebuildProfiles([]string{})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, jobNames(p.Services["web"].Prebuild), []string{"Build"})
+	assert.DeepEqual(t, p.Profiles, []string{"integration"})
+
+	p, err = p.WithPrebuildProfiles(nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, jobNames(p.Services["web"].Prebuild), []string{"Build", "Integration Tests"})
+}
+
 func Test_WithoutUnnecessaryResources(t *testing.T) {
 	p := makeProject()
 	p.Networks["unused"] = NetworkConfig{}
@@ -66,6 +108,33 @@ func Test_WithoutUnnecessaryResources(t *testing.T) {
 	}
 }
 
+func Test_WithoutUnnecessaryResourcesSensitive(t *testing.T) {
+	p := &Project{
+		Services: Services{
+			"web": {
+				Name: "web",
+				Sensitive: map[string]SensitiveConfig{
+					"env": {Target: "/run/secrets/app.env", Secrets: []SensitiveSecret{{Source: "api_key"}}},
+				},
+			},
+		},
+		DisabledServices: Services{
+			"db": {
+				Name: "db",
+				Sensitive: map[string]SensitiveConfig{
+					"db": {Target: "/run/secrets/db", Secrets: []SensitiveSecret{{Source: "db_password"}}},
+				},
+			},
+		},
+		Secrets: Secrets{
+			"api_key":     {Environment: "API_KEY"},
+			"db_password": {Environment: "DB_PASSWORD"},
+		},
+	}
+	p = p.WithoutUnnecessaryResources()
+	assert.DeepEqual(t, p.SecretNames(), []string{"api_key"})
+}
+
 func Test_NoProfiles(t *testing.T) {
 	p := makeProject()
 	p, err := p.WithProfiles(nil)
@@ -509,3 +578,62 @@ func TestProject_WithServicesEnvironmentResolved(t *testing.T) {
 func ptr[T any](s T) *T {
 	return &s
 }
+
+func TestProjectCICDIsEmpty(t *testing.T) {
+	p := &Project{
+		Services: Services{
+			"web": {Name: "web", Image: "nginx"},
+			"db":  {Name: "db", Image: "postgres"},
+		},
+	}
+	assert.Check(t, p.CICDIsEmpty())
+	assert.Check(t, (&Project{}).CICDIsEmpty())
+
+	for name, s := range map[string]ServiceConfig{
+		"prebuild":      {Name: "db", Prebuild: []PrebuildJob{{Name: "Migrate"}}},
+		"local_configs": {Name: "db", LocalConfigs: map[string]LocalConfigConfig{"conf": {Content: "max_connections=10"}}},
+		"sensitive":     {Name: "db", Sensitive: map[string]SensitiveConfig{"env": {}}},
+	} {
+		other := *p
+		other.Services = Services{"web": p.Services["web"], "db": s}
+		assert.Check(t, !other.CICDIsEmpty(), name)
+	}
+
+	stages := *p
+	stages.Stages = []string{"test"}
+	assert.Check(t, !stages.CICDIsEmpty())
+}
+
+func TestProjectClone(t *testing.T) {
+	mode := FileMode(0o440)
+	p := &Project{
+		Services: Services{
+			"web": {
+				Name:         "web",
+				Prebuild:     []PrebuildJob{{Name: "Build", Commands: []PrebuildCommand{{Name: "Make", Command: "make"}}}},
+				LocalConfigs: map[string]LocalConfigConfig{"app": {Content: "hello", Mode: &mode}},
+				Sensitive:    map[string]SensitiveConfig{"env": {Secrets: []SensitiveSecret{{Source: "api_key"}}}},
+				Extensions:   Extensions{"x-labels": map[string]any{"team": "web"}},
+			},
+		},
+		Extensions: Extensions{"x-targets": []any{"prod"}},
+	}
+	clone := p.Clone()
+	web := clone.Services["web"]
+	web.Prebuild[0].Commands[0].Command = "make all"
+	*web.LocalConfigs["app"].Mode = 0o400
+	web.Sensitive["env"].Secrets[0].Source = "token"
+	web.Extensions["x-labels"].(map[string]any)["team"] = "ops"
+	clone.Extensions["x-targets"].([]any)[0] = "dev"
+
+	original := p.Services["web"]
+	assert.Check(t, is.Equal("make", original.Prebuild[0].Commands[0].Command))
+	assert.Check(t, is.Equal(FileMode(0o440), *original.LocalConfigs["app"].Mode))
+	assert.Check(t, is.Equal("api_key", original.Sensitive["env"].Secrets[0].Source))
+	assert.Check(t, is.Equal("web", original.Extensions["x-labels"].(map[string]any)["team"]))
+	assert.Check(t, is.Equal("prod", p.Extensions["x-targets"].([]any)[0]))
+
+	service := original.Clone()
+	service.LocalConfigs["motd"] = LocalConfigConfig{Content: "welcome"}
+	assert.Check(t, is.Len(original.LocalConfigs, 1))
+}
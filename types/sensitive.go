@@ -0,0 +1,349 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/compose-spec/compose-go/v2/errdefs"
+	"github.com/compose-spec/compose-go/v2/utils"
+)
+
+// SensitiveFormat is the format a sensitive entry renders secrets with
+type SensitiveFormat string
+
+const (
+	// SensitiveFormatEnv renders secrets as `NAME=value` lines
+	SensitiveFormatEnv SensitiveFormat = "env"
+	// SensitiveFormatJSON renders secrets as a JSON object
+	SensitiveFormatJSON SensitiveFormat = "json"
+	// SensitiveFormatRaw renders the value of a single secret, which is the default
+	SensitiveFormatRaw SensitiveFormat = "raw"
+	// SensitiveFormatTemplate renders secrets with a template file
+	SensitiveFormatTemplate SensitiveFormat = "template"
+)
+
+// SensitiveFormats are the supported sensitive formats
+var SensitiveFormats = []SensitiveFormat{SensitiveFormatEnv, SensitiveFormatJSON, SensitiveFormatRaw, SensitiveFormatTemplate}
+
+// IsSupported tells if format is one of SensitiveFormats
+func (f SensitiveFormat) IsSupported() bool {
+	return slices.Contains(SensitiveFormats, f)
+}
+
+// SensitiveChangeAction is how a service reacts to a sensitive rendered file changing
+type SensitiveChangeAction string
+
+const (
+	// SensitiveChangeRewrite only rewrites the file, service reading it again on its own. This is the default
+	SensitiveChangeRewrite SensitiveChangeAction = "rewrite"
+	// SensitiveChangeRestart rewrites the file and restarts the service
+	SensitiveChangeRestart SensitiveChangeAction = "restart"
+	// SensitiveChangeSignal rewrites the file and sends a signal to the service containers, see SensitiveOnChange.Signal
+	SensitiveChangeSignal SensitiveChangeAction = "signal"
+)
+
+// SensitiveChangeActions are the supported sensitive change actions
+var SensitiveChangeActions = []SensitiveChangeAction{SensitiveChangeRewrite, SensitiveChangeRestart, SensitiveChangeSignal}
+
+// DefaultSensitiveChangeSignal is the signal SensitiveChangeSignal action sends when on_change doesn't set one
+const DefaultSensitiveChangeSignal = "SIGHUP"
+
+// ChangeAction returns the action taken when the rendered file changes, SensitiveChangeRewrite when not set
+func (s SensitiveConfig) ChangeAction() SensitiveChangeAction {
+	if s.OnChange == nil || s.OnChange.Action == "" {
+		return SensitiveChangeRewrite
+	}
+	return s.OnChange.Action
+}
+
+// ChangeSignal returns the signal sent to service containers when the rendered file changes, empty unless
+// ChangeAction is SensitiveChangeSignal
+func (s SensitiveConfig) ChangeSignal() string {
+	if s.ChangeAction() != SensitiveChangeSignal {
+		return ""
+	}
+	if s.OnChange.Signal == "" {
+		return DefaultSensitiveChangeSignal
+	}
+	return s.OnChange.Signal
+}
+
+// SELinuxOption returns the `z` or `Z` bind mount option runners relabel the file with, empty when not relabeled
+func (s SensitiveConfig) SELinuxOption() string {
+	return selinuxOptions[s.SELinux]
+}
+
+// FileMode returns the mode of the rendered file, DefaultSensitiveFileMode when not set
+func (s SensitiveConfig) FileMode() os.FileMode {
+	if s.Mode == nil {
+		return DefaultSensitiveFileMode
+	}
+	return s.Mode.OSFileMode()
+}
+
+// OutputFormat returns the format secrets are rendered with, SensitiveFormatRaw when not set
+func (s SensitiveConfig) OutputFormat() SensitiveFormat {
+	if s.Format == "" {
+		return SensitiveFormatRaw
+	}
+	return s.Format
+}
+
+func sensitiveFormatList() string {
+	formats := make([]string, len(SensitiveFormats))
+	for i, f := range SensitiveFormats {
+		formats[i] = string(f)
+	}
+	return strings.Join(formats, ", ")
+}
+
+const (
+	// SensitiveSortDeclaration renders secrets in declaration order, which is the default
+	SensitiveSortDeclaration = "declaration"
+	// SensitiveSortAlpha renders secrets sorted by exposed name
+	SensitiveSortAlpha = "alpha"
+)
+
+// SortedSecrets returns secrets in the order they are rendered, according to Sort
+func (s SensitiveConfig) SortedSecrets() []SensitiveSecret {
+	secrets := slices.Clone(s.Secrets)
+	if s.Sort == SensitiveSortAlpha {
+		slices.SortStableFunc(secrets, func(a, b SensitiveSecret) int {
+			return strings.Compare(s.SecretName(a), s.SecretName(b))
+		})
+	}
+	return secrets
+}
+
+// SecretName returns the name a secret is exposed with in the sensitive output: explicit secret name if set,
+// then entry alias for the source, then the source name
+func (s SensitiveConfig) SecretName(secret SensitiveSecret) string {
+	if secret.Name != "" {
+		return secret.Name
+	}
+	if alias, ok := s.Alias[secret.Source]; ok && alias != "" {
+		return alias
+	}
+	return secret.Source
+}
+
+// HasTrailingNewline tells if rendered output ends with a newline, as set by TrailingNewline. Defaults to true
+// for env and json formats, false for raw format, which is the default
+func (s SensitiveConfig) HasTrailingNewline() bool {
+	if s.TrailingNewline != nil {
+		return *s.TrailingNewline
+	}
+	return s.OutputFormat() != SensitiveFormatRaw
+}
+
+// ParseTemplate parses the template secrets are rendered with by template format, from TemplateContent when set,
+// otherwise from Template file
+func (s SensitiveConfig) ParseTemplate() (*template.Template, error) {
+	if s.TemplateContent != "" {
+		return template.New("template_content").Parse(s.TemplateContent)
+	}
+	return template.ParseFiles(s.Template)
+}
+
+// CheckTemplatePlaceholders validates tmpl only refers to secrets of the entry, as `{{ .NAME }}` by exposed name.
+// Fields referred to within range and with blocks are not checked, as those apply to another value
+func (s SensitiveConfig) CheckTemplatePlaceholders(tmpl *template.Template) error {
+	fields := utils.Set[string]{}
+	if tmpl.Tree != nil {
+		templateFields(tmpl.Root, fields)
+	}
+	for _, secret := range s.Secrets {
+		fields.Remove(s.SecretName(secret))
+	}
+	if len(fields) > 0 {
+		undeclared := fields.Elements()
+		slices.Sort(undeclared)
+		return fmt.Errorf("template refers to undeclared secrets %s: %w", strings.Join(undeclared, ", "), errdefs.ErrInvalid)
+	}
+	return nil
+}
+
+// templateFields collects names of top-level data fields a template node refers to
+func templateFields(node parse.Node, fields utils.Set[string]) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			templateFields(child, fields)
+		}
+	case *parse.ActionNode:
+		templateFields(n.Pipe, fields)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			templateFields(cmd, fields)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			templateFields(arg, fields)
+		}
+	case *parse.FieldNode:
+		fields.Add(n.Ident[0])
+	case *parse.VariableNode:
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			fields.Add(n.Ident[1])
+		}
+	case *parse.IfNode:
+		templateFields(n.Pipe, fields)
+		templateFields(n.List, fields)
+		templateFields(n.ElseList, fields)
+	case *parse.RangeNode:
+		templateFields(n.Pipe, fields)
+	case *parse.WithNode:
+		templateFields(n.Pipe, fields)
+	case *parse.TemplateNode:
+		templateFields(n.Pipe, fields)
+	}
+}
+
+// Validate checks a sensitive entry is valid on its own: supported format and sort, a single secret for raw format,
+// a single template and no trailing_newline for template format, an absolute target, a valid file mode, a
+// non-negative ttl, a supported selinux relabeling, a supported on_change action, and non-empty secret sources and
+// provider paths
+func (s SensitiveConfig) Validate() error {
+	if s.Format != "" && !s.Format.IsSupported() {
+		return fmt.Errorf("unsupported format %q, must be one of %s: %w", s.Format, sensitiveFormatList(), errdefs.ErrInvalid)
+	}
+	if s.Sort != "" && s.Sort != SensitiveSortDeclaration && s.Sort != SensitiveSortAlpha {
+		return fmt.Errorf("unsupported sort %q, must be one of %s, %s: %w", s.Sort, SensitiveSortDeclaration, SensitiveSortAlpha, errdefs.ErrInvalid)
+	}
+	if s.OutputFormat() == SensitiveFormatRaw && len(s.Secrets) != 1 {
+		return fmt.Errorf("raw format requires exactly one secret, got %d: %w", len(s.Secrets), errdefs.ErrInvalid)
+	}
+	if s.Format == SensitiveFormatTemplate && s.Template == "" && s.TemplateContent == "" {
+		return fmt.Errorf("template format requires a template: %w", errdefs.ErrInvalid)
+	}
+	if s.Template != "" && s.TemplateContent != "" {
+		return fmt.Errorf("template and template_content are mutually exclusive: %w", errdefs.ErrInvalid)
+	}
+	if s.Format == SensitiveFormatTemplate && s.TrailingNewline != nil {
+		return fmt.Errorf("trailing_newline is not supported by template format: %w", errdefs.ErrInvalid)
+	}
+	if !path.IsAbs(s.Target) {
+		return fmt.Errorf("target %q must be an absolute path: %w", s.Target, errdefs.ErrInvalid)
+	}
+	if s.Mode != nil && !s.Mode.IsPermission() {
+		return fmt.Errorf("mode %s is not a valid file permission: %w", s.Mode, errdefs.ErrInvalid)
+	}
+	if s.TTL != nil && *s.TTL < 0 {
+		return fmt.Errorf("ttl %s must not be negative: %w", s.TTL, errdefs.ErrInvalid)
+	}
+	if err := checkSELinux(s.SELinux); err != nil {
+		return err
+	}
+	if c := s.OnChange; c != nil {
+		if c.Action != "" && !slices.Contains(SensitiveChangeActions, c.Action) {
+			return fmt.Errorf("unsupported on_change action %q, must be one of %s, %s, %s: %w",
+				c.Action, SensitiveChangeRewrite, SensitiveChangeRestart, SensitiveChangeSignal, errdefs.ErrInvalid)
+		}
+		if c.Signal != "" && c.Action != SensitiveChangeSignal {
+			return fmt.Errorf("on_change signal requires %s action: %w", SensitiveChangeSignal, errdefs.ErrInvalid)
+		}
+	}
+	for i, secret := range s.Secrets {
+		if secret.Source == "" {
+			return fmt.Errorf("secrets[%d]: source must be set: %w", i, errdefs.ErrInvalid)
+		}
+		if secret.Provider != "" && secret.ProviderPath == "" {
+			return fmt.Errorf("secrets[%d]: provider %s requires a provider_path: %w", i, secret.Provider, errdefs.ErrInvalid)
+		}
+	}
+	return nil
+}
+
+// ValidateWithSecrets checks a sensitive entry is valid and only refers to the given top-level secrets
+func (s SensitiveConfig) ValidateWithSecrets(secrets Secrets) error {
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	for i, secret := range s.Secrets {
+		if _, ok := secrets[secret.Source]; !ok {
+			return fmt.Errorf("secrets[%d]: refers to undefined secret %s: %w", i, secret.Source, errdefs.ErrInvalid)
+		}
+	}
+	return nil
+}
+
+// RequiredSensitiveSecrets returns the sorted sources of top-level secrets the service sensitive entries render, for
+// deployment tools to check they exist before starting the service
+func (s ServiceConfig) RequiredSensitiveSecrets() []string {
+	sources := utils.Set[string]{}
+	for _, entry := range s.Sensitive {
+		for _, secret := range entry.Secrets {
+			sources.Add(secret.Source)
+		}
+	}
+	names := sources.Elements()
+	slices.Sort(names)
+	return names
+}
+
+// SensitiveSecretNames returns the sorted names of top-level secrets referenced by sensitive entries of enabled
+// services, so that a minimal secret bundle can be built. Sources which don't resolve to a declared secret are ignored
+func (p *Project) SensitiveSecretNames() []string {
+	sources := utils.Set[string]{}
+	for _, service := range p.Services {
+		for _, entry := range service.Sensitive {
+			for _, secret := range entry.Secrets {
+				if _, ok := p.Secrets[secret.Source]; ok {
+					sources.Add(secret.Source)
+				}
+			}
+		}
+	}
+	names := sources.Elements()
+	slices.Sort(names)
+	return names
+}
+
+// Check verifies value satisfies constraints. Returned errors never include value
+func (v SensitiveSecretValidation) Check(value string) error {
+	if v.MinLength < 0 {
+		return fmt.Errorf("min_length must not be negative: %w", errdefs.ErrInvalid)
+	}
+	if len(value) < v.MinLength {
+		return fmt.Errorf("value must be at least %d characters long: %w", v.MinLength, errdefs.ErrInvalid)
+	}
+	if v.Pattern == "" {
+		return nil
+	}
+	pattern, err := regexp.Compile(v.Pattern)
+	if err != nil {
+		return fmt.Errorf("invalid pattern %q: %v: %w", v.Pattern, err, errdefs.ErrInvalid)
+	}
+	if !pattern.MatchString(value) {
+		return fmt.Errorf("value doesn't match pattern %q: %w", v.Pattern, errdefs.ErrInvalid)
+	}
+	return nil
+}
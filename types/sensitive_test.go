@@ -0,0 +1,198 @@
+/*
+   Copyright 2020 The Compose Specification Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"testing"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/errdefs"
+	"gotest.tools/v3/assert"
+)
+
+func TestSensitiveValidate(t *testing.T) {
+	mode := func(m FileMode) *FileMode { return &m }
+	valid := SensitiveConfig{
+		Target:  "/app/.env",
+		Format:  "env",
+		Secrets: []SensitiveSecret{{Source: "db_password"}, {Source: "api_key"}},
+		Mode:    mode(0o440),
+	}
+	assert.NilError(t, valid.Validate())
+
+	tests := []struct {
+		name      string
+		sensitive func(s SensitiveConfig) SensitiveConfig
+		err       string
+	}{
+		{
+			name:      "unsupported format",
+			sensitive: func(s SensitiveConfig) SensitiveConfig { s.Format = "yaml"; return s },
+			err:       `unsupported format "yaml"`,
+		},
+		{
+			name:      "unsupported selinux",
+			sensitive: func(s SensitiveConfig) SensitiveConfig { s.SELinux = "Z"; return s },
+			err:       `unsupported selinux "Z", must be one of shared, private`,
+		},
+		{
+			name:      "unsupported sort",
+			sensitive: func(s SensitiveConfig) SensitiveConfig { s.Sort = "reverse"; return s },
+			err:       `unsupported sort "reverse"`,
+		},
+		{
+			name:      "raw with multiple secrets",
+			sensitive: func(s SensitiveConfig) SensitiveConfig { s.Format = "raw"; return s },
+			err:       "raw format requires exactly one secret, got 2",
+		},
+		{
+			name:      "template without template",
+			sensitive: func(s SensitiveConfig) SensitiveConfig { s.Format = "template"; return s },
+			err:       "template format requires a template",
+		},
+		{
+			name:      "relative target",
+			sensitive: func(s SensitiveConfig) SensitiveConfig { s.Target = "app/.env"; return s },
+			err:       `target "app/.env" must be an absolute path`,
+		},
+		{
+			name:      "invalid mode",
+			sensitive: func(s SensitiveConfig) SensitiveConfig { s.Mode = mode(0o1777); return s },
+			err:       "mode 01777 is not a valid file permission",
+		},
+		{
+			name: "negative ttl",
+			sensitive: func(s SensitiveConfig) SensitiveConfig {
+				ttl := Duration(-time.Hour)
+				s.TTL = &ttl
+				return s
+			},
+			err: "ttl -1h0m0s must not be negative",
+		},
+		{
+			name: "unsupported on_change action",
+			sensitive: func(s SensitiveConfig) SensitiveConfig {
+				s.OnChange = &SensitiveOnChange{Action: "reload"}
+				return s
+			},
+			err: `unsupported on_change action "reload", must be one of rewrite, restart, signal`,
+		},
+		{
+			name: "on_change signal without signal action",
+			sensitive: func(s SensitiveConfig) SensitiveConfig {
+				s.OnChange = &SensitiveOnChange{Signal: "SIGHUP"}
+				return s
+			},
+			err: "on_change signal requires signal action",
+		},
+		{
+			name: "empty source",
+			sensitive: func(s SensitiveConfig) SensitiveConfig {
+				s.Secrets = []SensitiveSecret{{Source: "db_password"}, {Name: "API_KEY"}}
+				return s
+			},
+			err: "secrets[1]: source must be set",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.sensitive(valid).Validate()
+			assert.ErrorContains(t, err, tt.err)
+			assert.ErrorIs(t, err, errdefs.ErrInvalid)
+		})
+	}
+}
+
+func TestSensitiveValidateWithSecrets(t *testing.T) {
+	s := SensitiveConfig{
+		Target:  "/run/secrets/db",
+		Secrets: []SensitiveSecret{{Source: "db_password"}},
+	}
+	assert.NilError(t, s.ValidateWithSecrets(Secrets{"db_password": {}}))
+	assert.ErrorContains(t, s.ValidateWithSecrets(Secrets{"api_key": {}}), "secrets[0]: refers to undefined secret db_password")
+}
+
+func TestSensitiveSortedSecrets(t *testing.T) {
+	s := SensitiveConfig{
+		Alias: map[string]string{"db_password": "DB_PASS"},
+		Secrets: []SensitiveSecret{
+			{Source: "token", Name: "TOKEN"},
+			{Source: "db_password"},
+			{Source: "api_key", Name: "API_KEY"},
+		},
+	}
+	names := func(secrets []SensitiveSecret) []string {
+		var names []string
+		for _, secret := range secrets {
+			names = append(names, s.SecretName(secret))
+		}
+		return names
+	}
+	assert.DeepEqual(t, names(s.SortedSecrets()), []string{"TOKEN", "DB_PASS", "API_KEY"})
+	s.Sort = SensitiveSortDeclaration
+	assert.DeepEqual(t, names(s.SortedSecrets()), []string{"TOKEN", "DB_PASS", "API_KEY"})
+	s.Sort = SensitiveSortAlpha
+	assert.DeepEqual(t, names(s.SortedSecrets()), []string{"API_KEY", "DB_PASS", "TOKEN"})
+	assert.Equal(t, s.Secrets[0].Source, "token", "declared secrets must not be reordered")
+}
+
+func TestProjectSensitiveSecretNames(t *testing.T) {
+	p := &Project{
+		Services: Services{
+			"web": {
+				Name: "web",
+				Sensitive: map[string]SensitiveConfig{
+					"env": {Secrets: []SensitiveSecret{{Source: "db_password"}, {Source: "api_key"}}},
+				},
+			},
+			"worker": {
+				Name: "worker",
+				Sensitive: map[string]SensitiveConfig{
+					"db": {Secrets: []SensitiveSecret{{Source: "db_password"}, {Source: "undeclared"}}},
+				},
+			},
+			"debug": {
+				Name:     "debug",
+				Profiles: []string{"debug"},
+				Sensitive: map[string]SensitiveConfig{
+					"token": {Secrets: []SensitiveSecret{{Source: "debug_token"}}},
+				},
+			},
+		},
+		Secrets: Secrets{"db_password": {}, "api_key": {}, "debug_token": {}},
+	}
+	assert.DeepEqual(t, p.SensitiveSecretNames(), []string{"api_key", "db_password", "debug_token"})
+
+	p, err := p.WithProfiles(nil)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, p.SensitiveSecretNames(), []string{"api_key", "db_password"})
+
+	assert.DeepEqual(t, (&Project{}).SensitiveSecretNames(), []string{})
+}
+
+func TestServiceRequiredSensitiveSecrets(t *testing.T) {
+	s := ServiceConfig{
+		Name: "web",
+		Sensitive: map[string]SensitiveConfig{
+			"env":  {Secrets: []SensitiveSecret{{Source: "db_password"}, {Source: "api_key"}}},
+			"db":   {Secrets: []SensitiveSecret{{Source: "db_password", Name: "PASSWORD"}}},
+			"none": {},
+		},
+	}
+	assert.DeepEqual(t, s.RequiredSensitiveSecrets(), []string{"api_key", "db_password"})
+	assert.DeepEqual(t, ServiceConfig{}.RequiredSensitiveSecrets(), []string{})
+}
@@ -19,6 +19,7 @@ package types
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"sort"
 	"strconv"
 	"strings"
@@ -85,11 +86,13 @@ type ServiceConfig struct {
 	Extends         *ExtendsConfig                   `yaml:"extends,omitempty" json:"extends,omitempty"`
 	ExternalLinks   []string                         `yaml:"external_links,omitempty" json:"external_links,omitempty"`
 	ExtraHosts      HostsList                        `yaml:"extra_hosts,omitempty" json:"extra_hosts,omitempty"`
+	FileDefaults    *FileDefaults                    `yaml:"file_defaults,omitempty" json:"file_defaults,omitempty"`
 	GroupAdd        []string                         `yaml:"group_add,omitempty" json:"group_add,omitempty"`
 	Gpus            []DeviceRequest                  `yaml:"gpus,omitempty" json:"gpus,omitempty"`
 	Hostname        string                           `yaml:"hostname,omitempty" json:"hostname,omitempty"`
 	HealthCheck     *HealthCheckConfig               `yaml:"healthcheck,omitempty" json:"healthcheck,omitempty"`
 	Image           string                           `yaml:"image,omitempty" json:"image,omitempty"`
+	InheritPrebuild string                           `yaml:"inherit_prebuild,omitempty" json:"inherit_prebuild,omitempty"`
 	Init            *bool                            `yaml:"init,omitempty" json:"init,omitempty"`
 	Ipc             string                           `yaml:"ipc,omitempty" json:"ipc,omitempty"`
 	Isolation       string                           `yaml:"isolation,omitempty" json:"isolation,omitempty"`
@@ -209,6 +212,11 @@ func (s *ServiceConfig) SetScale(scale int) {
 	}
 }
 
+// Clone returns a deep copy of service, sharing no state with it
+func (s ServiceConfig) Clone() ServiceConfig {
+	return *s.deepCopy()
+}
+
 func (s *ServiceConfig) deepCopy() *ServiceConfig {
 	if s == nil {
 		return nil
@@ -673,16 +681,65 @@ func (f *FileMode) String() string {
 	return fmt.Sprintf("0%o", int64(*f))
 }
 
+// DefaultCICDFileMode is the mode of local_configs files not setting one
+const DefaultCICDFileMode os.FileMode = 0o444
+
+// DefaultSensitiveFileMode is the mode of sensitive files not setting one, only readable by owner, see
+// SensitiveConfig.FileMode
+const DefaultSensitiveFileMode os.FileMode = 0o400
+
+// OSFileMode returns mode as an os.FileMode, defaulting to DefaultCICDFileMode when not set
+func (f *FileMode) OSFileMode() os.FileMode {
+	if f == nil {
+		return DefaultCICDFileMode
+	}
+	return os.FileMode(*f) & os.ModePerm
+}
+
+// IsPermission tells if mode is within the 0-0777 file permission range
+func (f FileMode) IsPermission() bool {
+	return f >= 0 && f <= 0o777
+}
+
 // ServiceConfigObjConfig is the config obj configuration for a service
 type ServiceConfigObjConfig FileReferenceConfig
 
 // ServiceSecretConfig is the secret configuration for a service
 type ServiceSecretConfig FileReferenceConfig
 
+// LocalConfigTemplateEngineGoTemplate renders local config Content as a Go text/template, with project
+// environment as data
+const LocalConfigTemplateEngineGoTemplate = "gotemplate"
+
 // LocalConfigConfig is the configuration for a local file config managed by cicdez
 type LocalConfigConfig struct {
-	Source     string     `yaml:"source,omitempty" json:"source,omitempty"`
-	Target     string     `yaml:"target,omitempty" json:"target,omitempty"`
+	Source string `yaml:"source,omitempty" json:"source,omitempty"`
+	// ResolvedSource is Source made absolute against project working directory, see loader Options.ResolvePaths
+	ResolvedSource string `yaml:"-" json:"-"`
+	// Content is the inline content of the config, as an alternative to Source
+	Content string `yaml:"content,omitempty" json:"content,omitempty"`
+	// MaxSize is the maximum size of the config, protecting from mounting an accidentally huge file
+	MaxSize *UnitBytes `yaml:"max_size,omitempty" json:"max_size,omitempty"`
+	// Interpolate opts in for Content to be interpolated with project environment, otherwise used literally
+	Interpolate bool `yaml:"interpolate,omitempty" json:"interpolate,omitempty"`
+	// ResolvedUID and ResolvedGID are the numeric ids UID and GID resolve to, see loader Options.ResolveUserNames
+	ResolvedUID int `yaml:"-" json:"-"`
+	ResolvedGID int `yaml:"-" json:"-"`
+	// SELinux relabels the mounted file, see SELinuxRelabelShared
+	SELinux string `yaml:"selinux,omitempty" json:"selinux,omitempty"`
+	// TemplateEngine selects how Content is rendered, see LocalConfigTemplateEngineGoTemplate
+	TemplateEngine string     `yaml:"template_engine,omitempty" json:"template_engine,omitempty"`
+	Target         string     `yaml:"target,omitempty" json:"target,omitempty"`
+	UID            string     `yaml:"uid,omitempty" json:"uid,omitempty"`
+	GID            string     `yaml:"gid,omitempty" json:"gid,omitempty"`
+	Mode           *FileMode  `yaml:"mode,omitempty" json:"mode,omitempty"`
+	Extensions     Extensions `yaml:"#extensions,inline,omitempty" json:"-"`
+}
+
+// FileDefaults are the uid, gid and mode of local_configs and sensitive entries not setting those, see
+// Project.FileDefaults. Service level defaults override project level ones, before falling back to root ownership and
+// DefaultCICDFileMode or DefaultSensitiveFileMode
+type FileDefaults struct {
 	UID        string     `yaml:"uid,omitempty" json:"uid,omitempty"`
 	GID        string     `yaml:"gid,omitempty" json:"gid,omitempty"`
 	Mode       *FileMode  `yaml:"mode,omitempty" json:"mode,omitempty"`
@@ -886,36 +943,198 @@ func (s ConfigObjConfig) MarshalJSON() ([]byte, error) {
 
 // PrebuildCommand represents a single command in a prebuild job
 type PrebuildCommand struct {
-	Name       string     `yaml:"name,omitempty" json:"name,omitempty"`
-	Command    string     `yaml:"command,omitempty" json:"command,omitempty"`
+	Name    string `yaml:"name,omitempty" json:"name,omitempty"`
+	Command string `yaml:"command,omitempty" json:"command,omitempty"`
+	// Group labels a log section commands are collapsed in, see PrebuildJob.CommandsByGroup
+	Group string `yaml:"group,omitempty" json:"group,omitempty"`
+	// OriginalCommand is the command as declared, before loader's PrebuildCommandWrapper applied
+	OriginalCommand string `yaml:"-" json:"-"`
+	// RawCommand is the command as declared, before interpolation, when it differs from Command
+	RawCommand string `yaml:"-" json:"-"`
+	// WasInterpolated tells if RawCommand contained variables which got interpolated
+	WasInterpolated bool `yaml:"-" json:"-"`
+	// Location is where loader found command, as set by normalization, see PrebuildCommand.ID
+	Location PrebuildCommandLocation `yaml:"-" json:"-"`
+	// Register captures the command outcome so later commands can reference it as `$${result.<register>.rc}`
+	// or `$${result.<register>.stdout}` in compose files, `$` being escaped from interpolation
+	Register string `yaml:"register,omitempty" json:"register,omitempty"`
+	// If is a condition for command to run, declared like `$${result.build.rc} == 0`, see ParsePrebuildCondition
+	If string `yaml:"if,omitempty" json:"if,omitempty"`
+	// SkipReason tells why loader skipped command, when If only compared literals and evaluated false. Command is
+	// kept for tooling to report it, runners must not run it
+	SkipReason  string            `yaml:"-" json:"-"`
+	Environment MappingWithEquals `yaml:"environment,omitempty" json:"environment,omitempty"`
+	// EnvFiles set command environment, layered below Environment and above job environment
+	EnvFiles []EnvFile `yaml:"env_file,omitempty" json:"env_file,omitempty"`
+	// Platforms restricts command to runners of the given `os/arch[/variant]` platforms, see RunsOnPlatform
+	Platforms []string `yaml:"platforms,omitempty" json:"platforms,omitempty"`
+	// WorkingDir is the directory command runs in. Relative paths are not resolved, runner decides the base
+	WorkingDir string `yaml:"working_dir,omitempty" json:"working_dir,omitempty"`
+	// Shell is the program command is passed to as last argument, see PrebuildCommand.ShellArgs
+	Shell ShellCommand `yaml:"shell,omitempty" json:"shell,omitempty"`
+	// EstimatedDuration is a hint on command duration, for timeline visualization
+	EstimatedDuration *Duration `yaml:"estimated_duration,omitempty" json:"estimated_duration,omitempty"`
+	// Timeout is the maximum duration of the command
+	Timeout *Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	// ContinueOnError lets job go on when command fails, for advisory commands
+	ContinueOnError bool `yaml:"continue_on_error,omitempty" json:"continue_on_error,omitempty"`
+	// Retries is the number of times command is retried on failure
+	Retries      *int                  `yaml:"retries,omitempty" json:"retries,omitempty"`
+	RetryBackoff *PrebuildRetryBackoff `yaml:"retry_backoff,omitempty" json:"retry_backoff,omitempty"`
+	// AllowedPaths are the paths command may read or write, for sandboxed runners to enforce
+	AllowedPaths []string   `yaml:"allowed_paths,omitempty" json:"allowed_paths,omitempty"`
+	Extensions   Extensions `yaml:"#extensions,inline,omitempty" json:"-"`
+}
+
+// PrebuildRetryBackoff is the delay policy between prebuild command retries
+type PrebuildRetryBackoff struct {
+	Initial Duration `yaml:"initial,omitempty" json:"initial,omitempty"`
+	// Factor multiplies delay after each retry, zero meaning a constant delay
+	Factor     float64    `yaml:"factor,omitempty" json:"factor,omitempty"`
+	Max        *Duration  `yaml:"max,omitempty" json:"max,omitempty"`
 	Extensions Extensions `yaml:"#extensions,inline,omitempty" json:"-"`
 }
 
 // PrebuildJob represents a job that runs before building the Docker image
 type PrebuildJob struct {
-	Name       string            `yaml:"name,omitempty" json:"name,omitempty"`
-	RunsOn     string            `yaml:"runs-on,omitempty" json:"runs-on,omitempty"`
-	Commands   []PrebuildCommand `yaml:"commands,omitempty" json:"commands,omitempty"`
-	Extensions Extensions        `yaml:"#extensions,inline,omitempty" json:"-"`
+	Name   string `yaml:"name,omitempty" json:"name,omitempty"`
+	RunsOn string `yaml:"runs-on,omitempty" json:"runs-on,omitempty"`
+	// ImagePullPolicy sets when runner image is pulled, see PrebuildJob.PullPolicy
+	ImagePullPolicy string `yaml:"image_pull_policy,omitempty" json:"image_pull_policy,omitempty"`
+	// Matrix expands job into a job per combination of values, see PrebuildJob.ExpandMatrix
+	Matrix map[string][]string `yaml:"matrix,omitempty" json:"matrix,omitempty"`
+	// Needs lists jobs from the same service which must complete before this one, see PrebuildNeed.Status
+	Needs []PrebuildNeed `yaml:"needs,omitempty" json:"needs,omitempty"`
+	// Stage groups jobs for CI visualization, see Project.Stages
+	Stage string `yaml:"stage,omitempty" json:"stage,omitempty"`
+	// Profiles restricts job to the given profiles, as for services
+	Profiles []string `yaml:"profiles,omitempty" json:"profiles,omitempty"`
+	// Skip disables job
+	Skip bool `yaml:"skip,omitempty" json:"skip,omitempty"`
+	// If is a condition for job to run, evaluated by loader against project environment, see ParsePrebuildCondition
+	If string `yaml:"if,omitempty" json:"if,omitempty"`
+	// SkipReason tells why loader skipped job, as for a false If condition. Skipped jobs are kept in
+	// Project.DisabledPrebuildJobs for tooling to report them
+	SkipReason string `yaml:"-" json:"-"`
+	// WhenChanged lists glob patterns of files which changes make job relevant, see PrebuildJob.MatchesChanges
+	WhenChanged []string `yaml:"when_changed,omitempty" json:"when_changed,omitempty"`
+	// RequiresEnv lists environment variables job requires, see Project.PrebuildRequiredSecrets
+	RequiresEnv []string `yaml:"requires_env,omitempty" json:"requires_env,omitempty"`
+	// Environment is set for all commands of the job, see PrebuildJob.CommandEnvironment
+	Environment MappingWithEquals `yaml:"environment,omitempty" json:"environment,omitempty"`
+	// Timeout is the maximum duration of the job
+	Timeout *Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	// Concurrency restricts job to a single run at a time within a group
+	Concurrency *PrebuildConcurrency `yaml:"concurrency,omitempty" json:"concurrency,omitempty"`
+	// Services lists sibling services which must be running for the job, see Project.PrebuildJobProject
+	Services []string `yaml:"services,omitempty" json:"services,omitempty"`
+	// Cache declares paths runners restore and save between runs of the job, see PrebuildJob.CacheChecksum
+	Cache *PrebuildCache `yaml:"cache,omitempty" json:"cache,omitempty"`
+	// Container configures the container job runs in
+	Container  *PrebuildContainer `yaml:"container,omitempty" json:"container,omitempty"`
+	Commands   []PrebuildCommand  `yaml:"commands,omitempty" json:"commands,omitempty"`
+	Extensions Extensions         `yaml:"#extensions,inline,omitempty" json:"-"`
+}
+
+// PrebuildNeed is a dependency of a prebuild job on another job of the same service
+type PrebuildNeed struct {
+	Job string `yaml:"job,omitempty" json:"job,omitempty"`
+	// Status is the outcome of Job required for the dependent job to run, see PrebuildNeedSuccess
+	Status     string     `yaml:"status,omitempty" json:"status,omitempty"`
+	Extensions Extensions `yaml:"#extensions,inline,omitempty" json:"-"`
+}
+
+// PrebuildConcurrency is a group of prebuild jobs which can't run concurrently
+type PrebuildConcurrency struct {
+	Group string `yaml:"group,omitempty" json:"group,omitempty"`
+	// CancelInProgress tells runner to cancel the job in progress in the group when a new one starts
+	CancelInProgress bool       `yaml:"cancel_in_progress,omitempty" json:"cancel_in_progress,omitempty"`
+	Extensions       Extensions `yaml:"#extensions,inline,omitempty" json:"-"`
+}
+
+// PrebuildCache is a dependency cache of a prebuild job, as a key identifying cache content and the paths it holds
+type PrebuildCache struct {
+	Key string `yaml:"key,omitempty" json:"key,omitempty"`
+	// Paths are paths of the job container, `~` standing for the home directory of the job user
+	Paths      []string   `yaml:"paths,omitempty" json:"paths,omitempty"`
+	Extensions Extensions `yaml:"#extensions,inline,omitempty" json:"-"`
+}
+
+// PrebuildContainer is the runtime configuration of the container a prebuild job runs in
+type PrebuildContainer struct {
+	// Image is an alias for the job runs-on attribute
+	Image       string                `yaml:"image,omitempty" json:"image,omitempty"`
+	Volumes     []ServiceVolumeConfig `yaml:"volumes,omitempty" json:"volumes,omitempty"`
+	Environment MappingWithEquals     `yaml:"environment,omitempty" json:"environment,omitempty"`
+	WorkingDir  string                `yaml:"working_dir,omitempty" json:"working_dir,omitempty"`
+	User        string                `yaml:"user,omitempty" json:"user,omitempty"`
+	// Networks are top-level networks job container joins
+	Networks   []string   `yaml:"networks,omitempty" json:"networks,omitempty"`
+	Extensions Extensions `yaml:"#extensions,inline,omitempty" json:"-"`
 }
 
 // SensitiveSecret represents a secret reference in a sensitive config
 type SensitiveSecret struct {
-	Source     string     `yaml:"source,omitempty" json:"source,omitempty"`
-	Name       string     `yaml:"name,omitempty" json:"name,omitempty"`
+	Source string `yaml:"source,omitempty" json:"source,omitempty"`
+	Name   string `yaml:"name,omitempty" json:"name,omitempty"`
+	// Validate are constraints resolved secret value must satisfy to be rendered
+	Validate *SensitiveSecretValidation `yaml:"validate,omitempty" json:"validate,omitempty"`
+	// Provider is a hint on the external secret manager backing the secret, like `vault`, for resolvers to fetch
+	// it from ProviderPath
+	Provider     string `yaml:"provider,omitempty" json:"provider,omitempty"`
+	ProviderPath string `yaml:"provider_path,omitempty" json:"provider_path,omitempty"`
+	// Environment and File define the secret inline, as a top-level secret would, the loader declaring it
+	Environment string `yaml:"environment,omitempty" json:"environment,omitempty"`
+	File        string `yaml:"file,omitempty" json:"file,omitempty"`
+	// Default is a placeholder value for local development, only used when rendering allows it and secret is not found
+	Default    *string    `yaml:"default,omitempty" json:"default,omitempty"`
+	Extensions Extensions `yaml:"#extensions,inline,omitempty" json:"-"`
+}
+
+// SensitiveSecretValidation are constraints on a secret value
+type SensitiveSecretValidation struct {
+	MinLength  int        `yaml:"min_length,omitempty" json:"min_length,omitempty"`
+	Pattern    string     `yaml:"pattern,omitempty" json:"pattern,omitempty"`
 	Extensions Extensions `yaml:"#extensions,inline,omitempty" json:"-"`
 }
 
 // SensitiveConfig manages how secrets are injected into containers
 type SensitiveConfig struct {
-	Target     string            `yaml:"target,omitempty" json:"target,omitempty"`
-	Format     string            `yaml:"format,omitempty" json:"format,omitempty"`
-	Secrets    []SensitiveSecret `yaml:"secrets,omitempty" json:"secrets,omitempty"`
-	Template   string            `yaml:"template,omitempty" json:"template,omitempty"`
-	UID        string            `yaml:"uid,omitempty" json:"uid,omitempty"`
-	GID        string            `yaml:"gid,omitempty" json:"gid,omitempty"`
-	Mode       *FileMode         `yaml:"mode,omitempty" json:"mode,omitempty"`
-	Extensions Extensions        `yaml:"#extensions,inline,omitempty" json:"-"`
+	Target  string            `yaml:"target,omitempty" json:"target,omitempty"`
+	Format  SensitiveFormat   `yaml:"format,omitempty" json:"format,omitempty"`
+	Secrets []SensitiveSecret `yaml:"secrets,omitempty" json:"secrets,omitempty"`
+	// FromLabel selects top-level secrets by label, as `key=value` or `key`, to be included with default naming
+	FromLabel string `yaml:"from_label,omitempty" json:"from_label,omitempty"`
+	// Alias renames secrets by source, unless an explicit name is set on the secret
+	Alias    map[string]string `yaml:"alias,omitempty" json:"alias,omitempty"`
+	Template string            `yaml:"template,omitempty" json:"template,omitempty"`
+	// TemplateContent is an inline template, alternative to a Template file, see SensitiveConfig.ParseTemplate
+	TemplateContent string    `yaml:"template_content,omitempty" json:"template_content,omitempty"`
+	UID             string    `yaml:"uid,omitempty" json:"uid,omitempty"`
+	GID             string    `yaml:"gid,omitempty" json:"gid,omitempty"`
+	Mode            *FileMode `yaml:"mode,omitempty" json:"mode,omitempty"`
+	// ResolvedUID and ResolvedGID are the numeric ids UID and GID resolve to, see loader Options.ResolveUserNames
+	ResolvedUID int `yaml:"-" json:"-"`
+	ResolvedGID int `yaml:"-" json:"-"`
+	// SELinux relabels the mounted file, see SELinuxRelabelShared
+	SELinux string `yaml:"selinux,omitempty" json:"selinux,omitempty"`
+	// Sort sets the order of secrets in rendered output, see SensitiveSortDeclaration and SensitiveSortAlpha
+	Sort string `yaml:"sort,omitempty" json:"sort,omitempty"`
+	// TTL is how often the rendered file should be refreshed, zero meaning no automatic rotation
+	TTL *Duration `yaml:"ttl,omitempty" json:"ttl,omitempty"`
+	// TrailingNewline sets if rendered output ends with a newline, see SensitiveConfig.HasTrailingNewline
+	TrailingNewline *bool `yaml:"trailing_newline,omitempty" json:"trailing_newline,omitempty"`
+	// OnChange tells how service reacts to the rendered file changing, see SensitiveConfig.ChangeAction
+	OnChange   *SensitiveOnChange `yaml:"on_change,omitempty" json:"on_change,omitempty"`
+	Extensions Extensions         `yaml:"#extensions,inline,omitempty" json:"-"`
+}
+
+// SensitiveOnChange is the action tooling takes when a sensitive rendered file changes, like a secret rotation
+type SensitiveOnChange struct {
+	Action SensitiveChangeAction `yaml:"action,omitempty" json:"action,omitempty"`
+	// Signal is sent to service containers by SensitiveChangeSignal action, defaulting to SIGHUP
+	Signal     string     `yaml:"signal,omitempty" json:"signal,omitempty"`
+	Extensions Extensions `yaml:"#extensions,inline,omitempty" json:"-"`
 }
 
 type IncludeConfig struct {